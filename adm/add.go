@@ -1,12 +1,18 @@
 package main
 
 import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
 	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/mgutz/ansi"
 	"gopkg.in/ini.v1"
-	"runtime"
-	"strings"
 )
 
 func addUser(cfgFile string) {
@@ -17,6 +23,8 @@ func addUser(cfgFile string) {
 	var (
 		driverName, host, port, dbFile, user, password,
 		database, schema, name, nickname, userPassword string
+
+		policy config.PasswordPolicy
 	)
 
 	if cfgFile != "" {
@@ -51,6 +59,15 @@ func addUser(cfgFile string) {
 			password = dbCfgModel.Key("password").Value()
 			database = dbCfgModel.Key("database").Value()
 		}
+
+		// 與後台相同的密碼規則
+		policyCfg, err := cfgModel.GetSection("password_policy")
+
+		if err == nil {
+			if err := policyCfg.MapTo(&policy); err != nil {
+				panic(err)
+			}
+		}
 	}
 
 	conn := askForDBConnection(&dbInfo{
@@ -76,6 +93,10 @@ func addUser(cfgFile string) {
 		userPassword = promptWithDefault("user password", "")
 	}
 
+	if err := auth.CheckPasswordStrength(policy, userPassword); err != nil {
+		panic(errors.New(err.(auth.PasswordPolicyError).Localize(getWord)))
+	}
+
 	checkExist, err := db.WithDriver(conn).Table("goadmin_users").
 		Where("name", "=", name).
 		First()
@@ -88,17 +109,31 @@ func addUser(cfgFile string) {
 		panic(newError("user record exists"))
 	}
 
-	_, err = db.WithDriver(conn).Table("goadmin_users").
+	hash := auth.EncodePassword([]byte(userPassword))
+
+	id, err := db.WithDriver(conn).Table("goadmin_users").
 		Insert(dialect.H{
 			"name":     name,
 			"username": nickname,
-			"password": auth.EncodePassword([]byte(userPassword)),
+			"password": hash,
 		})
 
 	if db.CheckError(err, db.INSERT) {
 		panic(err)
 	}
 
+	// 記錄密碼修改時間，用於判斷密碼過期(舊版本資料庫沒有此表格時略過)
+	_, err = db.WithDriver(conn).Table("goadmin_password_history").
+		Insert(dialect.H{
+			"user_id":    id,
+			"password":   hash,
+			"changed_at": time.Now().Unix(),
+		})
+
+	if db.CheckError(err, db.INSERT) {
+		fmt.Println(ansi.Color(getWord("save password history error")+": "+err.Error(), "yellow"))
+	}
+
 	printSuccessInfo("Add admin user success~~🍺🍺")
 }
 
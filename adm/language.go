@@ -20,6 +20,14 @@ var langs = map[string]map[string]string{
 		"user record exists":     "用户记录已存在",
 		"empty tables":           "表格不能为空",
 
+		"password must be at least %d characters":               "密码长度至少为%d个字符",
+		"password must contain an uppercase letter":             "密码必须包含大写字母",
+		"password must contain a lowercase letter":              "密码必须包含小写字母",
+		"password must contain a digit":                         "密码必须包含数字",
+		"password must contain a symbol":                        "密码必须包含符号",
+		"password can not be the same as the last %d passwords": "密码不能与最近%d次的密码相同",
+		"save password history error":                           "保存密码记录失败",
+
 		"tables to generate, use comma to split": "要生成权限的表格，用逗号分隔",
 
 		"no tables, you should build a table of your own business first.": "表格不能为空，请先创建您的业务表",
@@ -64,6 +64,7 @@ DROP TABLE IF EXISTS `goadmin_operation_log`;
 CREATE TABLE `goadmin_operation_log` (
   `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
   `user_id` int(11) unsigned NOT NULL,
+  `impersonator_id` int(11) unsigned NOT NULL DEFAULT '0',
   `path` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL,
   `method` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL,
   `ip` varchar(15) COLLATE utf8mb4_unicode_ci NOT NULL,
@@ -236,9 +237,13 @@ CREATE TABLE `goadmin_session` (
   `id` int(11) unsigned NOT NULL AUTO_INCREMENT,
   `sid` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
   `values` varchar(3000) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `user_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `ip` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `user_agent` varchar(500) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
-  PRIMARY KEY (`id`)
+  PRIMARY KEY (`id`),
+  KEY `goadmin_session_user_id_index` (`user_id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
 
@@ -299,6 +304,212 @@ UNLOCK TABLES;
 
 
 
+# Dump of table goadmin_user_totp
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_user_totp`;
+
+CREATE TABLE `goadmin_user_totp` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL,
+  `secret` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `enabled` tinyint(3) unsigned NOT NULL DEFAULT '0',
+  `recovery_codes` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `goadmin_user_totp_user_id_unique` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_login_lockout
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_login_lockout`;
+
+CREATE TABLE `goadmin_login_lockout` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `target_type` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `target` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `failures` int(11) unsigned NOT NULL DEFAULT '0',
+  `lock_times` int(11) unsigned NOT NULL DEFAULT '0',
+  `last_failure_at` bigint(20) NOT NULL DEFAULT '0',
+  `next_attempt_at` bigint(20) NOT NULL DEFAULT '0',
+  `locked_until` bigint(20) NOT NULL DEFAULT '0',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `goadmin_login_lockout_target_unique` (`target_type`,`target`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_password_history
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_password_history`;
+
+CREATE TABLE `goadmin_password_history` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL,
+  `password` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `changed_at` bigint(20) NOT NULL DEFAULT '0',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `goadmin_password_history_user_id_index` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_row_scopes
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_row_scopes`;
+
+CREATE TABLE `goadmin_row_scopes` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `role_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `permission_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `prefix` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `field` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `operator` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '=',
+  `val` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `goadmin_row_scopes_prefix_index` (`prefix`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_audit_log
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_audit_log`;
+
+CREATE TABLE `goadmin_audit_log` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `target_table` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `record_id` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `operation` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `old_values` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `new_values` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `diff` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `ip` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `goadmin_audit_log_record_index` (`target_table`,`record_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_role_parents
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_role_parents`;
+
+CREATE TABLE `goadmin_role_parents` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `role_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `parent_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `goadmin_role_parents_role_id_parent_id_index` (`role_id`,`parent_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_api_keys
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_api_keys`;
+
+CREATE TABLE `goadmin_api_keys` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `user_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `key_prefix` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `key_hash` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `permissions` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `expires_at` timestamp NULL DEFAULT NULL,
+  `last_used_at` timestamp NULL DEFAULT NULL,
+  `last_used_ip` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `goadmin_api_keys_key_prefix_index` (`key_prefix`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_change_requests
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_change_requests`;
+
+CREATE TABLE `goadmin_change_requests` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `prefix` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `target_table` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `record_id` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `operation` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `permission` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `old_values` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `new_values` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `user_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `status` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'pending',
+  `reviewer_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `comment` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `reviewed_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `goadmin_change_requests_status_index` (`status`,`permission`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_user_identities
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_user_identities`;
+
+CREATE TABLE `goadmin_user_identities` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL,
+  `provider` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `subject` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `goadmin_user_identities_subject_unique` (`provider`,`subject`),
+  KEY `goadmin_user_identities_user_id_index` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
+# Dump of table goadmin_user_token_versions
+# ------------------------------------------------------------
+
+DROP TABLE IF EXISTS `goadmin_user_token_versions`;
+
+CREATE TABLE `goadmin_user_token_versions` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL,
+  `version` int(11) unsigned NOT NULL DEFAULT '0',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `goadmin_user_token_versions_user_id_unique` (`user_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+
+
 /*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
 /*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
 /*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
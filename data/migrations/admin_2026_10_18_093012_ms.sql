@@ -0,0 +1,11 @@
+CREATE TABLE[goadmin_user_totp] (
+ [id] int   identity(1,1) ,
+ [user_id] int   NOT NULL,
+ [secret] varchar(100)   NOT NULL DEFAULT '',
+ [enabled] tinyint   NOT NULL DEFAULT 0,
+ [recovery_codes] text   NULL,
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE UNIQUE INDEX [goadmin_user_totp_user_id_unique] ON [goadmin_user_totp] ([user_id])
@@ -0,0 +1,21 @@
+CREATE SEQUENCE public.goadmin_user_totp_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_user_totp (
+    id integer DEFAULT nextval('public.goadmin_user_totp_myid_seq'::regclass) NOT NULL,
+    user_id integer NOT NULL,
+    secret character varying(100) DEFAULT ''::character varying NOT NULL,
+    enabled integer DEFAULT 0 NOT NULL,
+    recovery_codes text,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_user_totp
+    ADD CONSTRAINT goadmin_user_totp_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX goadmin_user_totp_user_id_unique ON public.goadmin_user_totp USING btree (user_id);
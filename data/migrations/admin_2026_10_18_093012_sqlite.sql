@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "goadmin_user_totp" (
+`id` integer PRIMARY KEY autoincrement,
+`user_id` INT NOT NULL,
+`secret` CHAR(100) NOT NULL DEFAULT '',
+`enabled` INT NOT NULL DEFAULT '0',
+`recovery_codes` TEXT DEFAULT NULL,
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "goadmin_user_totp_user_id_unique" ON "goadmin_user_totp" (`user_id`);
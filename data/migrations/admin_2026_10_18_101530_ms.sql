@@ -0,0 +1,14 @@
+CREATE TABLE[goadmin_login_lockout] (
+ [id] int   identity(1,1) ,
+ [target_type] varchar(20)   NOT NULL DEFAULT '',
+ [target] varchar(255)   NOT NULL DEFAULT '',
+ [failures] int   NOT NULL DEFAULT 0,
+ [lock_times] int   NOT NULL DEFAULT 0,
+ [last_failure_at] bigint   NOT NULL DEFAULT 0,
+ [next_attempt_at] bigint   NOT NULL DEFAULT 0,
+ [locked_until] bigint   NOT NULL DEFAULT 0,
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE UNIQUE INDEX [goadmin_login_lockout_target_unique] ON [goadmin_login_lockout] ([target_type], [target])
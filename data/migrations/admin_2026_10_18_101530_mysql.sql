@@ -0,0 +1,14 @@
+CREATE TABLE `goadmin_login_lockout` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `target_type` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `target` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `failures` int(11) unsigned NOT NULL DEFAULT '0',
+  `lock_times` int(11) unsigned NOT NULL DEFAULT '0',
+  `last_failure_at` bigint(20) NOT NULL DEFAULT '0',
+  `next_attempt_at` bigint(20) NOT NULL DEFAULT '0',
+  `locked_until` bigint(20) NOT NULL DEFAULT '0',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `goadmin_login_lockout_target_unique` (`target_type`,`target`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
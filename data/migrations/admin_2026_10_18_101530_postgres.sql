@@ -0,0 +1,24 @@
+CREATE SEQUENCE public.goadmin_login_lockout_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_login_lockout (
+    id integer DEFAULT nextval('public.goadmin_login_lockout_myid_seq'::regclass) NOT NULL,
+    target_type character varying(20) DEFAULT ''::character varying NOT NULL,
+    target character varying(255) DEFAULT ''::character varying NOT NULL,
+    failures integer DEFAULT 0 NOT NULL,
+    lock_times integer DEFAULT 0 NOT NULL,
+    last_failure_at bigint DEFAULT 0 NOT NULL,
+    next_attempt_at bigint DEFAULT 0 NOT NULL,
+    locked_until bigint DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_login_lockout
+    ADD CONSTRAINT goadmin_login_lockout_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX goadmin_login_lockout_target_unique ON public.goadmin_login_lockout USING btree (target_type, target);
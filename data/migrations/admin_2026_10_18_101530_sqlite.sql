@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS "goadmin_login_lockout" (
+`id` integer PRIMARY KEY autoincrement,
+`target_type` CHAR(20) NOT NULL DEFAULT '',
+`target` CHAR(255) NOT NULL DEFAULT '',
+`failures` INT NOT NULL DEFAULT '0',
+`lock_times` INT NOT NULL DEFAULT '0',
+`last_failure_at` INT NOT NULL DEFAULT '0',
+`next_attempt_at` INT NOT NULL DEFAULT '0',
+`locked_until` INT NOT NULL DEFAULT '0',
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "goadmin_login_lockout_target_unique" ON "goadmin_login_lockout" (`target_type`, `target`);
@@ -0,0 +1,10 @@
+CREATE TABLE[goadmin_password_history] (
+ [id] int   identity(1,1) ,
+ [user_id] int   NOT NULL,
+ [password] varchar(100)   NOT NULL DEFAULT '',
+ [changed_at] bigint   NOT NULL DEFAULT 0,
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE INDEX [goadmin_password_history_user_id_index] ON [goadmin_password_history] ([user_id])
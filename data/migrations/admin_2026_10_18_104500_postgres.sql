@@ -0,0 +1,20 @@
+CREATE SEQUENCE public.goadmin_password_history_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_password_history (
+    id integer DEFAULT nextval('public.goadmin_password_history_myid_seq'::regclass) NOT NULL,
+    user_id integer NOT NULL,
+    password character varying(100) DEFAULT ''::character varying NOT NULL,
+    changed_at bigint DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_password_history
+    ADD CONSTRAINT goadmin_password_history_pkey PRIMARY KEY (id);
+
+CREATE INDEX goadmin_password_history_user_id_index ON public.goadmin_password_history USING btree (user_id);
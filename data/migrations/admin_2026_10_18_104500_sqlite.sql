@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS "goadmin_password_history" (
+`id` integer PRIMARY KEY autoincrement,
+`user_id` INT NOT NULL,
+`password` CHAR(100) NOT NULL DEFAULT '',
+`changed_at` INT NOT NULL DEFAULT '0',
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "goadmin_password_history_user_id_index" ON "goadmin_password_history" (`user_id`);
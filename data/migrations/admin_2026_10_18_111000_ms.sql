@@ -0,0 +1,13 @@
+CREATE TABLE[goadmin_row_scopes] (
+ [id] int   identity(1,1) ,
+ [role_id] int   NOT NULL DEFAULT 0,
+ [permission_id] int   NOT NULL DEFAULT 0,
+ [prefix] varchar(100)   NOT NULL DEFAULT '',
+ [field] varchar(100)   NOT NULL DEFAULT '',
+ [operator] varchar(10)   NOT NULL DEFAULT '=',
+ [val] varchar(255)   NOT NULL DEFAULT '',
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE INDEX [goadmin_row_scopes_prefix_index] ON [goadmin_row_scopes] ([prefix])
@@ -0,0 +1,13 @@
+CREATE TABLE `goadmin_row_scopes` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `role_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `permission_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `prefix` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `field` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `operator` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '=',
+  `val` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `goadmin_row_scopes_prefix_index` (`prefix`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
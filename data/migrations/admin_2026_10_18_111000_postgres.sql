@@ -0,0 +1,23 @@
+CREATE SEQUENCE public.goadmin_row_scopes_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_row_scopes (
+    id integer DEFAULT nextval('public.goadmin_row_scopes_myid_seq'::regclass) NOT NULL,
+    role_id integer DEFAULT 0 NOT NULL,
+    permission_id integer DEFAULT 0 NOT NULL,
+    prefix character varying(100) DEFAULT ''::character varying NOT NULL,
+    field character varying(100) DEFAULT ''::character varying NOT NULL,
+    operator character varying(10) DEFAULT '='::character varying NOT NULL,
+    val character varying(255) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_row_scopes
+    ADD CONSTRAINT goadmin_row_scopes_pkey PRIMARY KEY (id);
+
+CREATE INDEX goadmin_row_scopes_prefix_index ON public.goadmin_row_scopes USING btree (prefix);
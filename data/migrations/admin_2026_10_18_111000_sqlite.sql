@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "goadmin_row_scopes" (
+`id` integer PRIMARY KEY autoincrement,
+`role_id` INT NOT NULL DEFAULT '0',
+`permission_id` INT NOT NULL DEFAULT '0',
+`prefix` CHAR(100) NOT NULL DEFAULT '',
+`field` CHAR(100) NOT NULL DEFAULT '',
+`operator` CHAR(10) NOT NULL DEFAULT '=',
+`val` CHAR(255) NOT NULL DEFAULT '',
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "goadmin_row_scopes_prefix_index" ON "goadmin_row_scopes" (`prefix`);
@@ -0,0 +1,15 @@
+CREATE TABLE[goadmin_audit_log] (
+ [id] int   identity(1,1) ,
+ [user_id] int   NOT NULL DEFAULT 0,
+ [target_table] varchar(100)   NOT NULL DEFAULT '',
+ [record_id] varchar(100)   NOT NULL DEFAULT '',
+ [operation] varchar(10)   NOT NULL DEFAULT '',
+ [old_values] text   NULL,
+ [new_values] text   NULL,
+ [diff] text   NULL,
+ [ip] varchar(50)   NOT NULL DEFAULT '',
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE INDEX [goadmin_audit_log_record_index] ON [goadmin_audit_log] ([target_table], [record_id])
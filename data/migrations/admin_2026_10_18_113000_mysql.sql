@@ -0,0 +1,15 @@
+CREATE TABLE `goadmin_audit_log` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `user_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `target_table` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `record_id` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `operation` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `old_values` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `new_values` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `diff` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `ip` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `goadmin_audit_log_record_index` (`target_table`,`record_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,25 @@
+CREATE SEQUENCE public.goadmin_audit_log_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_audit_log (
+    id integer DEFAULT nextval('public.goadmin_audit_log_myid_seq'::regclass) NOT NULL,
+    user_id integer DEFAULT 0 NOT NULL,
+    target_table character varying(100) DEFAULT ''::character varying NOT NULL,
+    record_id character varying(100) DEFAULT ''::character varying NOT NULL,
+    operation character varying(10) DEFAULT ''::character varying NOT NULL,
+    old_values text,
+    new_values text,
+    diff text,
+    ip character varying(50) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_audit_log
+    ADD CONSTRAINT goadmin_audit_log_pkey PRIMARY KEY (id);
+
+CREATE INDEX goadmin_audit_log_record_index ON public.goadmin_audit_log USING btree (target_table, record_id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "goadmin_audit_log" (
+`id` integer PRIMARY KEY autoincrement,
+`user_id` INT NOT NULL DEFAULT '0',
+`target_table` CHAR(100) NOT NULL DEFAULT '',
+`record_id` CHAR(100) NOT NULL DEFAULT '',
+`operation` CHAR(10) NOT NULL DEFAULT '',
+`old_values` TEXT DEFAULT NULL,
+`new_values` TEXT DEFAULT NULL,
+`diff` TEXT DEFAULT NULL,
+`ip` CHAR(50) NOT NULL DEFAULT '',
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "goadmin_audit_log_record_index" ON "goadmin_audit_log" (`target_table`, `record_id`);
@@ -0,0 +1,9 @@
+CREATE TABLE[goadmin_role_parents] (
+ [id] int   identity(1,1) ,
+ [role_id] int   NOT NULL DEFAULT 0,
+ [parent_id] int   NOT NULL DEFAULT 0,
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE UNIQUE INDEX [goadmin_role_parents_role_id_parent_id_index] ON [goadmin_role_parents] ([role_id], [parent_id])
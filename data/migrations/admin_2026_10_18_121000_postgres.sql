@@ -0,0 +1,19 @@
+CREATE SEQUENCE public.goadmin_role_parents_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_role_parents (
+    id integer DEFAULT nextval('public.goadmin_role_parents_myid_seq'::regclass) NOT NULL,
+    role_id integer DEFAULT 0 NOT NULL,
+    parent_id integer DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_role_parents
+    ADD CONSTRAINT goadmin_role_parents_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX goadmin_role_parents_role_id_parent_id_index ON public.goadmin_role_parents USING btree (role_id, parent_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "goadmin_role_parents" (
+`id` integer PRIMARY KEY autoincrement,
+`role_id` INT NOT NULL DEFAULT '0',
+`parent_id` INT NOT NULL DEFAULT '0',
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "goadmin_role_parents_role_id_parent_id_index" ON "goadmin_role_parents" (`role_id`, `parent_id`);
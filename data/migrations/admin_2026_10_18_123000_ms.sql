@@ -0,0 +1 @@
+ALTER TABLE [goadmin_operation_log] ADD [impersonator_id] int   NOT NULL DEFAULT 0
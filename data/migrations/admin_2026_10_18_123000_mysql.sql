@@ -0,0 +1 @@
+ALTER TABLE `goadmin_operation_log` ADD COLUMN `impersonator_id` int(11) unsigned NOT NULL DEFAULT '0' AFTER `user_id`;
@@ -0,0 +1 @@
+ALTER TABLE public.goadmin_operation_log ADD COLUMN impersonator_id integer DEFAULT 0 NOT NULL;
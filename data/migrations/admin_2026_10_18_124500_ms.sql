@@ -0,0 +1,4 @@
+ALTER TABLE [goadmin_session] ADD [user_id] int   NOT NULL DEFAULT 0
+ALTER TABLE [goadmin_session] ADD [ip] varchar(50)   NOT NULL DEFAULT ''
+ALTER TABLE [goadmin_session] ADD [user_agent] varchar(500)   NOT NULL DEFAULT ''
+CREATE INDEX [goadmin_session_user_id_index] ON [goadmin_session] ([user_id])
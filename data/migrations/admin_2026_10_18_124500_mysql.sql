@@ -0,0 +1,5 @@
+ALTER TABLE `goadmin_session`
+  ADD COLUMN `user_id` int(11) unsigned NOT NULL DEFAULT '0' AFTER `values`,
+  ADD COLUMN `ip` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `user_id`,
+  ADD COLUMN `user_agent` varchar(500) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `ip`,
+  ADD KEY `goadmin_session_user_id_index` (`user_id`);
@@ -0,0 +1,5 @@
+ALTER TABLE public.goadmin_session ADD COLUMN user_id integer DEFAULT 0 NOT NULL;
+ALTER TABLE public.goadmin_session ADD COLUMN ip character varying(50) DEFAULT ''::character varying NOT NULL;
+ALTER TABLE public.goadmin_session ADD COLUMN user_agent character varying(500) DEFAULT ''::character varying NOT NULL;
+
+CREATE INDEX goadmin_session_user_id_index ON public.goadmin_session USING btree (user_id);
@@ -0,0 +1,4 @@
+ALTER TABLE "goadmin_session" ADD COLUMN `user_id` INT NOT NULL DEFAULT 0;
+ALTER TABLE "goadmin_session" ADD COLUMN `ip` CHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE "goadmin_session" ADD COLUMN `user_agent` CHAR(500) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS "goadmin_session_user_id_index" ON "goadmin_session" (`user_id`);
@@ -0,0 +1,15 @@
+CREATE TABLE[goadmin_api_keys] (
+ [id] int   identity(1,1) ,
+ [name] varchar(100)   NOT NULL DEFAULT '',
+ [user_id] int   NOT NULL DEFAULT 0,
+ [key_prefix] varchar(20)   NOT NULL DEFAULT '',
+ [key_hash] varchar(100)   NOT NULL DEFAULT '',
+ [permissions] text   NULL,
+ [expires_at] datetime NULL,
+ [last_used_at] datetime NULL,
+ [last_used_ip] varchar(50)   NOT NULL DEFAULT '',
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE UNIQUE INDEX [goadmin_api_keys_key_prefix_index] ON [goadmin_api_keys] ([key_prefix])
@@ -0,0 +1,15 @@
+CREATE TABLE `goadmin_api_keys` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `user_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `key_prefix` varchar(20) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `key_hash` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `permissions` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `expires_at` timestamp NULL DEFAULT NULL,
+  `last_used_at` timestamp NULL DEFAULT NULL,
+  `last_used_ip` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `goadmin_api_keys_key_prefix_index` (`key_prefix`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
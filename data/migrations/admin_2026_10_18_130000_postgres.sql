@@ -0,0 +1,25 @@
+CREATE SEQUENCE public.goadmin_api_keys_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_api_keys (
+    id integer DEFAULT nextval('public.goadmin_api_keys_myid_seq'::regclass) NOT NULL,
+    name character varying(100) DEFAULT ''::character varying NOT NULL,
+    user_id integer DEFAULT 0 NOT NULL,
+    key_prefix character varying(20) DEFAULT ''::character varying NOT NULL,
+    key_hash character varying(100) DEFAULT ''::character varying NOT NULL,
+    permissions text,
+    expires_at timestamp without time zone,
+    last_used_at timestamp without time zone,
+    last_used_ip character varying(50) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_api_keys
+    ADD CONSTRAINT goadmin_api_keys_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX goadmin_api_keys_key_prefix_index ON public.goadmin_api_keys USING btree (key_prefix);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS "goadmin_api_keys" (
+`id` integer PRIMARY KEY autoincrement,
+`name` CHAR(100) NOT NULL DEFAULT '',
+`user_id` INT NOT NULL DEFAULT '0',
+`key_prefix` CHAR(20) NOT NULL DEFAULT '',
+`key_hash` CHAR(100) NOT NULL DEFAULT '',
+`permissions` TEXT DEFAULT NULL,
+`expires_at` TIMESTAMP DEFAULT NULL,
+`last_used_at` TIMESTAMP DEFAULT NULL,
+`last_used_ip` CHAR(50) NOT NULL DEFAULT '',
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "goadmin_api_keys_key_prefix_index" ON "goadmin_api_keys" (`key_prefix`);
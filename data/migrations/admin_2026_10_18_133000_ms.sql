@@ -0,0 +1,19 @@
+CREATE TABLE[goadmin_change_requests] (
+ [id] int   identity(1,1) ,
+ [prefix] varchar(100)   NOT NULL DEFAULT '',
+ [target_table] varchar(100)   NOT NULL DEFAULT '',
+ [record_id] varchar(255)   NOT NULL DEFAULT '',
+ [operation] varchar(10)   NOT NULL DEFAULT '',
+ [permission] varchar(50)   NOT NULL DEFAULT '',
+ [old_values] text   NULL,
+ [new_values] text   NULL,
+ [user_id] int   NOT NULL DEFAULT 0,
+ [status] varchar(10)   NOT NULL DEFAULT 'pending',
+ [reviewer_id] int   NOT NULL DEFAULT 0,
+ [comment] varchar(255)   NOT NULL DEFAULT '',
+ [reviewed_at] datetime NULL,
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE INDEX [goadmin_change_requests_status_index] ON [goadmin_change_requests] ([status], [permission])
@@ -0,0 +1,19 @@
+CREATE TABLE `goadmin_change_requests` (
+  `id` int(10) unsigned NOT NULL AUTO_INCREMENT,
+  `prefix` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `target_table` varchar(100) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `record_id` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `operation` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `permission` varchar(50) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `old_values` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `new_values` text COLLATE utf8mb4_unicode_ci NULL DEFAULT NULL,
+  `user_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `status` varchar(10) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'pending',
+  `reviewer_id` int(11) unsigned NOT NULL DEFAULT '0',
+  `comment` varchar(255) COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '',
+  `reviewed_at` timestamp NULL DEFAULT NULL,
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `goadmin_change_requests_status_index` (`status`,`permission`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
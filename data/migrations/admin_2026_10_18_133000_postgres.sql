@@ -0,0 +1,29 @@
+CREATE SEQUENCE public.goadmin_change_requests_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_change_requests (
+    id integer DEFAULT nextval('public.goadmin_change_requests_myid_seq'::regclass) NOT NULL,
+    prefix character varying(100) DEFAULT ''::character varying NOT NULL,
+    target_table character varying(100) DEFAULT ''::character varying NOT NULL,
+    record_id character varying(255) DEFAULT ''::character varying NOT NULL,
+    operation character varying(10) DEFAULT ''::character varying NOT NULL,
+    permission character varying(50) DEFAULT ''::character varying NOT NULL,
+    old_values text,
+    new_values text,
+    user_id integer DEFAULT 0 NOT NULL,
+    status character varying(10) DEFAULT 'pending'::character varying NOT NULL,
+    reviewer_id integer DEFAULT 0 NOT NULL,
+    comment character varying(255) DEFAULT ''::character varying NOT NULL,
+    reviewed_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_change_requests
+    ADD CONSTRAINT goadmin_change_requests_pkey PRIMARY KEY (id);
+
+CREATE INDEX goadmin_change_requests_status_index ON public.goadmin_change_requests USING btree (status, permission);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS "goadmin_change_requests" (
+`id` integer PRIMARY KEY autoincrement,
+`prefix` CHAR(100) NOT NULL DEFAULT '',
+`target_table` CHAR(100) NOT NULL DEFAULT '',
+`record_id` CHAR(255) NOT NULL DEFAULT '',
+`operation` CHAR(10) NOT NULL DEFAULT '',
+`permission` CHAR(50) NOT NULL DEFAULT '',
+`old_values` TEXT DEFAULT NULL,
+`new_values` TEXT DEFAULT NULL,
+`user_id` INT NOT NULL DEFAULT '0',
+`status` CHAR(10) NOT NULL DEFAULT 'pending',
+`reviewer_id` INT NOT NULL DEFAULT '0',
+`comment` CHAR(255) NOT NULL DEFAULT '',
+`reviewed_at` TIMESTAMP DEFAULT NULL,
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS "goadmin_change_requests_status_index" ON "goadmin_change_requests" (`status`, `permission`);
@@ -0,0 +1,11 @@
+CREATE TABLE[goadmin_user_identities] (
+ [id] int   identity(1,1) ,
+ [user_id] int   NOT NULL,
+ [provider] varchar(100)   NOT NULL DEFAULT '',
+ [subject] varchar(255)   NOT NULL DEFAULT '',
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE UNIQUE INDEX [goadmin_user_identities_subject_unique] ON [goadmin_user_identities] ([provider], [subject])
+CREATE INDEX [goadmin_user_identities_user_id_index] ON [goadmin_user_identities] ([user_id])
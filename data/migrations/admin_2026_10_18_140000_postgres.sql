@@ -0,0 +1,21 @@
+CREATE SEQUENCE public.goadmin_user_identities_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_user_identities (
+    id integer DEFAULT nextval('public.goadmin_user_identities_myid_seq'::regclass) NOT NULL,
+    user_id integer NOT NULL,
+    provider character varying(100) DEFAULT ''::character varying NOT NULL,
+    subject character varying(255) DEFAULT ''::character varying NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_user_identities
+    ADD CONSTRAINT goadmin_user_identities_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX goadmin_user_identities_subject_unique ON public.goadmin_user_identities USING btree (provider, subject);
+CREATE INDEX goadmin_user_identities_user_id_index ON public.goadmin_user_identities USING btree (user_id);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS "goadmin_user_identities" (
+`id` integer PRIMARY KEY autoincrement,
+`user_id` INT NOT NULL,
+`provider` CHAR(100) NOT NULL DEFAULT '',
+`subject` CHAR(255) NOT NULL DEFAULT '',
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "goadmin_user_identities_subject_unique" ON "goadmin_user_identities" (`provider`, `subject`);
+CREATE INDEX IF NOT EXISTS "goadmin_user_identities_user_id_index" ON "goadmin_user_identities" (`user_id`);
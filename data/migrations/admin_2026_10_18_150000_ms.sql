@@ -0,0 +1,9 @@
+CREATE TABLE[goadmin_user_token_versions] (
+ [id] int   identity(1,1) ,
+ [user_id] int   NOT NULL,
+ [version] int   NOT NULL DEFAULT 0,
+ [created_at] datetime NULL DEFAULT GETDATE(),
+ [updated_at] datetime NULL DEFAULT GETDATE(),
+  PRIMARY KEY ([id])
+)
+CREATE UNIQUE INDEX [goadmin_user_token_versions_user_id_unique] ON [goadmin_user_token_versions] ([user_id])
@@ -0,0 +1,19 @@
+CREATE SEQUENCE public.goadmin_user_token_versions_myid_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    MAXVALUE 99999999
+    CACHE 1;
+
+CREATE TABLE public.goadmin_user_token_versions (
+    id integer DEFAULT nextval('public.goadmin_user_token_versions_myid_seq'::regclass) NOT NULL,
+    user_id integer NOT NULL,
+    version integer DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT now(),
+    updated_at timestamp without time zone DEFAULT now()
+);
+
+ALTER TABLE ONLY public.goadmin_user_token_versions
+    ADD CONSTRAINT goadmin_user_token_versions_pkey PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX goadmin_user_token_versions_user_id_unique ON public.goadmin_user_token_versions USING btree (user_id);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "goadmin_user_token_versions" (
+`id` integer PRIMARY KEY autoincrement,
+`user_id` INT NOT NULL,
+`version` INT NOT NULL DEFAULT 0,
+`created_at` TIMESTAMP default CURRENT_TIMESTAMP,
+`updated_at` TIMESTAMP default CURRENT_TIMESTAMP
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "goadmin_user_token_versions_user_id_unique" ON "goadmin_user_token_versions" (`user_id`);
@@ -117,6 +117,19 @@ func (eng *Engine) Use(router interface{}) error {
 				language.GetWithScope("tool", "tool")))
 	}
 
+	// 待審核的變更申請通知，有可審核的申請時顯示數量
+	changeRequestUrl := config.Url("/info/change_requests?status=" + models.ChangePending)
+	*eng.NavButtons = (*eng.NavButtons).AddNavButton(icon.Bell, types.NavBtnApprovalName,
+		action.Jump(changeRequestUrl, template2.HTML(`<script>
+window.addEventListener('load', function () {
+    $.get('`+config.Url("/change_requests/pending")+`', function (data) {
+        if (data.data && data.data.count > 0) {
+            $('a[href="`+changeRequestUrl+`"]').append('<span class="label label-warning">' + data.data.count + '</span>');
+        }
+    });
+});
+</script>`)))
+
 	navButtons = eng.NavButtons
 
 	// ui.ServiceKey = ui
@@ -177,6 +190,12 @@ func (eng *Engine) AddAuthService(processor auth.Processor) *Engine {
 	return eng
 }
 
+// AddLDAPAuthService authenticate the users with the directory of the ldap config.
+// 以設置的LDAP/AD目錄驗證用戶，第一次登入時自動建立用戶
+func (eng *Engine) AddLDAPAuthService() *Engine {
+	return eng.AddAuthService(auth.LDAPProcessor(eng.DefaultConnection))
+}
+
 // ============================
 // Config APIs
 // ============================
@@ -403,7 +422,9 @@ func (eng *Engine) deferHandler(conn db.Connection) context.Handler {
 				// OperationLog回傳預設的OperationLogModel(struct)，記錄使用者操作行為，預設資料表為goadmin_operation_log
 				// SetConn將參數conn(Connection(interface))設置至OperationLogModel.Base.Conn(struct)
 				// New新增一筆使用者行為資料至資料表，回傳OperationLogModel(struct)
-				models.OperationLog().SetConn(conn).New(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(), string(input))
+				// 以其他用戶身分登入時同時記錄原本的超級管理員
+				models.OperationLog().SetConn(conn).SetImpersonator(user.ImpersonatorId).
+					New(user.Id, ctx.Path(), ctx.Method(), ctx.LocalIP(), string(input))
 			}
 
 			// 出現錯誤
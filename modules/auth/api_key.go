@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+const (
+	// APIKeyHeader is the header which carries the api key of the service account.
+	APIKeyHeader = "X-Api-Key"
+
+	// api key的格式為 gak_<前綴8碼>_<隨機32碼>
+	apiKeyScheme    = "gak_"
+	apiKeyPrefixLen = len(apiKeyScheme) + 8
+)
+
+// GenerateAPIKey return a random api key, the prefix used to find the key and the hash to store.
+// 產生隨機的api key，回傳明文(只顯示一次)、查詢用的前綴以及儲存用的sha256 hash
+func GenerateAPIKey() (key, prefix, hash string) {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	s := hex.EncodeToString(b)
+	key = apiKeyScheme + s[:8] + "_" + s[8:]
+	return key, key[:apiKeyPrefixLen], HashAPIKey(key)
+}
+
+// HashAPIKey return the sha256 hash of the api key.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(key)))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKey return the api key of the request, empty if not exist.
+// 取得header中的api key
+func APIKey(ctx *context.Context) string {
+	return strings.TrimSpace(ctx.Headers(APIKeyHeader))
+}
+
+// GetAPIKeyUser return the service account of the api key with its roles, the permissions of
+// the user are limited to the permissions bound to the key which the account also has.
+// 驗證api key並回傳其服務帳號(包含角色，以套用資料範圍及ip規則)，權限為api key綁定的權限與帳號權限的交集
+// 過期或錯誤的key回傳false
+func GetAPIKeyUser(ctx *context.Context, key string, conn db.Connection) (user models.UserModel, ok bool) {
+	if len(key) <= apiKeyPrefixLen || !strings.HasPrefix(key, apiKeyScheme) {
+		return
+	}
+
+	apiKey := models.APIKey().SetConn(conn).FindByPrefix(key[:apiKeyPrefixLen])
+	if apiKey.IsEmpty() || apiKey.IsExpired() ||
+		subtle.ConstantTimeCompare([]byte(apiKey.Hash), []byte(HashAPIKey(key))) != 1 {
+		return
+	}
+
+	user = models.User().SetConn(conn).Find(apiKey.UserId)
+	if user.IsEmpty() {
+		return
+	}
+
+	user = user.WithRoles().WithPermissions().WithAPIKey(apiKey.Id, apiKey.GetPermissions())
+
+	apiKey.Touch(ClientIP(ctx))
+
+	return user, true
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash := GenerateAPIKey()
+
+	assert.Equal(t, strings.HasPrefix(key, "gak_"), true)
+	assert.Equal(t, len(key), 45)
+	assert.Equal(t, prefix, key[:12])
+	assert.Equal(t, hash, HashAPIKey(key))
+	assert.Equal(t, hash, HashAPIKey(" "+key+" "))
+
+	key2, prefix2, _ := GenerateAPIKey()
+	assert.NotEqual(t, key, key2)
+	assert.NotEqual(t, prefix, prefix2)
+
+	_, ok := GetAPIKeyUser(nil, "wrong", nil)
+	assert.Equal(t, ok, false)
+	_, ok = GetAPIKeyUser(nil, prefix, nil)
+	assert.Equal(t, ok, false)
+}
+
+func TestGetAPIKeyUser(t *testing.T) {
+	conn, cleanup := testSqliteConn(t)
+	defer cleanup()
+
+	key, prefix, hash := GenerateAPIKey()
+	apiKey, err := models.APIKey().SetConn(conn).New("test", 1, prefix, hash, []string{}, "")
+	assert.Equal(t, err, nil)
+
+	req := httptest.NewRequest("GET", "/admin/api/list/manager", nil)
+	req.RemoteAddr = "10.0.0.9:1234"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+
+	user, ok := GetAPIKeyUser(context.NewContext(req), key, conn)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, user.Id, int64(1))
+
+	// the forwarded ip of the untrusted proxy is not recorded
+	apiKey = models.APIKey().SetConn(conn).FindByPrefix(prefix)
+	assert.Equal(t, apiKey.LastUsedIp, "10.0.0.9")
+}
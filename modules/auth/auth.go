@@ -6,12 +6,15 @@ package auth
 
 import (
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/modules/service"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"golang.org/x/crypto/bcrypt"
 	"sync"
+	"time"
 )
 
 // Auth get the user model from Context.
@@ -95,8 +98,10 @@ func DelCookie(ctx *context.Context, conn db.Connection) error {
 }
 
 type TokenService struct {
-	tokens CSRFToken //[]string
-	lock   sync.Mutex
+	tokens    CSRFToken //map[string]time.Time
+	lock      sync.Mutex
+	driver    PersistenceDriver
+	lastClean time.Time
 }
 
 // 回傳"token_csrf_helper"
@@ -111,7 +116,7 @@ func init() {
 	service.Register(TokenServiceKey, func() (service.Service, error) {
 		// 設置並回傳TokenService.tokens(struct)
 		return &TokenService{
-			tokens: make(CSRFToken, 0),
+			tokens: make(CSRFToken),
 		}, nil
 	})
 }
@@ -119,6 +124,9 @@ func init() {
 const (
 	TokenServiceKey = "token_csrf_helper"
 	ServiceKey      = "auth"
+
+	// 以PersistenceDriver儲存時sid的前綴
+	csrfTokenSidPrefix = "csrf_"
 )
 
 // 將參數s轉換成TokenService(struct)類別後回傳
@@ -129,33 +137,96 @@ func GetTokenService(s interface{}) *TokenService {
 	panic("wrong service")
 }
 
-// AddToken add the token to the CSRFToken.
-// 建立uuid並設置至TokenService.tokens，回傳uuid(string)
-func (s *TokenService) AddToken() string {
+// UseDriver set the PersistenceDriver of the tokens, so that the tokens can be shared
+// between the instances.
+// 設置token的儲存驅動，多個實例可以共用csrf token
+func (s *TokenService) UseDriver(driver PersistenceDriver) *TokenService {
 	s.lock.Lock()
 	defer s.lock.Unlock()
+	s.driver = driver
+	return s
+}
+
+// AddToken add the token to the CSRFToken.
+// 建立uuid並設置至TokenService.tokens(有設置驅動則儲存至驅動)，回傳uuid(string)
+func (s *TokenService) AddToken() string {
 	// 設置uuid
 	tokenStr := modules.Uuid()
-	s.tokens = append(s.tokens, tokenStr)
+	lifeTime := config.GetCSRFTokenLifeTime()
+	if lifeTime <= 0 {
+		lifeTime = 7200
+	}
+	expireAt := time.Now().Add(time.Duration(lifeTime) * time.Second)
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.driver != nil {
+		err := s.driver.Update(csrfTokenSidPrefix+tokenStr, map[string]interface{}{
+			"csrf_token": tokenStr,
+			"expire_at":  expireAt.Unix(),
+		})
+		if err == nil {
+			return tokenStr
+		}
+		logger.Error("save csrf token failed", err)
+	}
+
+	s.clean()
+	s.tokens[tokenStr] = expireAt
 	return tokenStr
 }
 
 // CheckToken check the given token with tokens in the CSRFToken, if exist
 // return true.
-// 檢查TokenService.tokens([]string)裡是否有符合參數toCheckToken的值
-// 如果符合，將在TokenService.tokens([]string)裡將符合的toCheckToken從[]string拿出
+// 檢查參數toCheckToken是否存在且未過期，token只能使用一次，檢查後即刪除
 func (s *TokenService) CheckToken(toCheckToken string) bool {
-	for i := 0; i < len(s.tokens); i++ {
-		if (s.tokens)[i] == toCheckToken {
-			s.tokens = append((s.tokens)[:i], (s.tokens)[i+1:]...)
-			return true
+	if toCheckToken == "" {
+		return false
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if expireAt, ok := s.tokens[toCheckToken]; ok {
+		delete(s.tokens, toCheckToken)
+		return expireAt.After(time.Now())
+	}
+
+	if s.driver == nil {
+		return false
+	}
+
+	sid := csrfTokenSidPrefix + toCheckToken
+	values, err := s.driver.Load(sid)
+	if err != nil || values["csrf_token"] != toCheckToken {
+		return false
+	}
+
+	if err = s.driver.Update(sid, map[string]interface{}{}); err != nil {
+		logger.Error("delete csrf token failed", err)
+	}
+
+	expireAt, _ := values["expire_at"].(float64)
+	return int64(expireAt) > time.Now().Unix()
+}
+
+// 每分鐘最多清除一次過期的token
+func (s *TokenService) clean() {
+	now := time.Now()
+	if now.Sub(s.lastClean) < time.Minute {
+		return
+	}
+	s.lastClean = now
+	for token, expireAt := range s.tokens {
+		if expireAt.Before(now) {
+			delete(s.tokens, token)
 		}
 	}
-	return false
 }
 
-// CSRFToken is type of a csrf token list.
-type CSRFToken []string
+// CSRFToken is type of a csrf token set, the value is the expiration time.
+type CSRFToken map[string]time.Time
 
 type Processor func(ctx *context.Context) (model models.UserModel, exist bool, msg string)
 
@@ -3,9 +3,30 @@ package auth
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestEncodePassword(t *testing.T) {
 	pwd := EncodePassword([]byte("123456"))
 	assert.Equal(t, comparePassword("123456", pwd), true)
 }
+
+func TestTokenService(t *testing.T) {
+	s := &TokenService{tokens: make(CSRFToken)}
+
+	token := s.AddToken()
+	assert.Equal(t, s.CheckToken(token), true)
+	assert.Equal(t, s.CheckToken(token), false)
+	assert.Equal(t, s.CheckToken(""), false)
+
+	token = s.AddToken()
+	s.tokens[token] = time.Now().Add(-time.Second)
+	assert.Equal(t, s.CheckToken(token), false)
+
+	// tokens stored by the driver can be checked by another instance
+	driver := NewMemoryDriver(time.Hour)
+	token = (&TokenService{tokens: make(CSRFToken)}).UseDriver(driver).AddToken()
+	another := (&TokenService{tokens: make(CSRFToken)}).UseDriver(driver)
+	assert.Equal(t, another.CheckToken(token), true)
+	assert.Equal(t, another.CheckToken(token), false)
+}
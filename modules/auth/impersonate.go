@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+// session中記錄原本登入的超級管理員的key
+const impersonatorSesKey = "impersonator_id"
+
+// Impersonate log in as the target user, and remember the super admin in the session.
+// 超級管理員以目標用戶的身分登入，session中會記錄原本的帳號以便結束時切換回來
+func Impersonate(ctx *context.Context, conn db.Connection, impersonator, target models.UserModel) error {
+	if !impersonator.IsSuperAdmin() || impersonator.IsImpersonated() {
+		return errors.New("only super admin can impersonate")
+	}
+	if target.IsEmpty() || target.Id == impersonator.Id {
+		return errors.New("wrong user")
+	}
+
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	ses.Values[impersonatorSesKey] = impersonator.Id
+
+	return ses.Add(defaultUserIDSesKey, target.Id)
+}
+
+// ExitImpersonate switch the session back to the super admin, and return the id of the super admin.
+// 結束以其他用戶身分登入，將session切換回原本的超級管理員並回傳其id
+func ExitImpersonate(ctx *context.Context, conn db.Connection) (int64, error) {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	id, ok := ses.Get(impersonatorSesKey).(float64)
+	if !ok {
+		return 0, errors.New("not impersonating")
+	}
+
+	delete(ses.Values, impersonatorSesKey)
+
+	return int64(id), ses.Add(defaultUserIDSesKey, int64(id))
+}
+
+// 如果session中記錄了原本登入的超級管理員，將其設置至用戶，原帳號已不是超級管理員時回傳false
+func withImpersonator(ses *Session, user models.UserModel, conn db.Connection) (models.UserModel, bool) {
+	id, ok := ses.Get(impersonatorSesKey).(float64)
+	if !ok {
+		return user, true
+	}
+
+	impersonator := models.User().SetConn(conn).Find(int64(id))
+	if impersonator.IsEmpty() || !impersonator.WithRoles().WithPermissions().IsSuperAdmin() {
+		return user, false
+	}
+
+	user.ImpersonatorId = impersonator.Id
+	user.ImpersonatorName = impersonator.Name
+
+	return user, true
+}
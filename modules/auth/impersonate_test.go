@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImpersonate(t *testing.T) {
+	var (
+		admin = models.UserModel{Id: 1, Permissions: []models.PermissionModel{
+			{HttpMethod: []string{""}, HttpPath: []string{"*"}},
+		}}
+		user = models.UserModel{Id: 2}
+	)
+
+	assert.NotEqual(t, Impersonate(nil, nil, user, admin), nil)
+	assert.NotEqual(t, Impersonate(nil, nil, admin, admin), nil)
+	assert.NotEqual(t, Impersonate(nil, nil, admin, models.UserModel{}), nil)
+
+	admin.ImpersonatorId = 3
+	assert.Equal(t, admin.IsImpersonated(), true)
+	assert.NotEqual(t, Impersonate(nil, nil, admin, user), nil)
+
+	res, ok := withImpersonator(&Session{Values: map[string]interface{}{"user_id": float64(2)}}, user, nil)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, res.IsImpersonated(), false)
+}
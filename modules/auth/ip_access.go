@@ -0,0 +1,167 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"encoding/json"
+	"net"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+// The reasons of the denied request which are written to the operation log.
+const (
+	IPDenyGlobal = "ip not allowed"
+	IPDenyRole   = "ip not allowed for the role"
+)
+
+// ClientIP return the ip of the request client. The X-Forwarded-For and X-Real-Ip headers
+// are only used when the request comes from the trusted proxies.
+// 回傳用戶端ip，只有連線ip為信任的代理時才採用X-Forwarded-For(由右往左略過信任的代理)、X-Real-Ip
+func ClientIP(ctx *context.Context) string {
+	proxies, _ := utils.ParseIPNets(config.GetIPAccess().TrustedProxies)
+	return forwardedIP(remoteIP(ctx.Request.RemoteAddr),
+		ctx.Headers("X-Forwarded-For"), ctx.Headers("X-Real-Ip"), proxies)
+}
+
+func remoteIP(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if ip, _, err := net.SplitHostPort(addr); err == nil {
+		return ip
+	}
+	if addr != "" {
+		return addr
+	}
+	return "127.0.0.1"
+}
+
+func forwardedIP(remote, forwardedFor, realIP string, proxies []*net.IPNet) string {
+	if ip := net.ParseIP(remote); ip == nil || !utils.ContainsIP(proxies, ip) {
+		return remote
+	}
+	hops := strings.Split(forwardedFor, ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		if ip := net.ParseIP(hop); ip == nil || !utils.ContainsIP(proxies, ip) || i == 0 {
+			return hop
+		}
+	}
+	if realIP = strings.TrimSpace(realIP); realIP != "" {
+		return realIP
+	}
+	return remote
+}
+
+// ipAllowed 符合deny的ip拒絕，allow不為空時只允許符合的ip
+func ipAllowed(ip string, allow, deny []string) bool {
+	if len(allow) == 0 && len(deny) == 0 {
+		return true
+	}
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	if denyNets, _ := utils.ParseIPNets(deny); utils.ContainsIP(denyNets, addr) {
+		return false
+	}
+	if len(allow) == 0 {
+		return true
+	}
+	allowNets, _ := utils.ParseIPNets(allow)
+	return utils.ContainsIP(allowNets, addr)
+}
+
+// CheckIPAccess apply the ip access rules to the user. It return false if the ip is not allowed
+// globally, otherwise the roles(with their parent roles) which are not allowed are removed from
+// the user and their slugs are returned.
+// 依ip存取規則檢查用戶，全域規則不允許時回傳false，角色(或其上層角色)規則不允許時移除該角色的權限並回傳被移除的角色
+func CheckIPAccess(user models.UserModel, ip string) (models.UserModel, []string, bool) {
+	cfg := config.GetIPAccess()
+	if !cfg.Enabled() {
+		return user, nil, true
+	}
+
+	if !ipAllowed(ip, cfg.Allow, cfg.Deny) {
+		return user, nil, false
+	}
+
+	if len(cfg.RoleRules) == 0 || len(user.Roles) == 0 {
+		return user, nil, true
+	}
+
+	var (
+		removedIds []int64
+		removed    []string
+		roleSlugs  = user.RoleSlugsWithAncestors()
+	)
+
+	for _, role := range user.Roles {
+		if !roleAllowed(ip, roleSlugs[role.Id], cfg.RoleRules) {
+			removedIds = append(removedIds, role.Id)
+			removed = append(removed, role.Slug)
+		}
+	}
+
+	if len(removedIds) > 0 {
+		user = user.WithoutRoles(removedIds...)
+	}
+
+	return user, removed, true
+}
+
+func roleAllowed(ip string, slugs []string, rules []config.IPRoleRule) bool {
+	for _, slug := range slugs {
+		for _, rule := range rules {
+			if rule.Role == slug && !ipAllowed(ip, rule.Allow, rule.Deny) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkAccess 依ip存取規則及權限檢查用戶，拒絕的請求寫入紀錄
+func checkAccess(ctx *context.Context, user models.UserModel, conn db.Connection) (models.UserModel, bool) {
+	ip := ClientIP(ctx)
+
+	user, removed, ok := CheckIPAccess(user, ip)
+	if !ok {
+		logIPDenied(ctx, user, ip, IPDenyGlobal, nil, conn)
+		return user, false
+	}
+
+	// CheckPermissions透過path、method、param檢查用戶權限
+	if CheckPermissions(user, ctx.Request.URL.String(), ctx.Method(), ctx.PostForm()) {
+		return user, true
+	}
+
+	// 因角色被移除而沒有權限時才視為違反規則
+	if len(removed) > 0 {
+		logIPDenied(ctx, user, ip, IPDenyRole, removed, conn)
+	}
+
+	return user, false
+}
+
+// logIPDenied 將違反ip存取規則的請求寫入log及操作紀錄(goadmin_operation_log)
+func logIPDenied(ctx *context.Context, user models.UserModel, ip, reason string, roles []string, conn db.Connection) {
+	logger.Warnf("ip access denied: user %s, ip %s, path %s, reason %s %s",
+		user.UserName, ip, ctx.Path(), reason, strings.Join(roles, ","))
+
+	input, _ := json.Marshal(map[string]string{
+		"reason": reason,
+		"roles":  strings.Join(roles, ","),
+	})
+	models.OperationLog().SetConn(conn).New(user.Id, ctx.Path(), ctx.Method(), ip, string(input))
+}
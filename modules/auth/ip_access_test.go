@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForwardedIP(t *testing.T) {
+	proxies, _ := utils.ParseIPNets([]string{"10.0.0.0/8"})
+
+	// 不是來自信任的代理時不採用header
+	assert.Equal(t, forwardedIP("1.2.3.4", "5.6.7.8", "5.6.7.8", proxies), "1.2.3.4")
+	assert.Equal(t, forwardedIP("10.0.0.2", "5.6.7.8", "", nil), "10.0.0.2")
+
+	// 由右往左略過信任的代理，偽造的最左邊ip不會被採用
+	assert.Equal(t, forwardedIP("10.0.0.2", "9.9.9.9, 5.6.7.8, 10.0.0.3", "", proxies), "5.6.7.8")
+	assert.Equal(t, forwardedIP("10.0.0.2", "10.0.0.4, 10.0.0.3", "", proxies), "10.0.0.4")
+	assert.Equal(t, forwardedIP("10.0.0.2", "", "5.6.7.8", proxies), "5.6.7.8")
+	assert.Equal(t, forwardedIP("10.0.0.2", "", "", proxies), "10.0.0.2")
+
+	assert.Equal(t, remoteIP("1.2.3.4:5678"), "1.2.3.4")
+	assert.Equal(t, remoteIP("[::1]:80"), "::1")
+	assert.Equal(t, remoteIP(""), "127.0.0.1")
+}
+
+func TestIPAllowed(t *testing.T) {
+	assert.Equal(t, ipAllowed("1.2.3.4", nil, nil), true)
+	assert.Equal(t, ipAllowed("1.2.3.4", nil, []string{"1.2.3.0/24"}), false)
+	assert.Equal(t, ipAllowed("1.2.4.4", nil, []string{"1.2.3.0/24"}), true)
+	assert.Equal(t, ipAllowed("10.1.1.1", []string{"10.0.0.0/8"}, nil), true)
+	assert.Equal(t, ipAllowed("11.1.1.1", []string{"10.0.0.0/8"}, nil), false)
+	assert.Equal(t, ipAllowed("10.1.1.1", []string{"10.0.0.0/8"}, []string{"10.1.1.1"}), false)
+	assert.Equal(t, ipAllowed("unknown", []string{"10.0.0.0/8"}, nil), false)
+
+	rules := []config.IPRoleRule{{Role: "finance", Allow: []string{"192.168.10.0/24"}}}
+	assert.Equal(t, roleAllowed("192.168.10.8", []string{"finance"}, rules), true)
+	assert.Equal(t, roleAllowed("8.8.8.8", []string{"finance"}, rules), false)
+	// 繼承finance的下層角色同樣受限制
+	assert.Equal(t, roleAllowed("8.8.8.8", []string{"finance_junior", "finance"}, rules), false)
+	assert.Equal(t, roleAllowed("8.8.8.8", []string{"operator"}, rules), true)
+}
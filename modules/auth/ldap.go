@@ -0,0 +1,208 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/ldap"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+// LDAPIdentityProvider is the provider of the identities authenticated by LDAP.
+const LDAPIdentityProvider = "ldap"
+
+// ErrLDAPInvalidCredentials is returned when the user is not found or the password is wrong.
+var ErrLDAPInvalidCredentials = errors.New("wrong password or username")
+
+// LDAPUser is the user found in the directory.
+type LDAPUser struct {
+	DN       string
+	Username string
+	Name     string
+	Groups   []string
+}
+
+// LDAPProcessor return a Processor which authenticates the user against the directory of
+// config.LDAP, the goadmin user is created at the first login.
+// 以LDAP/AD驗證帳號密碼的Processor，第一次登入時自動建立用戶並依群組設置角色
+// getConn回傳預設的資料庫連線，例如engine.DefaultConnection
+func LDAPProcessor(getConn func() db.Connection) Processor {
+	return func(ctx *context.Context) (models.UserModel, bool, string) {
+		cfg := config.GetLDAP()
+		username, password := ctx.FormValue("username"), ctx.FormValue("password")
+
+		if username == "" || password == "" {
+			return models.User(), false, "wrong password or username"
+		}
+
+		ldapUser, err := LDAPAuthenticate(cfg, username, password)
+		if err == ErrLDAPInvalidCredentials {
+			return models.User(), false, "wrong password or username"
+		}
+		if err != nil {
+			logger.Error("ldap authenticate error", err)
+			return models.User(), false, "fail"
+		}
+
+		user, err := ProvisionLDAPUser(cfg, ldapUser, getConn())
+		if err != nil {
+			logger.Error("ldap provision error", err)
+			return models.User(), false, err.Error()
+		}
+
+		return user.WithRoles().WithPermissions().WithMenus(), true, "ok"
+	}
+}
+
+// LDAPAuthenticate search the user in the directory and bind with the password.
+// 以服務帳號搜尋用戶後再以用戶DN及密碼驗證，成功時回傳用戶的名稱及群組
+func LDAPAuthenticate(cfg config.LDAP, username, password string) (LDAPUser, error) {
+	if password == "" {
+		return LDAPUser{}, ErrLDAPInvalidCredentials
+	}
+
+	var (
+		timeout   = time.Duration(cfg.Timeout) * time.Second
+		tlsConfig = &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	)
+
+	conn, err := ldap.Dial(cfg.Url, timeout, tlsConfig)
+	if err != nil {
+		return LDAPUser{}, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			return LDAPUser{}, err
+		}
+	}
+
+	if cfg.BindDN != "" {
+		if err := conn.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+			return LDAPUser{}, err
+		}
+	}
+
+	attrs := []string{cfg.NameAttribute}
+	if cfg.GroupAttribute != "" {
+		attrs = append(attrs, cfg.GroupAttribute)
+	}
+
+	entries, err := conn.Search(ldap.SearchRequest{
+		BaseDN:     cfg.BaseDN,
+		Scope:      ldap.ScopeWholeSubtree,
+		SizeLimit:  2,
+		Filter:     ldapFilter(cfg.UserFilter, username, ""),
+		Attributes: attrs,
+	})
+	if err != nil {
+		return LDAPUser{}, err
+	}
+	// 找不到或找到多個用戶時都視為帳號錯誤
+	if len(entries) != 1 {
+		return LDAPUser{}, ErrLDAPInvalidCredentials
+	}
+	entry := entries[0]
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.ResultInvalidCredentials) {
+			return LDAPUser{}, ErrLDAPInvalidCredentials
+		}
+		return LDAPUser{}, err
+	}
+
+	user := LDAPUser{
+		DN:       entry.DN,
+		Username: username,
+		Name:     entry.GetAttributeValue(cfg.NameAttribute),
+	}
+	if cfg.GroupAttribute != "" {
+		user.Groups = entry.GetAttributeValues(cfg.GroupAttribute)
+	}
+
+	if cfg.GroupBaseDN != "" && cfg.GroupFilter != "" {
+		// 有服務帳號時切換回服務帳號搜尋群組，否則以用戶的身分搜尋
+		if cfg.BindDN != "" {
+			if err := conn.Bind(cfg.BindDN, cfg.BindPassword); err != nil {
+				return LDAPUser{}, err
+			}
+		}
+		groups, err := conn.Search(ldap.SearchRequest{
+			BaseDN:     cfg.GroupBaseDN,
+			Scope:      ldap.ScopeWholeSubtree,
+			Filter:     ldapFilter(cfg.GroupFilter, username, entry.DN),
+			Attributes: []string{"cn"},
+		})
+		if err != nil {
+			return LDAPUser{}, err
+		}
+		for _, group := range groups {
+			user.Groups = append(user.Groups, group.DN)
+		}
+	}
+
+	return user, nil
+}
+
+// LDAPRoles return the role slugs of the groups by the rules of config.LDAP.GroupRoles.
+// 依GroupRoles將群組轉換成角色(slug)，沒有任何對應時回傳DefaultRoles
+func LDAPRoles(cfg config.LDAP, groups []string) []string {
+	var roles []string
+	for _, rule := range cfg.GroupRoles {
+		for _, group := range groups {
+			if ldapGroupMatch(rule.Group, group) && !utils.InArray(roles, rule.Role) {
+				roles = append(roles, rule.Role)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		roles = append(roles, cfg.DefaultRoles...)
+	}
+	return roles
+}
+
+// ProvisionLDAPUser return the goadmin user linked to the DN of the directory user, the user
+// is created if not exists, and the roles of the new user(or every login when SyncRoles is
+// true) are set.
+// 以DN尋找綁定的用戶，不存在時建立用戶並依群組設置角色
+func ProvisionLDAPUser(cfg config.LDAP, ldapUser LDAPUser, conn db.Connection) (models.UserModel, error) {
+	return ProvisionUser(ExternalIdentity{
+		Provider: LDAPIdentityProvider,
+		Subject:  strings.ToLower(ldapUser.DN),
+		Username: ldapUser.Username,
+		Name:     ldapUser.Name,
+	}, LDAPRoles(cfg, ldapUser.Groups), cfg.SyncRoles, conn)
+}
+
+// 將過濾條件中的{username}、{dn}替換成跳脫後的值
+func ldapFilter(filter, username, dn string) string {
+	return strings.NewReplacer("{username}", ldap.EscapeFilter(username),
+		"{dn}", ldap.EscapeFilter(dn)).Replace(filter)
+}
+
+// 比對群組DN或第一個RDN的值
+func ldapGroupMatch(rule, group string) bool {
+	if strings.EqualFold(rule, group) {
+		return true
+	}
+	rdn := group
+	if i := strings.IndexByte(group, ','); i >= 0 {
+		rdn = group[:i]
+	}
+	if i := strings.IndexByte(rdn, '='); i >= 0 {
+		return strings.EqualFold(strings.TrimSpace(rdn[i+1:]), rule)
+	}
+	return false
+}
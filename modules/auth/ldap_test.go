@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/ldap/ldaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLDAPAuthenticate(t *testing.T) {
+	server := ldaptest.NewServer(
+		ldaptest.Entry{DN: "cn=reader,dc=example,dc=com", Password: "reader"},
+		ldaptest.Entry{DN: "uid=jack,ou=people,dc=example,dc=com", Password: "jack123", Attributes: map[string][]string{
+			"uid":      {"jack"},
+			"cn":       {"Jack"},
+			"memberOf": {"cn=admins,ou=groups,dc=example,dc=com"},
+		}},
+		ldaptest.Entry{DN: "cn=dev,ou=groups,dc=example,dc=com", Attributes: map[string][]string{
+			"cn":     {"dev"},
+			"member": {"uid=jack,ou=people,dc=example,dc=com"},
+		}},
+	)
+	defer server.Close()
+
+	cfg := config.LDAP{
+		Url:            server.URL(),
+		BindDN:         "cn=reader,dc=example,dc=com",
+		BindPassword:   "reader",
+		BaseDN:         "ou=people,dc=example,dc=com",
+		UserFilter:     "(uid={username})",
+		NameAttribute:  "cn",
+		GroupAttribute: "memberOf",
+		GroupBaseDN:    "ou=groups,dc=example,dc=com",
+		GroupFilter:    "(member={dn})",
+		Timeout:        1,
+	}
+
+	user, err := LDAPAuthenticate(cfg, "jack", "jack123")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, user.DN, "uid=jack,ou=people,dc=example,dc=com")
+	assert.Equal(t, user.Name, "Jack")
+	assert.Equal(t, user.Groups, []string{"cn=admins,ou=groups,dc=example,dc=com", "cn=dev,ou=groups,dc=example,dc=com"})
+
+	for _, item := range [][2]string{{"jack", "wrong"}, {"jack", ""}, {"nobody", "jack123"}, {"*", "jack123"}} {
+		_, err = LDAPAuthenticate(cfg, item[0], item[1])
+		assert.Equal(t, err, ErrLDAPInvalidCredentials)
+	}
+
+	cfg.BindPassword = "wrong"
+	_, err = LDAPAuthenticate(cfg, "jack", "jack123")
+	assert.NotEqual(t, err, nil)
+	assert.NotEqual(t, err, ErrLDAPInvalidCredentials)
+}
+
+func TestLDAPRoles(t *testing.T) {
+	cfg := config.LDAP{
+		GroupRoles: []config.LDAPGroupRole{
+			{Group: "cn=admins,ou=groups,dc=example,dc=com", Role: "administrator"},
+			{Group: "Dev", Role: "operator"},
+			{Group: "ops", Role: "operator"},
+		},
+		DefaultRoles: []string{"visitor"},
+	}
+
+	assert.Equal(t, LDAPRoles(cfg, []string{"CN=Admins,OU=Groups,DC=example,DC=com"}), []string{"administrator"})
+	assert.Equal(t, LDAPRoles(cfg, []string{"cn=dev,dc=example", "cn=ops,dc=example"}), []string{"operator"})
+	assert.Equal(t, LDAPRoles(cfg, []string{"cn=others,dc=example"}), []string{"visitor"})
+
+	cfg.DefaultRoles = nil
+	assert.Equal(t, len(LDAPRoles(cfg, nil)), 0)
+}
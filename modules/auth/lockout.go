@@ -0,0 +1,129 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+// The reasons of the failed login attempt which are written to the operation log.
+const (
+	LoginFailWrongPassword = "wrong password or username"
+	LoginFailWrongCaptcha  = "wrong captcha"
+	LoginFailWrongCode     = "wrong two factor code"
+	LoginFailUserLocked    = "username locked"
+	LoginFailIPLocked      = "ip locked"
+	LoginFailThrottled     = "too many login attempts"
+)
+
+// CheckLoginLockout return the waiting duration and the reason if the username or the ip
+// is locked or throttled.
+// 檢查帳號及ip是否被鎖定(或需要等待)，允許登入時回傳0
+func CheckLoginLockout(username, ip string, conn db.Connection) (time.Duration, string) {
+	cfg := config.GetLoginLockout()
+	if !cfg.Enabled() {
+		return 0, ""
+	}
+
+	var (
+		now    = time.Now()
+		wait   time.Duration
+		reason string
+	)
+
+	if cfg.MaxUserFailures > 0 && username != "" {
+		w, locked := models.LoginLockout().SetConn(conn).
+			FindByTarget(models.LockoutTargetUsername, lockoutUsername(username)).Wait(now)
+		if w > wait {
+			wait, reason = w, LoginFailThrottled
+			if locked {
+				reason = LoginFailUserLocked
+			}
+		}
+	}
+
+	if cfg.MaxIPFailures > 0 && ip != "" {
+		w, locked := models.LoginLockout().SetConn(conn).
+			FindByTarget(models.LockoutTargetIP, ip).Wait(now)
+		if w > wait {
+			wait, reason = w, LoginFailThrottled
+			if locked {
+				reason = LoginFailIPLocked
+			}
+		}
+	}
+
+	return wait, reason
+}
+
+// RecordLoginFailure increase the failure counters of the username and the ip, and write the
+// attempt to the operation log.
+// 增加帳號及ip(ClientIP，只採用信任的代理帶入的X-Forwarded-For)的失敗次數，並將失敗原因寫入操作紀錄
+func RecordLoginFailure(ctx *context.Context, username, reason string, conn db.Connection) {
+	cfg := config.GetLoginLockout()
+	if cfg.Enabled() {
+		now := time.Now()
+		if cfg.MaxUserFailures > 0 && username != "" {
+			_, err := models.LoginLockout().SetConn(conn).
+				FindByTarget(models.LockoutTargetUsername, lockoutUsername(username)).
+				AddFailure(cfg.MaxUserFailures, cfg.LockoutTime, cfg.FailureWindow, now)
+			if db.CheckError(err, db.INSERT) {
+				logger.Error("record login failure error", err)
+			}
+		}
+		if ip := ClientIP(ctx); cfg.MaxIPFailures > 0 && ip != "" {
+			_, err := models.LoginLockout().SetConn(conn).
+				FindByTarget(models.LockoutTargetIP, ip).
+				AddFailure(cfg.MaxIPFailures, cfg.LockoutTime, cfg.FailureWindow, now)
+			if db.CheckError(err, db.INSERT) {
+				logger.Error("record login failure error", err)
+			}
+		}
+	}
+
+	LogLoginFailure(ctx, username, reason, conn)
+}
+
+// LogLoginFailure write the failed login attempt to the operation log without increasing
+// the counters.
+// 將登入失敗的帳號及原因寫入操作紀錄(goadmin_operation_log)，不會寫入密碼
+func LogLoginFailure(ctx *context.Context, username, reason string, conn db.Connection) {
+	input, _ := json.Marshal(map[string]string{
+		"username": username,
+		"reason":   reason,
+	})
+	var userId int64
+	if username != "" {
+		userId = models.User().SetConn(conn).FindByUserName(username).Id
+	}
+	models.OperationLog().SetConn(conn).New(userId, ctx.Path(), ctx.Method(), ClientIP(ctx), string(input))
+}
+
+// ResetLoginFailures remove the failure counter of the username after login successfully.
+// 登入成功後清除帳號的失敗次數(ip的失敗次數則在時間範圍後自動重置)
+func ResetLoginFailures(username string, conn db.Connection) {
+	if cfg := config.GetLoginLockout(); cfg.MaxUserFailures <= 0 || username == "" {
+		return
+	}
+	err := models.LoginLockout().SetConn(conn).
+		FindByTarget(models.LockoutTargetUsername, lockoutUsername(username)).
+		Unlock()
+	if db.CheckError(err, db.DELETE) {
+		logger.Error("reset login failures error", err)
+	}
+}
+
+// 帳號不分大小寫計算，避免以大小寫變化繞過限制
+func lockoutUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -57,6 +57,14 @@ func DefaultInvoker(conn db.Connection) *Invoker {
 				}, ``)
 				return
 			}
+			// header裡包含accept:json(例如admin api的請求)或api key，回傳JSON而不是導向登入頁面
+			if ctx.WantJSON() || APIKey(ctx) != "" {
+				ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+					"code": http.StatusUnauthorized,
+					"msg":  language.Get("login overdue, please login again"),
+				})
+				return
+			}
 			param := ""
 			// url後加入參數
 			if ref := ctx.Headers("Referer"); ref != "" {
@@ -98,7 +106,7 @@ func DefaultInvoker(conn db.Connection) *Invoker {
 		},
 		permissionDenyCallback: func(ctx *context.Context) {
 			// constant.PjaxHeade = X-PJAX
-			if ctx.Headers(constant.PjaxHeader) == "" && ctx.Method() != "GET" {
+			if (ctx.Headers(constant.PjaxHeader) == "" && ctx.Method() != "GET") || ctx.WantJSON() || APIKey(ctx) != "" {
 				// 轉換成JSON存至Context.Response.body
 				ctx.JSON(http.StatusForbidden, map[string]interface{}{
 					"code": http.StatusForbidden,
@@ -186,6 +194,42 @@ func Filter(ctx *context.Context, conn db.Connection) (models.UserModel, bool, b
 		user = models.User()
 	)
 
+	// header中帶有api key時，以服務帳號的api key驗證，權限只有api key綁定的權限
+	if key := APIKey(ctx); key != "" {
+		if user, ok = GetAPIKeyUser(ctx, key, conn); !ok {
+			return user, false, false
+		}
+
+		ctx.SetUserValue(bearerAuthKey, true)
+
+		user, ok = checkAccess(ctx, user, conn)
+
+		return user, true, ok
+	}
+
+	// 有設置auth_token_key且header中帶有Authorization: Bearer時，改以token驗證而不使用cookie
+	if token := BearerToken(ctx); token != "" && TokenEnabled() {
+		claims, err := ParseToken(config.GetAuthTokenKey(), token)
+		if err == nil {
+			// 密碼、角色變更或撤銷session後，舊版本的token失效
+			err = CheckTokenVersion(claims, conn)
+		}
+		if err != nil {
+			logger.Error("retrieve auth user from token failed", err)
+			return user, false, false
+		}
+
+		if user, ok = GetCurUserByID(claims.Uid, conn); !ok {
+			return user, false, false
+		}
+
+		ctx.SetUserValue(bearerAuthKey, true)
+
+		user, ok = checkAccess(ctx, user, conn)
+
+		return user, true, ok
+	}
+
 	// 設置Session(struct)資訊並取得cookie及設置cookie值
 	ses, err := InitSession(ctx, conn)
 
@@ -207,8 +251,18 @@ func Filter(ctx *context.Context, conn db.Connection) (models.UserModel, bool, b
 		return user, false, false
 	}
 
-	// CheckPermissions透過path、method、param檢查用戶權限
-	return user, true, CheckPermissions(user, ctx.Request.URL.String(), ctx.Method(), ctx.PostForm())
+	// 超級管理員以此用戶身分登入
+	if user, ok = withImpersonator(ses, user, conn); !ok {
+		return user, false, false
+	}
+
+	// 更新session的最後活動時間
+	ses.touch()
+
+	// 依ip存取規則及path、method、param檢查用戶權限
+	user, ok = checkAccess(ctx, user, conn)
+
+	return user, true, ok
 }
 
 const defaultUserIDSesKey = "user_id"
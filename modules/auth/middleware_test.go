@@ -5,14 +5,24 @@ import (
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/stretchr/testify/assert"
 	"net/url"
+	"sync"
 	"testing"
 )
 
-func TestCheckPermissions(t *testing.T) {
+var setTestConfigOnce sync.Once
 
-	config.Set(config.Config{
-		UrlPrefix: "admin",
+// 設置測試用的config(只能設置一次)
+func setTestConfig() {
+	setTestConfigOnce.Do(func() {
+		config.Set(config.Config{
+			UrlPrefix: "admin",
+		})
 	})
+}
+
+func TestCheckPermissions(t *testing.T) {
+
+	setTestConfig()
 
 	user := models.UserModel{
 		Permissions: []models.PermissionModel{
@@ -80,3 +90,32 @@ func TestCheckPermissions(t *testing.T) {
 	assert.Equal(t, CheckPermissions(user, "/admin/info/user_list?__goadmin_edit_pk=3&user_type=20", "get", param), true)
 	assert.Equal(t, CheckPermissions(user, "/admin/delete/user", "post", param), true)
 }
+
+func TestCheckPermissionsSuperAdminDeny(t *testing.T) {
+
+	setTestConfig()
+
+	user := models.UserModel{
+		Permissions: []models.PermissionModel{
+			{
+				Name:       "*",
+				Slug:       "*",
+				HttpMethod: []string{""},
+				HttpPath:   []string{"*"},
+			}, {
+				Name:       "deny managers",
+				Slug:       "deny_managers",
+				HttpMethod: []string{""},
+				HttpPath:   []string{"!/info/manager"},
+			},
+		},
+	}
+
+	param := make(url.Values)
+
+	assert.Equal(t, user.IsSuperAdmin(), true)
+	assert.Equal(t, CheckPermissions(user, "/admin/info/user", "GET", param), true)
+	// the deny rules are evaluated before the super administrator
+	assert.Equal(t, CheckPermissions(user, "/admin/info/manager", "GET", param), false)
+	assert.Equal(t, CheckPermissions(user, "/admin/logout", "POST", param), true)
+}
@@ -0,0 +1,543 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/utils"
+)
+
+const (
+	// session中oidc登入流程的資料，只能使用一次
+	oidcProviderSesKey = "oidc_provider"
+	oidcStateSesKey    = "oidc_state"
+	oidcNonceSesKey    = "oidc_nonce"
+	oidcVerifierSesKey = "oidc_verifier"
+	oidcRefSesKey      = "oidc_ref"
+	oidcExpireSesKey   = "oidc_expire_at"
+	// 從跳轉至provider到callback的時間限制
+	oidcLifeTime = 10 * time.Minute
+	// provider設置及公鑰的快取時間
+	oidcCacheTime = time.Hour
+	// 驗證時間時允許的誤差
+	oidcLeeway = time.Minute
+)
+
+// Errors of the OpenID Connect login.
+var (
+	ErrOIDCState        = errors.New("oidc: invalid state")
+	ErrOIDCInvalidToken = errors.New("oidc: invalid id token")
+)
+
+var oidcClient = &http.Client{Timeout: 10 * time.Second}
+
+// OIDCDiscovery is the provider metadata of /.well-known/openid-configuration.
+type OIDCDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+type oidcDiscoveryItem struct {
+	discovery OIDCDiscovery
+	expireAt  time.Time
+}
+
+type oidcKeysItem struct {
+	keys     map[string]crypto.PublicKey
+	expireAt time.Time
+}
+
+var oidcCache = struct {
+	lock      sync.Mutex
+	discovery map[string]oidcDiscoveryItem
+	keys      map[string]oidcKeysItem
+}{
+	discovery: make(map[string]oidcDiscoveryItem),
+	keys:      make(map[string]oidcKeysItem),
+}
+
+// DiscoverOIDC return the provider metadata of the issuer, the result is cached.
+// 取得issuer的/.well-known/openid-configuration並快取
+func DiscoverOIDC(issuer string) (OIDCDiscovery, error) {
+	oidcCache.lock.Lock()
+	item, ok := oidcCache.discovery[issuer]
+	oidcCache.lock.Unlock()
+	if ok && item.expireAt.After(time.Now()) {
+		return item.discovery, nil
+	}
+
+	var discovery OIDCDiscovery
+	if err := oidcGetJSON(strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return discovery, err
+	}
+	if discovery.Issuer != issuer {
+		return discovery, fmt.Errorf("oidc: issuer did not match, expected %s got %s", issuer, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JwksURI == "" {
+		return discovery, errors.New("oidc: incomplete provider metadata")
+	}
+
+	oidcCache.lock.Lock()
+	oidcCache.discovery[issuer] = oidcDiscoveryItem{discovery: discovery, expireAt: time.Now().Add(oidcCacheTime)}
+	oidcCache.lock.Unlock()
+	return discovery, nil
+}
+
+// 取得jwks_uri的公鑰，refresh為true時不使用快取(provider更換金鑰時)
+func oidcKeys(jwksURI string, refresh bool) (map[string]crypto.PublicKey, error) {
+	oidcCache.lock.Lock()
+	item, ok := oidcCache.keys[jwksURI]
+	oidcCache.lock.Unlock()
+	if ok && !refresh && item.expireAt.After(time.Now()) {
+		return item.keys, nil
+	}
+
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := oidcGetJSON(jwksURI, &set); err != nil {
+		return nil, err
+	}
+	keys, err := ParseJWKS(set.Keys)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcCache.lock.Lock()
+	oidcCache.keys[jwksURI] = oidcKeysItem{keys: keys, expireAt: time.Now().Add(oidcCacheTime)}
+	oidcCache.lock.Unlock()
+	return keys, nil
+}
+
+func oidcGetJSON(u string, v interface{}) error {
+	res, err := oidcClient.Get(u)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	return oidcDecodeResponse(res, v)
+}
+
+func oidcDecodeResponse(res *http.Response, v interface{}) error {
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("oidc: %s: %s", res.Status, body)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// ParseJWKS parse the keys of a JSON Web Key Set, the keys are indexed by kid. The keys
+// for encryption and the unsupported types are ignored.
+// 解析JWKS，支援RSA及EC(P-256、P-384、P-521)金鑰
+func ParseJWKS(list []json.RawMessage) (map[string]crypto.PublicKey, error) {
+	keys := make(map[string]crypto.PublicKey)
+	for i, raw := range list {
+		var jwk struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}
+		if err := json.Unmarshal(raw, &jwk); err != nil {
+			return nil, err
+		}
+		if jwk.Use == "enc" {
+			continue
+		}
+		kid := jwk.Kid
+		if kid == "" {
+			kid = fmt.Sprintf("#%d", i)
+		}
+
+		switch jwk.Kty {
+		case "RSA":
+			n, err1 := base64.RawURLEncoding.DecodeString(jwk.N)
+			e, err2 := base64.RawURLEncoding.DecodeString(jwk.E)
+			if err1 != nil || err2 != nil || len(e) == 0 || len(e) > 4 {
+				return nil, errors.New("oidc: invalid rsa key " + kid)
+			}
+			keys[kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch jwk.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, err1 := base64.RawURLEncoding.DecodeString(jwk.X)
+			y, err2 := base64.RawURLEncoding.DecodeString(jwk.Y)
+			if err1 != nil || err2 != nil {
+				return nil, errors.New("oidc: invalid ec key " + kid)
+			}
+			key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+			if !curve.IsOnCurve(key.X, key.Y) {
+				return nil, errors.New("oidc: invalid ec key " + kid)
+			}
+			keys[kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// VerifyIDToken verify the signature and the claims(iss, sub, aud, exp, nonce) of the id token,
+// only the asymmetric algorithms(RS256/384/512, ES256/384/512) are accepted.
+// 驗證id token的簽章及iss、sub、aud、exp、nonce，只接受非對稱的簽章演算法
+func VerifyIDToken(token string, keys map[string]crypto.PublicKey, issuer, clientID, nonce string, now time.Time) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	candidates := keys
+	if header.Kid != "" {
+		key, ok := keys[header.Kid]
+		if !ok {
+			return nil, ErrOIDCInvalidToken
+		}
+		candidates = map[string]crypto.PublicKey{header.Kid: key}
+	}
+
+	verified := false
+	for _, key := range candidates {
+		if verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	var claims map[string]interface{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	if iss, _ := claims["iss"].(string); iss != issuer {
+		return nil, ErrOIDCInvalidToken
+	}
+	if sub, _ := claims["sub"].(string); sub == "" {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	var aud []string
+	switch v := claims["aud"].(type) {
+	case string:
+		aud = []string{v}
+	case []interface{}:
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				aud = append(aud, s)
+			}
+		}
+	}
+	if !utils.InArray(aud, clientID) {
+		return nil, ErrOIDCInvalidToken
+	}
+	if azp, ok := claims["azp"].(string); ok && azp != clientID {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok || time.Unix(int64(exp), 0).Add(oidcLeeway).Before(now) {
+		return nil, ErrOIDCInvalidToken
+	}
+	if iat, ok := claims["iat"].(float64); ok && time.Unix(int64(iat), 0).After(now.Add(oidcLeeway)) {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	if n, _ := claims["nonce"].(string); subtle.ConstantTimeCompare([]byte(n), []byte(nonce)) != 1 {
+		return nil, ErrOIDCInvalidToken
+	}
+
+	return claims, nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed string, sig []byte) bool {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		return false
+	}
+	h := hash.New()
+	_, _ = h.Write([]byte(signed))
+	digest := h.Sum(nil)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(alg, "RS") && rsa.VerifyPKCS1v15(k, hash, digest, sig) == nil
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(sig) != 2*size {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(sig[:size]), new(big.Int).SetBytes(sig[size:])
+		return ecdsa.Verify(k, digest, r, s)
+	}
+	return false
+}
+
+// OIDCAuthURL start the authorization code flow with PKCE, the state, the nonce and the
+// code verifier are saved in the session, return the url of the authorization endpoint.
+// 產生state、nonce及PKCE的code verifier並存至session，回傳跳轉至provider的url
+func OIDCAuthURL(ctx *context.Context, p config.OIDCProvider, redirectURL, ref string, conn db.Connection) (string, error) {
+	discovery, err := DiscoverOIDC(p.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		state    = oidcRandom()
+		nonce    = oidcRandom()
+		verifier = oidcRandom()
+	)
+
+	ses.Values[oidcProviderSesKey] = p.Name
+	ses.Values[oidcStateSesKey] = state
+	ses.Values[oidcNonceSesKey] = nonce
+	ses.Values[oidcRefSesKey] = ref
+	ses.Values[oidcExpireSesKey] = time.Now().Add(oidcLifeTime).Unix()
+	if err := ses.Add(oidcVerifierSesKey, verifier); err != nil {
+		return "", err
+	}
+
+	params := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.ClientID},
+		"redirect_uri":          {redirectURL},
+		"scope":                 {strings.Join(p.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {PKCEChallenge(verifier)},
+		"code_challenge_method": {"S256"},
+	}
+
+	sep := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return discovery.AuthorizationEndpoint + sep + params.Encode(), nil
+}
+
+// OIDCExchange check the state of the callback, exchange the code for the tokens and
+// return the verified claims of the id token and the ref saved by OIDCAuthURL.
+// 檢查callback的state後以code及code verifier換取token，回傳驗證後的id token claims
+func OIDCExchange(ctx *context.Context, p config.OIDCProvider, redirectURL string, conn db.Connection) (map[string]interface{}, string, error) {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var (
+		provider, _ = ses.Get(oidcProviderSesKey).(string)
+		state, _    = ses.Get(oidcStateSesKey).(string)
+		nonce, _    = ses.Get(oidcNonceSesKey).(string)
+		verifier, _ = ses.Get(oidcVerifierSesKey).(string)
+		ref, _      = ses.Get(oidcRefSesKey).(string)
+		expireAt, _ = ses.Get(oidcExpireSesKey).(float64)
+	)
+
+	// state只能使用一次
+	for _, key := range []string{oidcProviderSesKey, oidcStateSesKey, oidcNonceSesKey,
+		oidcVerifierSesKey, oidcRefSesKey, oidcExpireSesKey} {
+		delete(ses.Values, key)
+	}
+	if err := ses.Driver.Update(ses.Sid, ses.Values); err != nil {
+		return nil, "", err
+	}
+
+	if provider != p.Name || state == "" || int64(expireAt) < time.Now().Unix() ||
+		subtle.ConstantTimeCompare([]byte(state), []byte(ctx.Query("state"))) != 1 {
+		return nil, "", ErrOIDCState
+	}
+	if e := ctx.Query("error"); e != "" {
+		return nil, "", fmt.Errorf("oidc: %s: %s", e, ctx.Query("error_description"))
+	}
+
+	discovery, err := DiscoverOIDC(p.Issuer)
+	if err != nil {
+		return nil, "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {ctx.Query("code")},
+		"redirect_uri":  {redirectURL},
+		"client_id":     {p.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret))
+	}
+
+	res, err := oidcClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := oidcDecodeResponse(res, &tokens); err != nil {
+		return nil, "", err
+	}
+
+	keys, err := oidcKeys(discovery.JwksURI, false)
+	if err != nil {
+		return nil, "", err
+	}
+	claims, err := VerifyIDToken(tokens.IDToken, keys, discovery.Issuer, p.ClientID, nonce, time.Now())
+	if err == ErrOIDCInvalidToken {
+		// provider可能已更換金鑰，重新取得公鑰後再驗證一次
+		if keys, err = oidcKeys(discovery.JwksURI, true); err != nil {
+			return nil, "", err
+		}
+		claims, err = VerifyIDToken(tokens.IDToken, keys, discovery.Issuer, p.ClientID, nonce, time.Now())
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return claims, ref, nil
+}
+
+// OIDCClaim return the string values of the claim, the name can be a path separated by
+// dots, e.g. realm_access.roles.
+// 取得claim的值(字串或字串陣列)，name可以用.取得巢狀的值
+func OIDCClaim(claims map[string]interface{}, name string) []string {
+	var v interface{} = claims
+	for _, key := range strings.Split(name, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+
+	switch value := v.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		var list []string
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	}
+	return nil
+}
+
+// OIDCIdentityProvider return the provider of the identities authenticated by the provider,
+// the identity is the sub claim of the id token.
+// 回傳外部身分的來源(oidc:{name})，身分為id token的sub
+func OIDCIdentityProvider(p config.OIDCProvider) string {
+	return "oidc:" + p.Name
+}
+
+// OIDCRoles return the role slugs of the claims by the rules of the provider.
+// 依ClaimRoles將RoleClaim的值轉換成角色(slug)，沒有任何對應時回傳DefaultRoles
+func OIDCRoles(p config.OIDCProvider, claims map[string]interface{}) []string {
+	var roles []string
+	if p.RoleClaim != "" {
+		values := OIDCClaim(claims, p.RoleClaim)
+		for _, rule := range p.ClaimRoles {
+			if utils.InArray(values, rule.Value) && !utils.InArray(roles, rule.Role) {
+				roles = append(roles, rule.Role)
+			}
+		}
+	}
+	if len(roles) == 0 {
+		roles = append(roles, p.DefaultRoles...)
+	}
+	return roles
+}
+
+// PKCEChallenge return the S256 code challenge of the verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func oidcRandom() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
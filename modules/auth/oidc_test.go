@@ -0,0 +1,169 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func signTestJWT(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		assert.Equal(t, err, nil)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		assert.Equal(t, err, nil)
+		sig = append(padBytes(r, 32), padBytes(s, 32)...)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func padBytes(n *big.Int, size int) []byte {
+	b := n.Bytes()
+	return append(make([]byte, size-len(b)), b...)
+}
+
+func TestPKCEChallenge(t *testing.T) {
+	// RFC 7636 Appendix B
+	assert.Equal(t, PKCEChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"), "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM")
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	jwks, _ := json.Marshal([]map[string]string{{
+		"kty": "RSA", "kid": "rsa", "use": "sig",
+		"n": base64.RawURLEncoding.EncodeToString(rsaKey.N.Bytes()),
+		"e": base64.RawURLEncoding.EncodeToString(big.NewInt(int64(rsaKey.E)).Bytes()),
+	}, {
+		"kty": "EC", "kid": "ec", "crv": "P-256",
+		"x": base64.RawURLEncoding.EncodeToString(padBytes(ecKey.X, 32)),
+		"y": base64.RawURLEncoding.EncodeToString(padBytes(ecKey.Y, 32)),
+	}, {
+		"kty": "RSA", "kid": "enc", "use": "enc",
+	}})
+	var list []json.RawMessage
+	assert.Equal(t, json.Unmarshal(jwks, &list), nil)
+	keys, err := ParseJWKS(list)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(keys), 2)
+
+	now := time.Now()
+	claims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   "https://sso.example.com",
+			"aud":   "goadmin",
+			"exp":   now.Add(time.Minute).Unix(),
+			"iat":   now.Unix(),
+			"nonce": "n-0S6_WzA2Mj",
+			"sub":   "248289761001",
+		}
+	}
+	verify := func(token string) error {
+		_, err := VerifyIDToken(token, keys, "https://sso.example.com", "goadmin", "n-0S6_WzA2Mj", now)
+		return err
+	}
+
+	assert.Equal(t, verify(signTestJWT(t, "RS256", "rsa", rsaKey, claims())), nil)
+	assert.Equal(t, verify(signTestJWT(t, "ES256", "ec", ecKey, claims())), nil)
+	assert.Equal(t, verify(signTestJWT(t, "RS256", "", rsaKey, claims())), nil)
+
+	// wrong key, algorithm or kid
+	assert.Equal(t, verify(signTestJWT(t, "RS256", "ec", rsaKey, claims())), ErrOIDCInvalidToken)
+	assert.Equal(t, verify(signTestJWT(t, "ES256", "rsa", ecKey, claims())), ErrOIDCInvalidToken)
+	assert.Equal(t, verify(signTestJWT(t, "RS256", "unknown", rsaKey, claims())), ErrOIDCInvalidToken)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Equal(t, verify(signTestJWT(t, "RS256", "rsa", otherKey, claims())), ErrOIDCInvalidToken)
+
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+	payload, _ := json.Marshal(claims())
+	assert.Equal(t, verify(header+"."+base64.RawURLEncoding.EncodeToString(payload)+"."), ErrOIDCInvalidToken)
+
+	for key, value := range map[string]interface{}{
+		"iss":   "https://evil.example.com",
+		"aud":   []string{"others"},
+		"exp":   now.Add(-2 * time.Minute).Unix(),
+		"iat":   now.Add(time.Hour).Unix(),
+		"nonce": "replayed",
+		"azp":   "others",
+		"sub":   "",
+	} {
+		c := claims()
+		c[key] = value
+		assert.Equal(t, verify(signTestJWT(t, "RS256", "rsa", rsaKey, c)), ErrOIDCInvalidToken, key)
+	}
+
+	c := claims()
+	c["aud"] = []string{"others", "goadmin"}
+	c["azp"] = "goadmin"
+	assert.Equal(t, verify(signTestJWT(t, "RS256", "rsa", rsaKey, c)), nil)
+}
+
+func TestOIDCRoles(t *testing.T) {
+	p := config.OIDCProvider{
+		RoleClaim: "realm_access.roles",
+		ClaimRoles: []config.OIDCClaimRole{
+			{Value: "goadmin-admin", Role: "administrator"},
+			{Value: "goadmin-operator", Role: "operator"},
+		},
+		DefaultRoles: []string{"visitor"},
+	}
+	claims := map[string]interface{}{
+		"groups": "goadmin-admin",
+		"realm_access": map[string]interface{}{
+			"roles": []interface{}{"goadmin-operator", "offline_access"},
+		},
+	}
+
+	assert.Equal(t, OIDCClaim(claims, "groups"), []string{"goadmin-admin"})
+	assert.Equal(t, OIDCRoles(p, claims), []string{"operator"})
+
+	p.RoleClaim = "groups"
+	assert.Equal(t, OIDCRoles(p, claims), []string{"administrator"})
+
+	p.RoleClaim = "missing.claim"
+	assert.Equal(t, OIDCRoles(p, claims), []string{"visitor"})
+}
+
+func TestDiscoverOIDC(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.Path, "/.well-known/openid-configuration")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/auth",
+			"token_endpoint":         server.URL + "/token",
+			"jwks_uri":               server.URL + "/keys",
+		})
+	}))
+	defer server.Close()
+
+	discovery, err := DiscoverOIDC(server.URL)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, discovery.TokenEndpoint, server.URL+"/token")
+
+	_, err = DiscoverOIDC(server.URL + "/")
+	assert.NotEqual(t, err, nil)
+}
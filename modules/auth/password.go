@@ -0,0 +1,182 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+const (
+	// session中等待修改過期密碼的用戶id
+	passwordPendingSesKey = "password_change_user_id"
+	// session中等待修改過期密碼的過期時間
+	passwordPendingExpireSesKey = "password_change_expire_at"
+	// 修改過期密碼的時間限制
+	passwordPendingLifeTime = 10 * time.Minute
+)
+
+// PasswordPolicyError is the error of the password which does not satisfy the policy,
+// the Msg is a key of the language package.
+type PasswordPolicyError struct {
+	Msg  string
+	Args []interface{}
+}
+
+// Error return the message localized by the language package.
+func (e PasswordPolicyError) Error() string {
+	return e.Localize(language.Get)
+}
+
+// Localize return the message localized by the given function.
+// 以參數get翻譯訊息，例如adm使用自己的語言設置
+func (e PasswordPolicyError) Localize(get func(string) string) string {
+	return fmt.Sprintf(get(e.Msg), e.Args...)
+}
+
+// CheckPasswordStrength check the length and the character classes of the password.
+// 檢查密碼長度及必須包含的字元類型
+func CheckPasswordStrength(policy config.PasswordPolicy, password string) error {
+	if len([]rune(password)) < policy.MinLength {
+		return PasswordPolicyError{Msg: "password must be at least %d characters", Args: []interface{}{policy.MinLength}}
+	}
+
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	if policy.RequireUpper && !upper {
+		return PasswordPolicyError{Msg: "password must contain an uppercase letter"}
+	}
+	if policy.RequireLower && !lower {
+		return PasswordPolicyError{Msg: "password must contain a lowercase letter"}
+	}
+	if policy.RequireDigit && !digit {
+		return PasswordPolicyError{Msg: "password must contain a digit"}
+	}
+	if policy.RequireSymbol && !symbol {
+		return PasswordPolicyError{Msg: "password must contain a symbol"}
+	}
+	return nil
+}
+
+// CheckPasswordPolicy check the new password of the user with the configured policy, userId
+// is zero for a new user.
+// 以設置的密碼規則檢查用戶的新密碼，包含強度以及是否與最近的密碼重複(新用戶userId為0)
+func CheckPasswordPolicy(userId int64, password string, conn db.Connection) error {
+	policy := config.GetPasswordPolicy()
+
+	if err := CheckPasswordStrength(policy, password); err != nil {
+		return err
+	}
+
+	if policy.HistorySize <= 0 || userId == 0 {
+		return nil
+	}
+
+	hashes := []string{models.User().SetConn(conn).Find(userId).Password}
+	for i, item := range models.PasswordHistory().SetConn(conn).FindByUserId(userId) {
+		if i >= policy.HistorySize {
+			break
+		}
+		hashes = append(hashes, item.Password)
+	}
+	for _, hash := range hashes {
+		if hash != "" && comparePassword(password, hash) {
+			return PasswordPolicyError{Msg: "password can not be the same as the last %d passwords",
+				Args: []interface{}{policy.HistorySize}}
+		}
+	}
+	return nil
+}
+
+// SavePasswordHistory record the password change of the user.
+// 記錄用戶的密碼修改，用於判斷密碼重複及過期
+func SavePasswordHistory(userId int64, hash string, conn db.Connection) {
+	_, err := models.PasswordHistory().SetConn(conn).New(userId, hash, config.GetPasswordPolicy().HistorySize)
+	if db.CheckError(err, db.INSERT) {
+		logger.Error("save password history error", err)
+	}
+}
+
+// PasswordExpired check the password of the user is older than the max age. The time is
+// counted from now for the user without any history.
+// 判斷用戶密碼是否超過有效天數，沒有修改紀錄的用戶(例如開啟設置前建立的用戶)從現在開始計算
+func PasswordExpired(user models.UserModel, conn db.Connection) bool {
+	policy := config.GetPasswordPolicy()
+	if policy.MaxAge <= 0 {
+		return false
+	}
+
+	list := models.PasswordHistory().SetConn(conn).FindByUserId(user.Id)
+	if len(list) == 0 {
+		SavePasswordHistory(user.Id, user.Password, conn)
+		return false
+	}
+
+	return time.Unix(list[0].ChangedAt, 0).AddDate(0, 0, policy.MaxAge).Before(time.Now())
+}
+
+// SetPasswordChangePending mark the user of the session as waiting for changing the expired password.
+// 密碼過期時先不登入，將用戶id存至session，等待修改密碼
+func SetPasswordChangePending(ctx *context.Context, user models.UserModel, conn db.Connection) error {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return err
+	}
+	delete(ses.Values, defaultUserIDSesKey)
+	delete(ses.Values, totpPendingSesKey)
+	delete(ses.Values, totpPendingExpireSesKey)
+	delete(ses.Values, totpPendingFailSesKey)
+	ses.Values[passwordPendingExpireSesKey] = time.Now().Add(passwordPendingLifeTime).Unix()
+	return ses.Add(passwordPendingSesKey, user.Id)
+}
+
+// GetPasswordChangePendingUser return the user waiting for changing the expired password.
+// 取得session中等待修改密碼的用戶
+func GetPasswordChangePendingUser(ctx *context.Context, conn db.Connection) (models.UserModel, bool) {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return models.User(), false
+	}
+	id, ok := ses.Get(passwordPendingSesKey).(float64)
+	if !ok {
+		return models.User(), false
+	}
+	if expireAt, _ := ses.Get(passwordPendingExpireSesKey).(float64); int64(expireAt) < time.Now().Unix() {
+		return models.User(), false
+	}
+	return GetCurUserByID(int64(id), conn)
+}
+
+// FinishPasswordChangePending login the user after the password is changed.
+// 修改密碼成功，清除等待狀態並登入
+func FinishPasswordChangePending(ctx *context.Context, user models.UserModel, conn db.Connection) error {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return err
+	}
+	delete(ses.Values, passwordPendingSesKey)
+	delete(ses.Values, passwordPendingExpireSesKey)
+	return ses.Add(defaultUserIDSesKey, user.Id)
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPasswordStrength(t *testing.T) {
+	policy := config.PasswordPolicy{
+		MinLength:     8,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+	}
+
+	for password, msg := range map[string]string{
+		"Ab1!":     "password must be at least %d characters",
+		"abcdef1!": "password must contain an uppercase letter",
+		"ABCDEF1!": "password must contain a lowercase letter",
+		"Abcdefg!": "password must contain a digit",
+		"Abcdefg1": "password must contain a symbol",
+		"Abcdef1!": "",
+		"密碼Abcd1 ": "",
+	} {
+		err := CheckPasswordStrength(policy, password)
+		if msg == "" {
+			assert.Equal(t, err, nil)
+			continue
+		}
+		assert.Equal(t, err.(PasswordPolicyError).Msg, msg)
+	}
+
+	assert.Equal(t, CheckPasswordStrength(config.PasswordPolicy{}, "a"), nil)
+}
+
+func TestPasswordPolicyError_Localize(t *testing.T) {
+	err := PasswordPolicyError{Msg: "password must be at least %d characters", Args: []interface{}{8}}
+	assert.Equal(t, err.Localize(func(s string) string { return s }), "password must be at least 8 characters")
+}
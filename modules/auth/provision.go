@@ -0,0 +1,125 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	errs "github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+// ErrIdentityNotLinked is returned when the user of the username exists but is not linked to
+// the external identity.
+var ErrIdentityNotLinked = errors.New("user exists and is not linked to the identity")
+
+// ExternalIdentity is the identity authenticated by the external service.
+// 外部驗證的身分，Provider為驗證來源(例如ldap、oidc:google)，Subject為該來源中不變的唯一識別(LDAP DN、OIDC sub)
+// Username、Name只用於建立新用戶，不用於比對已存在的用戶
+type ExternalIdentity struct {
+	Provider string
+	Subject  string
+	Username string
+	Name     string
+}
+
+// LinkIdentity bind the external identity to the existing user, the user can login by the
+// identity after that.
+// 將外部身分綁定至已存在的用戶(例如本地帳號改為使用單一登入)，需由管理者明確執行
+func LinkIdentity(userId int64, provider, subject string, conn db.Connection) error {
+	_, err := models.UserIdentity().SetConn(conn).Link(userId, provider, subject)
+	if db.CheckError(err, db.INSERT) {
+		return err
+	}
+	return nil
+}
+
+// ProvisionUser return the user linked to the identity authenticated by the external
+// service(LDAP, OpenID Connect...), the user is created and linked if not exists, and the roles
+// of the new user(or every login when syncRoles is true) are set.
+// 以Provider及Subject尋找綁定的用戶，不存在時建立用戶(密碼為隨機值，無法以本地密碼登入)並綁定，並以角色(slug)設置角色
+// 用戶名稱已被未綁定的用戶使用時拒絕登入，避免外部身分登入成同名的本地帳號(例如admin)
+// 沒有任何角色的新用戶無法登入
+func ProvisionUser(identity ExternalIdentity, slugs []string, syncRoles bool, conn db.Connection) (models.UserModel, error) {
+	if identity.Provider == "" || identity.Subject == "" {
+		return models.User(), errors.New(errs.NoPermission)
+	}
+
+	var (
+		link  = models.UserIdentity().SetConn(conn).FindBySubject(identity.Provider, identity.Subject)
+		user  = models.User().SetConn(conn)
+		isNew = true
+	)
+
+	if !link.IsEmpty() {
+		user = user.Find(link.UserId)
+		isNew = user.IsEmpty()
+		// 綁定的用戶已被刪除
+		if isNew {
+			if err := link.Delete(); db.CheckError(err, db.DELETE) {
+				return user, err
+			}
+		}
+	}
+
+	if isNew && !models.User().SetConn(conn).FindByUserName(identity.Username).IsEmpty() {
+		return user, ErrIdentityNotLinked
+	}
+
+	if !isNew && !syncRoles {
+		return user, nil
+	}
+
+	var roleIds []string
+	for _, slug := range slugs {
+		role := models.Role().SetConn(conn).FindBySlug(slug)
+		if role.IsEmpty() {
+			logger.Warn("role not found: ", slug)
+			continue
+		}
+		roleIds = append(roleIds, strconv.FormatInt(role.Id, 10))
+	}
+	if len(roleIds) == 0 {
+		return user, errors.New(errs.NoPermission)
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = identity.Username
+	}
+
+	_, txErr := db.WithDriver(conn).WithTransaction(func(tx *sql.Tx) (error, map[string]interface{}) {
+		if isNew {
+			var err error
+			user, err = models.User().WithTx(tx).SetConn(conn).New(identity.Username,
+				EncodePassword([]byte(GenerateTOTPSecret())), name, "")
+			if db.CheckError(err, db.INSERT) {
+				return err, nil
+			}
+			_, err = models.UserIdentity().WithTx(tx).SetConn(conn).Link(user.Id, identity.Provider, identity.Subject)
+			if db.CheckError(err, db.INSERT) {
+				return err, nil
+			}
+		} else if err := user.WithTx(tx).DeleteRoles(); db.CheckError(err, db.DELETE) {
+			return err, nil
+		}
+
+		for _, id := range roleIds {
+			if _, err := user.WithTx(tx).AddRole(id); db.CheckError(err, db.INSERT) {
+				return err, nil
+			}
+		}
+		return nil, nil
+	})
+	if txErr != nil {
+		return user, txErr
+	}
+
+	return user.WithTx(nil), nil
+}
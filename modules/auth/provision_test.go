@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/sqlite"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// 複製測試用的sqlite資料庫，回傳連線及清除的函式
+func testSqliteConn(t *testing.T) (db.Connection, func()) {
+	setTestConfig()
+	data, err := ioutil.ReadFile("../../tests/data/admin.db")
+	assert.Equal(t, err, nil)
+	dir, err := ioutil.TempDir("", "goadmin")
+	assert.Equal(t, err, nil)
+	file := filepath.Join(dir, "admin.db")
+	assert.Equal(t, ioutil.WriteFile(file, data, os.ModePerm), nil)
+	conn := db.GetConnectionByDriver(db.DriverSqlite).InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: file},
+	})
+	return conn, func() {
+		conn.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestProvisionUser(t *testing.T) {
+	conn, cleanup := testSqliteConn(t)
+	defer cleanup()
+
+	// the identity with the username of the local user is not bound to the user
+	_, err := ProvisionUser(ExternalIdentity{Provider: "oidc:test", Subject: "1001", Username: "admin"},
+		[]string{"operator"}, false, conn)
+	assert.Equal(t, err, ErrIdentityNotLinked)
+
+	user, err := ProvisionUser(ExternalIdentity{Provider: "oidc:test", Subject: "1002", Username: "jane"},
+		[]string{"operator"}, false, conn)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, user.UserName, "jane")
+
+	// the user is found by the subject even if the username is changed
+	same, err := ProvisionUser(ExternalIdentity{Provider: "oidc:test", Subject: "1002", Username: "jane.doe"},
+		[]string{"operator"}, false, conn)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, same.Id, user.Id)
+
+	// the same subject of another provider is another identity
+	_, err = ProvisionUser(ExternalIdentity{Provider: "oidc:other", Subject: "1002", Username: "jane"},
+		[]string{"operator"}, false, conn)
+	assert.Equal(t, err, ErrIdentityNotLinked)
+
+	// the local user can login by the identity after linked explicitly
+	assert.Equal(t, LinkIdentity(1, "oidc:test", "1001", conn), nil)
+	admin, err := ProvisionUser(ExternalIdentity{Provider: "oidc:test", Subject: "1001", Username: "someone"},
+		[]string{"operator"}, false, conn)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, admin.Id, int64(1))
+
+	_, err = ProvisionUser(ExternalIdentity{Provider: "oidc:test", Username: "admin"}, []string{"operator"}, false, conn)
+	assert.NotEqual(t, err, nil)
+
+	assert.Equal(t, models.UserIdentity().SetConn(conn).FindBySubject("oidc:test", "1002").UserId, user.Id)
+}
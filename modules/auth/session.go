@@ -14,6 +14,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -39,9 +40,9 @@ type PersistenceDriver interface {
 // GetSessionByKey get the session value by key.
 // 尋找資料表中符合參數(sesKey)的user資料，將資料的values欄位值JSON解碼並回傳values(map)key鍵的值
 func GetSessionByKey(sesKey, key string, conn db.Connection) (interface{}, error) {
-	// newDBDriver將參數(conn)設置並回傳DBDriver(struct)
-	// 尋找資料表中符合參數(sesKey)的user資料，將資料的values欄位值JSON解碼並回傳values
-	m, err := newDBDriver(conn).Load(sesKey)
+	// newSessionDriver依照設置回傳session的儲存驅動
+	// 尋找符合參數(sesKey)的user資料，將資料的values欄位值JSON解碼並回傳values
+	m, err := newSessionDriver(conn).Load(sesKey)
 	return m[key], err
 }
 
@@ -86,6 +87,8 @@ func (ses *Session) Add(key string, value interface{}) error {
 	if err := ses.Driver.Update(ses.Sid, ses.Values); err != nil {
 		return err
 	}
+	// 記錄session的用戶、IP及瀏覽器資訊
+	ses.recordMeta()
 	cookie := http.Cookie{
 		Name:     ses.Cookie,
 		Value:    ses.Sid,
@@ -152,15 +155,67 @@ func InitSession(ctx *context.Context, conn db.Connection) (*Session, error) {
 		Cookie:  DefaultCookieKey,
 	})
 
-	// UseDriver透過參數(newDBDriver(conn))設置Session.Driver
-	// newDBDriver透過參數(conn)回傳DBDriver(struct)
-	sessions.UseDriver(newDBDriver(conn))
+	// UseDriver透過參數(newSessionDriver(conn))設置Session.Driver
+	// newSessionDriver依照config的session_store設置回傳對應的PersistenceDriver
+	sessions.UseDriver(newSessionDriver(conn))
 	sessions.Values = make(map[string]interface{})
 
 	// 取得cookie並設置值，接著設定Session(struct)資訊，將參數ctx設置至Session.Context
 	return sessions.StartCtx(ctx)
 }
 
+const (
+	SessionDriverDB     = "db"
+	SessionDriverMemory = "memory"
+	SessionDriverFile   = "file"
+	SessionDriverRedis  = "redis"
+)
+
+var (
+	sessionDriver     PersistenceDriver
+	sessionDriverOnce sync.Once
+)
+
+// GetSessionDriver return the PersistenceDriver of the config session_store.
+// 依照config的session_store設置回傳對應的PersistenceDriver
+func GetSessionDriver(conn db.Connection) PersistenceDriver {
+	return newSessionDriver(conn)
+}
+
+// newSessionDriver return the PersistenceDriver of the config session_store.
+// memory、file、redis驅動在整個程序中共用同一個實例，未設置或設置錯誤時使用DBDriver
+func newSessionDriver(conn db.Connection) PersistenceDriver {
+	store := config.GetSessionStore()
+	if store.Driver == "" || store.Driver == SessionDriverDB {
+		return newDBDriver(conn)
+	}
+
+	sessionDriverOnce.Do(func() {
+		expires := time.Second * time.Duration(config.GetSessionLifeTime())
+		switch store.Driver {
+		case SessionDriverMemory:
+			sessionDriver = NewMemoryDriver(expires)
+		case SessionDriverFile:
+			driver, err := NewFileDriver(store.Path, expires)
+			if err != nil {
+				logger.Error("init session file driver failed", err)
+				return
+			}
+			sessionDriver = driver
+		case SessionDriverRedis:
+			sessionDriver = NewRedisDriver(store.Addr, store.Password, store.DB, store.Prefix, expires)
+		default:
+			logger.Error("unknown session driver: ", store.Driver)
+		}
+	})
+
+	if sessionDriver == nil {
+		return newDBDriver(conn)
+	}
+
+	return sessionDriver
+}
+
 // DBDriver is a driver which uses database as a persistence tool.
 // 使用資料庫當作持久性的驅動程式
 // DBDriver也是PersistenceDriver(interface)
@@ -248,6 +303,7 @@ func (driver *DBDriver) Update(sid string, values map[string]interface{}) error
 			if db.CheckError(err, db.DELETE) {
 				return err
 			}
+			return nil
 		}
 		valuesByte, err := json.Marshal(values)
 		if err != nil {
@@ -288,6 +344,7 @@ func (driver *DBDriver) Update(sid string, values map[string]interface{}) error
 	return nil
 }
 
+// session在主資料庫讀取，避免登入後讀取副本尚未同步
 func (driver *DBDriver) table() *db.SQL {
-	return db.Table(driver.tableName).WithDriver(driver.conn)
+	return db.Table(driver.tableName).WithDriver(driver.conn).UsePrimary()
 }
@@ -0,0 +1,171 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+)
+
+// FileDriver is a driver which stores every session as a file in the given directory.
+// 將每個session以檔案形式(檔名為sid)儲存在資料夾中
+// FileDriver也是PersistenceDriver(interface)
+type FileDriver struct {
+	lock    sync.Mutex
+	path    string
+	expires time.Duration
+}
+
+// NewFileDriver return a FileDriver, the directory will be created if not exist.
+// 設置並回傳FileDriver(struct)，資料夾不存在時會建立
+func NewFileDriver(path string, expires time.Duration) (*FileDriver, error) {
+	if path == "" {
+		path = filepath.Join(os.TempDir(), "goadmin_session")
+	}
+	if err := os.MkdirAll(path, os.ModePerm); err != nil {
+		return nil, err
+	}
+	return &FileDriver{
+		path:    path,
+		expires: expires,
+	}, nil
+}
+
+// Load implements the PersistenceDriver.Load.
+// 讀取參數sid的檔案並JSON解碼，檔案不存在或過期則回傳空map
+func (driver *FileDriver) Load(sid string) (map[string]interface{}, error) {
+	file, ok := driver.file(sid)
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+
+	info, err := os.Stat(file)
+	if os.IsNotExist(err) {
+		return map[string]interface{}{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if driver.overdue(info) {
+		_ = os.Remove(file)
+		return map[string]interface{}{}, nil
+	}
+
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	err = json.Unmarshal(content, &values)
+	return values, err
+}
+
+// Update implements the PersistenceDriver.Update.
+// 參數values為空時刪除檔案，否則以JSON編碼後寫入檔案
+func (driver *FileDriver) Update(sid string, values map[string]interface{}) error {
+	file, ok := driver.file(sid)
+	if !ok {
+		return nil
+	}
+
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	if len(values) == 0 {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	valuesByte, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	// 新的session，刪除過期的session，若不允許多處登入則刪除相同內容的session
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		driver.clean(valuesByte)
+	}
+
+	return ioutil.WriteFile(file, valuesByte, 0600)
+}
+
+// 刪除過期以及內容與參數values相同的session檔案
+func (driver *FileDriver) clean(values []byte) {
+	infos, err := ioutil.ReadDir(driver.path)
+	if err != nil {
+		return
+	}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		file := filepath.Join(driver.path, info.Name())
+		if driver.overdue(info) {
+			_ = os.Remove(file)
+			continue
+		}
+		if !config.GetNoLimitLoginIP() {
+			if content, err := ioutil.ReadFile(file); err == nil && string(content) == string(values) {
+				_ = os.Remove(file)
+			}
+		}
+	}
+}
+
+// RevokeUser implements the SessionRevoker.RevokeUser.
+// 刪除用戶所有的session檔案，exceptSid不為空時保留該session
+func (driver *FileDriver) RevokeUser(userId int64, exceptSid string) error {
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	infos, err := ioutil.ReadDir(driver.path)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if info.IsDir() || info.Name() == exceptSid {
+			continue
+		}
+		file := filepath.Join(driver.path, info.Name())
+		content, err := ioutil.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var values map[string]interface{}
+		if json.Unmarshal(content, &values) == nil && sessionUserId(values) == userId {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (driver *FileDriver) overdue(info os.FileInfo) bool {
+	return info.ModTime().Add(driver.expires).Before(time.Now())
+}
+
+// 回傳sid的檔案路徑，sid來自cookie，只允許英數字及-、_避免路徑穿越
+func (driver *FileDriver) file(sid string) (string, bool) {
+	if sid == "" {
+		return "", false
+	}
+	for _, c := range sid {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return "", false
+		}
+	}
+	return filepath.Join(driver.path, sid), true
+}
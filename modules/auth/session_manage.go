@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+)
+
+// SessionTouchInterval is the minimum interval of updating the last seen time of a session.
+// 更新session最後活動時間的最短間隔，避免每個請求都寫入資料庫
+var SessionTouchInterval = time.Minute
+
+// ErrSessionRevokeNotSupported is returned when the session driver can not revoke the sessions
+// of a user.
+var ErrSessionRevokeNotSupported = errors.New("the session driver does not support revoking the sessions of a user")
+
+// SessionRevoker is implemented by the PersistenceDriver which can revoke the sessions by user.
+// 可以依用戶撤銷session的儲存驅動，DBDriver、MemoryDriver、FileDriver及RedisDriver皆有實作
+type SessionRevoker interface {
+	RevokeUser(userId int64, exceptSid string) error
+}
+
+// SessionManager is implemented by the PersistenceDriver which records the user, ip and
+// user agent of the sessions, so that the sessions can be listed and revoked by user.
+// 可以記錄session的用戶、IP及瀏覽器並依用戶撤銷session的儲存驅動，目前只有DBDriver實作(session列表只支援DBDriver)
+type SessionManager interface {
+	SessionRevoker
+	UpdateMeta(sid string, userId int64, ip, userAgent string) error
+	Touch(sid string) error
+}
+
+// 各session最後一次更新活動時間
+var sessionTouched sync.Map
+
+// 記錄session的用戶、IP及瀏覽器資訊
+func (ses *Session) recordMeta() {
+	manager, ok := ses.Driver.(SessionManager)
+	if !ok || ses.Context == nil || ses.Sid == "" {
+		return
+	}
+	if err := manager.UpdateMeta(ses.Sid, sessionUserId(ses.Values), ClientIP(ses.Context), ses.Context.Headers("User-Agent")); err != nil {
+		logger.Error("record session error: ", err)
+	}
+}
+
+// 取得session values中的用戶id，沒有登入時回傳0
+func sessionUserId(values map[string]interface{}) int64 {
+	switch id := values[defaultUserIDSesKey].(type) {
+	case int64:
+		return id
+	case float64:
+		return int64(id)
+	case int:
+		return int64(id)
+	}
+	return 0
+}
+
+// 更新session的最後活動時間
+func (ses *Session) touch() {
+	if manager, ok := ses.Driver.(SessionManager); ok && ses.Sid != "" {
+		if err := manager.Touch(ses.Sid); err != nil {
+			logger.Error("touch session error: ", err)
+		}
+	}
+}
+
+// UpdateMeta implements the SessionManager.UpdateMeta.
+// 將session的用戶、IP及瀏覽器資訊寫入資料表
+func (driver *DBDriver) UpdateMeta(sid string, userId int64, ip, userAgent string) error {
+	if len(userAgent) > 500 {
+		userAgent = userAgent[:500]
+	}
+	_, err := driver.table().Where("sid", "=", sid).Update(dialect.H{
+		"user_id":    userId,
+		"ip":         ip,
+		"user_agent": userAgent,
+		"updated_at": time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if db.CheckError(err, db.UPDATE) {
+		return err
+	}
+	sessionTouched.Store(sid, time.Now())
+	return nil
+}
+
+// Touch implements the SessionManager.Touch.
+// 更新session的最後活動時間(updated_at)，在SessionTouchInterval內只會更新一次
+func (driver *DBDriver) Touch(sid string) error {
+	if last, ok := sessionTouched.Load(sid); ok && time.Since(last.(time.Time)) < SessionTouchInterval {
+		return nil
+	}
+	sessionTouched.Store(sid, time.Now())
+	_, err := driver.table().Where("sid", "=", sid).Update(dialect.H{
+		"updated_at": time.Now().Format("2006-01-02 15:04:05"),
+	})
+	if db.CheckError(err, db.UPDATE) {
+		return err
+	}
+	return nil
+}
+
+// RevokeUser implements the SessionManager.RevokeUser.
+// 刪除用戶所有的session，exceptSid不為空時保留該session
+func (driver *DBDriver) RevokeUser(userId int64, exceptSid string) error {
+	sql := driver.table().Where("user_id", "=", userId)
+	if exceptSid != "" {
+		sql = sql.Where("sid", "!=", exceptSid)
+	}
+	err := sql.Delete()
+	if db.CheckError(err, db.DELETE) {
+		return err
+	}
+	return nil
+}
+
+// CurrentSid return the sid of the session of the request.
+// 回傳目前請求的session id，沒有cookie時回傳空值
+func CurrentSid(ctx *context.Context) string {
+	if cookie, err := ctx.Request.Cookie(DefaultCookieKey); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// RevokeSession revoke the session of the sid.
+// 撤銷單一session，所有的儲存驅動皆支援
+func RevokeSession(conn db.Connection, sid string) error {
+	sessionTouched.Delete(sid)
+	return newSessionDriver(conn).Update(sid, map[string]interface{}{})
+}
+
+// RevokeUserSessions revoke all the sessions of the user except the exceptSid and all the
+// issued bearer tokens of the user. It returns ErrSessionRevokeNotSupported if the session
+// driver does not implement the SessionRevoker.
+// 撤銷用戶所有的token及session(保留exceptSid)，儲存驅動未實作SessionRevoker時回傳ErrSessionRevokeNotSupported
+func RevokeUserSessions(conn db.Connection, userId int64, exceptSid string) error {
+	if err := RevokeUserTokens(userId, conn); err != nil {
+		return err
+	}
+	if revoker, ok := newSessionDriver(conn).(SessionRevoker); ok {
+		return revoker.RevokeUser(userId, exceptSid)
+	}
+	return ErrSessionRevokeNotSupported
+}
+
+// SessionListable check whether the sessions can be listed, which needs the session driver
+// implements the SessionManager.
+// 判斷是否可以列出session(儲存驅動實作SessionManager，目前只有DBDriver)
+func SessionListable(conn db.Connection) bool {
+	_, ok := newSessionDriver(conn).(SessionManager)
+	return ok
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSessionManager struct {
+	values    map[string]map[string]interface{}
+	userId    int64
+	userAgent string
+	touched   int
+}
+
+func (m *testSessionManager) Load(sid string) (map[string]interface{}, error) {
+	return m.values[sid], nil
+}
+
+func (m *testSessionManager) Update(sid string, values map[string]interface{}) error {
+	m.values[sid] = values
+	return nil
+}
+
+func (m *testSessionManager) UpdateMeta(sid string, userId int64, ip, userAgent string) error {
+	m.userId, m.userAgent = userId, userAgent
+	return nil
+}
+
+func (m *testSessionManager) Touch(sid string) error {
+	m.touched++
+	return nil
+}
+
+func (m *testSessionManager) RevokeUser(userId int64, exceptSid string) error {
+	return nil
+}
+
+func TestSessionMeta(t *testing.T) {
+	var (
+		driver = &testSessionManager{values: make(map[string]map[string]interface{})}
+		ctx    = context.NewContext(&http.Request{
+			Method: "GET",
+			URL:    &url.URL{},
+			Header: http.Header{"User-Agent": {"test-agent"}},
+		})
+		ses = &Session{Driver: driver, Sid: "sid1", Values: make(map[string]interface{}), Context: ctx}
+	)
+
+	assert.Equal(t, ses.Add(defaultUserIDSesKey, int64(2)), nil)
+	assert.Equal(t, driver.userId, int64(2))
+	assert.Equal(t, driver.userAgent, "test-agent")
+
+	ses.touch()
+	assert.Equal(t, driver.touched, 1)
+
+	ctx.Request.Header.Set("Cookie", DefaultCookieKey+"=sid1")
+	assert.Equal(t, CurrentSid(ctx), "sid1")
+}
@@ -0,0 +1,117 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+)
+
+// MemoryDriver is a driver which stores the sessions in the process memory.
+// 將session儲存在記憶體中，重啟後session會全部失效
+// MemoryDriver也是PersistenceDriver(interface)
+type MemoryDriver struct {
+	lock     sync.RWMutex
+	expires  time.Duration
+	sessions map[string]memorySession
+}
+
+type memorySession struct {
+	values   string
+	expireAt time.Time
+}
+
+// NewMemoryDriver return a MemoryDriver with the given expires.
+// 設置並回傳MemoryDriver(struct)
+func NewMemoryDriver(expires time.Duration) *MemoryDriver {
+	return &MemoryDriver{
+		expires:  expires,
+		sessions: make(map[string]memorySession),
+	}
+}
+
+// Load implements the PersistenceDriver.Load.
+// 取得參數sid的session，過期則刪除並回傳空map
+func (driver *MemoryDriver) Load(sid string) (map[string]interface{}, error) {
+	driver.lock.RLock()
+	ses, ok := driver.sessions[sid]
+	driver.lock.RUnlock()
+
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+
+	if ses.expireAt.Before(time.Now()) {
+		driver.lock.Lock()
+		delete(driver.sessions, sid)
+		driver.lock.Unlock()
+		return map[string]interface{}{}, nil
+	}
+
+	var values map[string]interface{}
+	err := json.Unmarshal([]byte(ses.values), &values)
+	return values, err
+}
+
+// Update implements the PersistenceDriver.Update.
+// 參數values為空時刪除session，否則以JSON編碼後儲存(與DBDriver相同，數字解碼後為float64)
+func (driver *MemoryDriver) Update(sid string, values map[string]interface{}) error {
+	if sid == "" {
+		return nil
+	}
+
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	if len(values) == 0 {
+		delete(driver.sessions, sid)
+		return nil
+	}
+
+	valuesByte, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	sesValue := string(valuesByte)
+
+	now := time.Now()
+
+	// 新的session，刪除過期的session，若不允許多處登入則刪除相同內容的session
+	if _, ok := driver.sessions[sid]; !ok {
+		for key, ses := range driver.sessions {
+			if ses.expireAt.Before(now) || (!config.GetNoLimitLoginIP() && ses.values == sesValue) {
+				delete(driver.sessions, key)
+			}
+		}
+	}
+
+	driver.sessions[sid] = memorySession{
+		values:   sesValue,
+		expireAt: now.Add(driver.expires),
+	}
+
+	return nil
+}
+
+// RevokeUser implements the SessionRevoker.RevokeUser.
+// 刪除用戶所有的session，exceptSid不為空時保留該session
+func (driver *MemoryDriver) RevokeUser(userId int64, exceptSid string) error {
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	for sid, ses := range driver.sessions {
+		if sid == exceptSid {
+			continue
+		}
+		var values map[string]interface{}
+		if err := json.Unmarshal([]byte(ses.values), &values); err == nil && sessionUserId(values) == userId {
+			delete(driver.sessions, sid)
+		}
+	}
+	return nil
+}
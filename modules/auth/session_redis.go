@@ -0,0 +1,286 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+)
+
+// RedisDriver is a driver which stores the sessions in a server speaking the redis protocol.
+// 將session儲存在redis(或任何支援redis協議的服務)中，多個實例可以共用session
+// RedisDriver也是PersistenceDriver(interface)
+type RedisDriver struct {
+	lock     sync.Mutex
+	addr     string
+	password string
+	db       int
+	prefix   string
+	expires  time.Duration
+	conn     net.Conn
+	reader   *bufio.Reader
+}
+
+// NewRedisDriver return a RedisDriver, the connection is established lazily.
+// 設置並回傳RedisDriver(struct)，第一次使用時才會建立連線
+func NewRedisDriver(addr, password string, db int, prefix string, expires time.Duration) *RedisDriver {
+	if addr == "" {
+		addr = "127.0.0.1:6379"
+	}
+	if prefix == "" {
+		prefix = "goadmin_session:"
+	}
+	return &RedisDriver{
+		addr:     addr,
+		password: password,
+		db:       db,
+		prefix:   prefix,
+		expires:  expires,
+	}
+}
+
+// Load implements the PersistenceDriver.Load.
+// 取得key為prefix+sid的值並JSON解碼
+func (driver *RedisDriver) Load(sid string) (map[string]interface{}, error) {
+	reply, err := driver.do("GET", driver.prefix+sid)
+	if err != nil {
+		return nil, err
+	}
+
+	content, ok := reply.(string)
+	if !ok {
+		return map[string]interface{}{}, nil
+	}
+
+	var values map[string]interface{}
+	err = json.Unmarshal([]byte(content), &values)
+	return values, err
+}
+
+// Update implements the PersistenceDriver.Update.
+// 參數values為空時刪除key，否則以JSON編碼後設置並給予過期時間
+// 若不允許多處登入，以values的hash記錄目前的sid並刪除舊的session
+func (driver *RedisDriver) Update(sid string, values map[string]interface{}) error {
+	if sid == "" {
+		return nil
+	}
+
+	if len(values) == 0 {
+		_, err := driver.do("DEL", driver.prefix+sid)
+		return err
+	}
+
+	valuesByte, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	ttl := strconv.Itoa(int(driver.expires / time.Second))
+
+	if !config.GetNoLimitLoginIP() {
+		hash := sha1.Sum(valuesByte)
+		indexKey := driver.prefix + "values:" + hex.EncodeToString(hash[:])
+		old, err := driver.do("GET", indexKey)
+		if err != nil {
+			return err
+		}
+		if oldSid, ok := old.(string); ok && oldSid != sid {
+			if _, err = driver.do("DEL", driver.prefix+oldSid); err != nil {
+				return err
+			}
+		}
+		if _, err = driver.do("SET", indexKey, sid, "EX", ttl); err != nil {
+			return err
+		}
+	}
+
+	if _, err = driver.do("SET", driver.prefix+sid, string(valuesByte), "EX", ttl); err != nil {
+		return err
+	}
+
+	// 以集合記錄用戶的sid，撤銷用戶session時使用
+	if userId := sessionUserId(values); userId != 0 {
+		userKey := driver.userKey(userId)
+		if _, err = driver.do("SADD", userKey, sid); err != nil {
+			return err
+		}
+		_, err = driver.do("EXPIRE", userKey, ttl)
+	}
+	return err
+}
+
+// RevokeUser implements the SessionRevoker.RevokeUser.
+// 刪除用戶集合中所有的session，exceptSid不為空時保留該session
+func (driver *RedisDriver) RevokeUser(userId int64, exceptSid string) error {
+	userKey := driver.userKey(userId)
+	reply, err := driver.do("SMEMBERS", userKey)
+	if err != nil {
+		return err
+	}
+	members, _ := reply.([]interface{})
+	for _, member := range members {
+		sid, ok := member.(string)
+		if !ok || sid == exceptSid {
+			continue
+		}
+		if _, err = driver.do("DEL", driver.prefix+sid); err != nil {
+			return err
+		}
+		if _, err = driver.do("SREM", userKey, sid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (driver *RedisDriver) userKey(userId int64) string {
+	return driver.prefix + "user:" + strconv.FormatInt(userId, 10)
+}
+
+// Close closes the connection.
+// 關閉連線
+func (driver *RedisDriver) Close() error {
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+	return driver.closeConn()
+}
+
+// 執行命令，連線中斷時重新連線再試一次
+func (driver *RedisDriver) do(args ...string) (interface{}, error) {
+	driver.lock.Lock()
+	defer driver.lock.Unlock()
+
+	reply, err := driver.command(args...)
+	if _, isReplyErr := err.(redisError); err != nil && !isReplyErr {
+		_ = driver.closeConn()
+		reply, err = driver.command(args...)
+	}
+	return reply, err
+}
+
+func (driver *RedisDriver) command(args ...string) (interface{}, error) {
+	if driver.conn == nil {
+		if err := driver.connect(); err != nil {
+			return nil, err
+		}
+	}
+	_ = driver.conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if err := writeRedisCommand(driver.conn, args...); err != nil {
+		return nil, err
+	}
+	return readRedisReply(driver.reader)
+}
+
+// 建立連線，有設置密碼及db時先執行AUTH、SELECT
+func (driver *RedisDriver) connect() error {
+	conn, err := net.DialTimeout("tcp", driver.addr, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	driver.conn = conn
+	driver.reader = bufio.NewReader(conn)
+
+	if driver.password != "" {
+		if _, err = driver.command("AUTH", driver.password); err != nil {
+			_ = driver.closeConn()
+			return err
+		}
+	}
+	if driver.db != 0 {
+		if _, err = driver.command("SELECT", strconv.Itoa(driver.db)); err != nil {
+			_ = driver.closeConn()
+			return err
+		}
+	}
+	return nil
+}
+
+func (driver *RedisDriver) closeConn() error {
+	if driver.conn == nil {
+		return nil
+	}
+	err := driver.conn.Close()
+	driver.conn = nil
+	return err
+}
+
+// redisError is the error reply of the server.
+// 伺服器回傳的錯誤(-ERR ...)，不需要重新連線
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// 以RESP格式寫入命令
+func writeRedisCommand(w io.Writer, args ...string) error {
+	buf := []byte("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		buf = append(buf, "$"+strconv.Itoa(len(arg))+"\r\n"+arg+"\r\n"...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// 讀取RESP格式的回覆，nil bulk string回傳nil
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, errors.New("redis: invalid reply")
+	}
+	line = line[:len(line)-2]
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, err
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		replies := make([]interface{}, n)
+		for i := range replies {
+			if replies[i], err = readRedisReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return replies, nil
+	}
+	return nil, fmt.Errorf("redis: unexpected reply %q", line)
+}
@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPersistenceDriver(t *testing.T, driver PersistenceDriver) {
+	values, err := driver.Load("sid1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(values), 0)
+
+	assert.Equal(t, driver.Update("sid1", map[string]interface{}{"user_id": 1}), nil)
+
+	values, err = driver.Load("sid1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, values["user_id"], float64(1))
+
+	// the same user login again, the old session should be removed
+	assert.Equal(t, driver.Update("sid2", map[string]interface{}{"user_id": 1}), nil)
+
+	values, _ = driver.Load("sid1")
+	assert.Equal(t, len(values), 0)
+	values, _ = driver.Load("sid2")
+	assert.Equal(t, values["user_id"], float64(1))
+
+	assert.Equal(t, driver.Update("sid2", map[string]interface{}{}), nil)
+	values, _ = driver.Load("sid2")
+	assert.Equal(t, len(values), 0)
+}
+
+func testSessionRevoker(t *testing.T, driver PersistenceDriver) {
+	revoker, ok := driver.(SessionRevoker)
+	assert.Equal(t, ok, true)
+
+	assert.Equal(t, driver.Update("sid4", map[string]interface{}{"user_id": 4, "ip": "1"}), nil)
+	assert.Equal(t, driver.Update("sid5", map[string]interface{}{"user_id": 4, "ip": "2"}), nil)
+	assert.Equal(t, driver.Update("sid6", map[string]interface{}{"user_id": 4, "ip": "3"}), nil)
+	assert.Equal(t, driver.Update("sid7", map[string]interface{}{"user_id": 7}), nil)
+
+	assert.Equal(t, revoker.RevokeUser(4, "sid5"), nil)
+
+	values, _ := driver.Load("sid4")
+	assert.Equal(t, len(values), 0)
+	values, _ = driver.Load("sid6")
+	assert.Equal(t, len(values), 0)
+	values, _ = driver.Load("sid5")
+	assert.Equal(t, values["user_id"], float64(4))
+	values, _ = driver.Load("sid7")
+	assert.Equal(t, values["user_id"], float64(7))
+
+	assert.Equal(t, revoker.RevokeUser(4, ""), nil)
+	values, _ = driver.Load("sid5")
+	assert.Equal(t, len(values), 0)
+}
+
+func TestMemoryDriver(t *testing.T) {
+	testPersistenceDriver(t, NewMemoryDriver(time.Hour))
+	testSessionRevoker(t, NewMemoryDriver(time.Hour))
+
+	driver := NewMemoryDriver(-time.Second)
+	assert.Equal(t, driver.Update("sid1", map[string]interface{}{"user_id": 1}), nil)
+	values, _ := driver.Load("sid1")
+	assert.Equal(t, len(values), 0)
+}
+
+func TestDBDriver(t *testing.T) {
+	conn, cleanup := testSqliteConn(t)
+	defer cleanup()
+
+	driver := newDBDriver(conn)
+	testPersistenceDriver(t, driver)
+
+	// the session of the empty values is deleted instead of saving an empty row
+	assert.Equal(t, driver.Update("csrf_token", map[string]interface{}{"1": 1}), nil)
+	assert.Equal(t, driver.Update("csrf_token", map[string]interface{}{}), nil)
+	count, err := driver.table().Where("sid", "=", "csrf_token").Count()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, int64(0))
+	count, err = driver.table().Where("sid", "=", "sid2").Count()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, int64(0))
+}
+
+func TestFileDriver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goadmin_session")
+	assert.Equal(t, err, nil)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	driver, err := NewFileDriver(dir, time.Hour)
+	assert.Equal(t, err, nil)
+	testPersistenceDriver(t, driver)
+	testSessionRevoker(t, driver)
+
+	assert.Equal(t, driver.Update("../sid1", map[string]interface{}{"user_id": 1}), nil)
+	_, err = os.Stat(dir + "/../sid1")
+	assert.Equal(t, os.IsNotExist(err), true)
+}
+
+func TestRedisDriver(t *testing.T) {
+	addr, stop := startRedisStub(t)
+	defer stop()
+
+	driver := NewRedisDriver(addr, "secret", 1, "", time.Hour)
+	defer func() { _ = driver.Close() }()
+
+	testPersistenceDriver(t, driver)
+	testSessionRevoker(t, driver)
+
+	// reconnect after the connection is broken
+	_ = driver.conn.Close()
+	assert.Equal(t, driver.Update("sid3", map[string]interface{}{"user_id": 3}), nil)
+	values, err := driver.Load("sid3")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, values["user_id"], float64(3))
+
+	_, err = NewRedisDriver(addr, "wrong", 0, "", time.Hour).Load("sid3")
+	assert.NotEqual(t, err, nil)
+}
+
+// startRedisStub start a minimal server of the redis protocol which supports AUTH, SELECT, GET, SET, DEL,
+// EXPIRE and the SADD, SREM, SMEMBERS of the sets.
+func startRedisStub(t *testing.T) (string, func()) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		lock sync.Mutex
+		data = make(map[string]string)
+		sets = make(map[string]map[string]bool)
+	)
+
+	handle := func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+		r := bufio.NewReader(conn)
+		for {
+			reply, err := readRedisReply(r)
+			if err != nil {
+				return
+			}
+			args := make([]string, 0)
+			for _, arg := range reply.([]interface{}) {
+				args = append(args, arg.(string))
+			}
+			lock.Lock()
+			switch strings.ToUpper(args[0]) {
+			case "AUTH":
+				if args[1] == "secret" {
+					_, _ = conn.Write([]byte("+OK\r\n"))
+				} else {
+					_, _ = conn.Write([]byte("-ERR invalid password\r\n"))
+				}
+			case "SELECT":
+				_, _ = conn.Write([]byte("+OK\r\n"))
+			case "GET":
+				if v, ok := data[args[1]]; ok {
+					_, _ = conn.Write([]byte("$" + strconv.Itoa(len(v)) + "\r\n" + v + "\r\n"))
+				} else {
+					_, _ = conn.Write([]byte("$-1\r\n"))
+				}
+			case "SET":
+				data[args[1]] = args[2]
+				_, _ = conn.Write([]byte("+OK\r\n"))
+			case "DEL":
+				delete(data, args[1])
+				_, _ = conn.Write([]byte(":1\r\n"))
+			case "EXPIRE":
+				_, _ = conn.Write([]byte(":1\r\n"))
+			case "SADD":
+				if sets[args[1]] == nil {
+					sets[args[1]] = make(map[string]bool)
+				}
+				sets[args[1]][args[2]] = true
+				_, _ = conn.Write([]byte(":1\r\n"))
+			case "SREM":
+				delete(sets[args[1]], args[2])
+				_, _ = conn.Write([]byte(":1\r\n"))
+			case "SMEMBERS":
+				buf := "*" + strconv.Itoa(len(sets[args[1]])) + "\r\n"
+				for member := range sets[args[1]] {
+					buf += "$" + strconv.Itoa(len(member)) + "\r\n" + member + "\r\n"
+				}
+				_, _ = conn.Write([]byte(buf))
+			default:
+				_, _ = conn.Write([]byte("-ERR unknown command\r\n"))
+			}
+			lock.Unlock()
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handle(conn)
+		}
+	}()
+
+	return ln.Addr().String(), func() { _ = ln.Close() }
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+const (
+	// AuthorizationHeader is the header which carries the bearer token.
+	AuthorizationHeader = "Authorization"
+	bearerPrefix        = "Bearer "
+	bearerAuthKey       = "auth_by_bearer_token"
+)
+
+var (
+	ErrTokenMalformed = errors.New("malformed token")
+	ErrTokenSignature = errors.New("token signature is invalid")
+	ErrTokenExpired   = errors.New("token is expired")
+	ErrTokenRevoked   = errors.New("token is revoked")
+)
+
+// jwt header，固定使用HS256
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// TokenClaims is the payload of the bearer token.
+// token所攜帶的資訊，Uid為用戶id，Ver為簽發時用戶的token版本，Exp為過期時間(unix時間)
+type TokenClaims struct {
+	Uid int64 `json:"uid"`
+	Ver int64 `json:"ver"`
+	Iat int64 `json:"iat"`
+	Exp int64 `json:"exp"`
+}
+
+// NewToken generate a HS256 signed jwt token of the given user id and token version.
+// 利用參數key簽署包含用戶id、token版本及過期時間的jwt token
+func NewToken(key string, uid, ver int64, lifeTime time.Duration) (string, error) {
+	if key == "" {
+		return "", errors.New("empty token key")
+	}
+	now := time.Now()
+	payload, err := json.Marshal(TokenClaims{
+		Uid: uid,
+		Ver: ver,
+		Iat: now.Unix(),
+		Exp: now.Add(lifeTime).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + signToken(key, unsigned), nil
+}
+
+// ParseToken verify the signature and expiry of the token and return the claims.
+// 驗證token的簽章及是否過期，成功則回傳TokenClaims
+func ParseToken(key, token string) (TokenClaims, error) {
+	var claims TokenClaims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != tokenHeader {
+		return claims, ErrTokenMalformed
+	}
+
+	if key == "" || !hmac.Equal([]byte(parts[2]), []byte(signToken(key, parts[0]+"."+parts[1]))) {
+		return claims, ErrTokenSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, ErrTokenMalformed
+	}
+
+	if err = json.Unmarshal(payload, &claims); err != nil {
+		return claims, ErrTokenMalformed
+	}
+
+	if claims.Exp < time.Now().Unix() {
+		return claims, ErrTokenExpired
+	}
+
+	return claims, nil
+}
+
+// 利用HMAC-SHA256簽署參數s
+func signToken(key, s string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write([]byte(s))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// BearerToken return the bearer token of the request, empty if not exist.
+// 取得header中Authorization: Bearer後的token
+func BearerToken(ctx *context.Context) string {
+	h := ctx.Headers(AuthorizationHeader)
+	if len(h) > len(bearerPrefix) && strings.EqualFold(h[:len(bearerPrefix)], bearerPrefix) {
+		return strings.TrimSpace(h[len(bearerPrefix):])
+	}
+	return ""
+}
+
+// IsBearerAuth check if the user of the request is authenticated by the bearer token.
+// 判斷請求是否透過token驗證，token驗證的請求不需要檢查csrf token
+func IsBearerAuth(ctx *context.Context) bool {
+	ok, _ := ctx.UserValue[bearerAuthKey].(bool)
+	return ok
+}
+
+// TokenEnabled check if the bearer token authentication is enabled.
+// 設置了auth_token_key才開啟token驗證
+func TokenEnabled() bool {
+	return config.GetAuthTokenKey() != ""
+}
+
+// IssueToken generate the bearer token of the given user with the global config.
+// 利用全局設置的key及有效時間產生用戶目前token版本的token
+func IssueToken(uid int64, conn db.Connection) (string, error) {
+	return NewToken(config.GetAuthTokenKey(), uid, TokenVersion(uid, conn),
+		time.Duration(config.GetAuthTokenLifeTime())*time.Second)
+}
+
+// TokenVersion return the current token version of the user.
+// 取得用戶目前的token版本，token中的版本不同時視為已撤銷
+func TokenVersion(uid int64, conn db.Connection) int64 {
+	return models.UserTokenVersion().SetConn(conn).FindByUserId(uid).Version
+}
+
+// CheckTokenVersion check the token is not revoked.
+// 檢查token的版本是否為用戶目前的版本
+func CheckTokenVersion(claims TokenClaims, conn db.Connection) error {
+	if claims.Ver != TokenVersion(claims.Uid, conn) {
+		return ErrTokenRevoked
+	}
+	return nil
+}
+
+// RevokeUserTokens revoke all the issued bearer tokens of the user.
+// 增加用戶的token版本，撤銷所有已簽發的token
+func RevokeUserTokens(uid int64, conn db.Connection) error {
+	_, err := models.UserTokenVersion().SetConn(conn).FindByUserId(uid).Bump()
+	return err
+}
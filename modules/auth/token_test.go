@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToken(t *testing.T) {
+	token, err := NewToken("secret", 12, 3, time.Hour)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(strings.Split(token, ".")), 3)
+
+	claims, err := ParseToken("secret", token)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, claims.Uid, int64(12))
+	assert.Equal(t, claims.Ver, int64(3))
+
+	_, err = ParseToken("another secret", token)
+	assert.Equal(t, err, ErrTokenSignature)
+
+	_, err = ParseToken("secret", token+"a")
+	assert.Equal(t, err, ErrTokenSignature)
+
+	_, err = ParseToken("secret", "abc")
+	assert.Equal(t, err, ErrTokenMalformed)
+
+	token, _ = NewToken("secret", 12, 3, -time.Minute)
+	_, err = ParseToken("secret", token)
+	assert.Equal(t, err, ErrTokenExpired)
+
+	_, err = NewToken("", 12, 3, time.Hour)
+	assert.NotEqual(t, err, nil)
+}
+
+func TestTokenRevoke(t *testing.T) {
+	conn, cleanup := testSqliteConn(t)
+	defer cleanup()
+
+	assert.Equal(t, TokenVersion(1, conn), int64(0))
+	assert.Equal(t, CheckTokenVersion(TokenClaims{Uid: 1}, conn), nil)
+
+	assert.Equal(t, RevokeUserTokens(1, conn), nil)
+	assert.Equal(t, CheckTokenVersion(TokenClaims{Uid: 1}, conn), ErrTokenRevoked)
+	assert.Equal(t, CheckTokenVersion(TokenClaims{Uid: 1, Ver: 1}, conn), nil)
+
+	// revoking the sessions revokes the tokens too
+	assert.Equal(t, RevokeUserSessions(conn, 1, ""), nil)
+	assert.Equal(t, CheckTokenVersion(TokenClaims{Uid: 1, Ver: 1}, conn), ErrTokenRevoked)
+	assert.Equal(t, TokenVersion(1, conn), int64(2))
+
+	// the other users are not affected
+	assert.Equal(t, CheckTokenVersion(TokenClaims{Uid: 2}, conn), nil)
+}
@@ -0,0 +1,224 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+)
+
+const (
+	totpPeriod = 30
+	totpDigits = 6
+
+	// session中等待兩步驟驗證的用戶id
+	totpPendingSesKey = "totp_user_id"
+	// session中等待兩步驟驗證的過期時間
+	totpPendingExpireSesKey = "totp_expire_at"
+	// session中輸入錯誤驗證碼的次數
+	totpPendingFailSesKey = "totp_fail_times"
+	// 輸入驗證碼的時間限制
+	totpPendingLifeTime = 5 * time.Minute
+	// 輸入錯誤驗證碼的次數限制，超過需要重新登入
+	totpPendingMaxFail = 5
+
+	recoveryCodeCount = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret return a random base32 encoded secret.
+// 產生隨機的base32 secret(160 bits)
+func GenerateTOTPSecret() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return totpEncoding.EncodeToString(b)
+}
+
+// TOTPCode return the time-based one-time code of the given secret and time(RFC 6238).
+// 依照RFC 6238計算參數t時間點的驗證碼
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+	if err != nil {
+		return "", err
+	}
+
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/totpPeriod))
+
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	code := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	return fmt.Sprintf("%06d", code%1000000), nil
+}
+
+// ValidateTOTP check the code of the secret, one period before and after is allowed
+// for the clock skew.
+// 檢查驗證碼是否正確，允許前後一個週期(30秒)的時間誤差
+func ValidateTOTP(secret, code string, t time.Time) bool {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return false
+	}
+	for _, skew := range []int64{0, -totpPeriod, totpPeriod} {
+		expected, err := TOTPCode(secret, t.Add(time.Duration(skew)*time.Second))
+		if err != nil {
+			return false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return true
+		}
+	}
+	return false
+}
+
+// TOTPURI return the otpauth uri which is used by the authenticator app.
+// 回傳驗證器app掃描用的otpauth uri
+func TOTPURI(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("digits", fmt.Sprintf("%d", totpDigits))
+	v.Set("period", fmt.Sprintf("%d", totpPeriod))
+	return "otpauth://totp/" + url.PathEscape(issuer+":"+account) + "?" + v.Encode()
+}
+
+// GenerateRecoveryCodes return the recovery codes and the hashes of them.
+// 產生一次性的恢復碼，回傳明文(只顯示一次)以及儲存用的sha256 hash
+func GenerateRecoveryCodes() ([]string, []string) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		_, _ = rand.Read(b)
+		s := strings.ToLower(hex.EncodeToString(b))
+		codes[i] = s[:5] + "-" + s[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
+
+// TOTPRequired check if the user must use the two-factor authentication.
+// 判斷用戶的角色(包含繼承的上層角色)是否在totp_required_roles中("*"表示所有用戶)
+func TOTPRequired(user models.UserModel, conn db.Connection) bool {
+	return userInRoles(user, config.GetTOTPRequiredRoles(), conn)
+}
+
+// 判斷用戶的角色或其上層角色是否在參數slugs中，"*"表示所有用戶
+func userInRoles(user models.UserModel, slugs []string, conn db.Connection) bool {
+	if len(slugs) == 0 {
+		return false
+	}
+	for _, slug := range slugs {
+		if slug == "*" {
+			return true
+		}
+	}
+	for _, roleSlugs := range user.SetConn(conn).RoleSlugsWithAncestors() {
+		for _, roleSlug := range roleSlugs {
+			for _, slug := range slugs {
+				if roleSlug == slug {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// CheckTOTP check the one-time code or the recovery code of the user, a used recovery
+// code will be removed.
+// 檢查用戶的驗證碼或恢復碼，恢復碼使用後即失效
+func CheckTOTP(user models.UserModel, code string, conn db.Connection) bool {
+	totp := models.UserTOTP().SetConn(conn).FindByUserId(user.Id)
+	if !totp.Enabled {
+		return false
+	}
+	if ValidateTOTP(totp.Secret, code, time.Now()) {
+		return true
+	}
+	return totp.UseRecoveryCode(hashRecoveryCode(code))
+}
+
+// SetTOTPPending mark the user of the session as waiting for the two-factor authentication.
+// 密碼驗證成功後將用戶id存至session，等待輸入驗證碼，此時用戶尚未登入
+func SetTOTPPending(ctx *context.Context, user models.UserModel, conn db.Connection) error {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return err
+	}
+	delete(ses.Values, defaultUserIDSesKey)
+	delete(ses.Values, totpPendingFailSesKey)
+	ses.Values[totpPendingExpireSesKey] = time.Now().Add(totpPendingLifeTime).Unix()
+	return ses.Add(totpPendingSesKey, user.Id)
+}
+
+// GetTOTPPendingUser return the user waiting for the two-factor authentication.
+// 取得session中等待兩步驟驗證的用戶
+func GetTOTPPendingUser(ctx *context.Context, conn db.Connection) (models.UserModel, bool) {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return models.User(), false
+	}
+	id, ok := ses.Get(totpPendingSesKey).(float64)
+	if !ok {
+		return models.User(), false
+	}
+	if expireAt, _ := ses.Get(totpPendingExpireSesKey).(float64); int64(expireAt) < time.Now().Unix() {
+		return models.User(), false
+	}
+	return GetCurUserByID(int64(id), conn)
+}
+
+// AddTOTPPendingFailure record a wrong code, the pending state is cleared when it
+// reaches the limit and false will be returned.
+// 記錄輸入錯誤的次數，超過限制時清除等待狀態並回傳false(需要重新輸入帳號密碼)
+func AddTOTPPendingFailure(ctx *context.Context, conn db.Connection) (bool, error) {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return false, err
+	}
+	times, _ := ses.Get(totpPendingFailSesKey).(float64)
+	if int(times)+1 >= totpPendingMaxFail {
+		return false, ses.Clear()
+	}
+	return true, ses.Add(totpPendingFailSesKey, int(times)+1)
+}
+
+// FinishTOTPPending login the user after the two-factor authentication.
+// 兩步驟驗證成功，清除等待狀態並登入
+func FinishTOTPPending(ctx *context.Context, user models.UserModel, conn db.Connection) error {
+	ses, err := InitSession(ctx, conn)
+	if err != nil {
+		return err
+	}
+	delete(ses.Values, totpPendingSesKey)
+	delete(ses.Values, totpPendingExpireSesKey)
+	delete(ses.Values, totpPendingFailSesKey)
+	return ses.Add(defaultUserIDSesKey, user.Id)
+}
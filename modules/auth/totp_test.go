@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// the secret of RFC 6238 test vectors: "12345678901234567890"
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	for unix, code := range map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	} {
+		c, err := TOTPCode(rfcSecret, time.Unix(unix, 0))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, c, code)
+	}
+
+	_, err := TOTPCode("not base32!", time.Now())
+	assert.NotEqual(t, err, nil)
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	assert.Equal(t, ValidateTOTP(rfcSecret, "081804", now), true)
+	assert.Equal(t, ValidateTOTP(rfcSecret, " 081804 ", now), true)
+	// one period of clock skew is allowed
+	assert.Equal(t, ValidateTOTP(rfcSecret, "081804", now.Add(30*time.Second)), true)
+	assert.Equal(t, ValidateTOTP(rfcSecret, "081804", now.Add(-30*time.Second)), true)
+	assert.Equal(t, ValidateTOTP(rfcSecret, "081804", now.Add(90*time.Second)), false)
+	assert.Equal(t, ValidateTOTP(rfcSecret, "81804", now), false)
+
+	secret := GenerateTOTPSecret()
+	assert.Equal(t, len(secret), 32)
+	code, _ := TOTPCode(secret, time.Now())
+	assert.Equal(t, ValidateTOTP(secret, code, time.Now()), true)
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes := GenerateRecoveryCodes()
+	assert.Equal(t, len(codes), recoveryCodeCount)
+	assert.Equal(t, len(hashes), recoveryCodeCount)
+	for i, code := range codes {
+		assert.Equal(t, len(code), 11)
+		assert.Equal(t, hashRecoveryCode(strings.ToUpper(" "+code+" ")), hashes[i])
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	assert.Equal(t, TOTPURI("GoAdmin", "admin", rfcSecret),
+		"otpauth://totp/GoAdmin:admin?digits=6&issuer=GoAdmin&period=30&secret="+rfcSecret)
+}
+
+func TestUserInRoles(t *testing.T) {
+	conn, cleanup := testSqliteConn(t)
+	defer cleanup()
+
+	user := models.UserModel{Roles: []models.RoleModel{{Id: 2, Slug: "operator"}}}
+
+	assert.Equal(t, userInRoles(user, nil, conn), false)
+	assert.Equal(t, userInRoles(user, []string{"*"}, conn), true)
+	assert.Equal(t, userInRoles(user, []string{"operator"}, conn), true)
+	assert.Equal(t, userInRoles(user, []string{"administrator"}, conn), false)
+
+	// the roles inherited from the parent roles are required too
+	_, err := models.Role().SetConn(conn).Find(2).AddParent("1")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, userInRoles(user, []string{"administrator"}, conn), true)
+}
@@ -6,6 +6,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
@@ -30,6 +31,16 @@ import (
 // If the Dsn is configured, when driver is mysql/postgresql/
 // mssql, the other configurations will be ignored, except for
 // MaxIdleCon and MaxOpenCon.
+//
+// QueryTimeout is the default timeout(seconds) of the statements which are
+// executed with a context, zero means no timeout.
+//
+// Replicas are the read replicas of the connection, the reads are routed to
+// the healthy replicas in turn, the writes, the transactions and the reads of
+// the admin models and sessions are executed in the primary. The empty options of a replica are the same as the primary
+// except for the Dsn. ReplicaCheckInterval is the interval(seconds) of the
+// health check of the replicas, default is 10. ReadAfterWrite is the duration
+// (seconds) of reading from the primary after a write of the connection.
 // 資料庫引擎資訊配置
 type Database struct {
 	Host       string            `json:"host,omitempty" yaml:"host,omitempty" ini:"host,omitempty"`
@@ -43,6 +54,58 @@ type Database struct {
 	File       string            `json:"file,omitempty" yaml:"file,omitempty" ini:"file,omitempty"`
 	Dsn        string            `json:"dsn,omitempty" yaml:"dsn,omitempty" ini:"dsn,omitempty"`
 	Params     map[string]string `json:"params,omitempty" yaml:"params,omitempty" ini:"params,omitempty"`
+
+	QueryTimeout int `json:"query_timeout,omitempty" yaml:"query_timeout,omitempty" ini:"query_timeout,omitempty"`
+
+	Replicas             []Database `json:"replicas,omitempty" yaml:"replicas,omitempty" ini:"replicas,omitempty"`
+	ReplicaCheckInterval int        `json:"replica_check_interval,omitempty" yaml:"replica_check_interval,omitempty" ini:"replica_check_interval,omitempty"`
+	ReadAfterWrite       int        `json:"read_after_write,omitempty" yaml:"read_after_write,omitempty" ini:"read_after_write,omitempty"`
+}
+
+// ReplicaConfigs return the configs of the replicas, the empty options are
+// inherited from the primary except for the Dsn.
+// 取得讀取副本的設定，未設置的選項(Dsn除外)沿用主資料庫的設定
+func (d Database) ReplicaConfigs() []Database {
+	list := make([]Database, len(d.Replicas))
+	for i, r := range d.Replicas {
+		if r.Host == "" {
+			r.Host = d.Host
+		}
+		if r.Port == "" {
+			r.Port = d.Port
+		}
+		if r.User == "" {
+			r.User = d.User
+		}
+		if r.Pwd == "" {
+			r.Pwd = d.Pwd
+		}
+		if r.Name == "" {
+			r.Name = d.Name
+		}
+		if r.MaxIdleCon == 0 {
+			r.MaxIdleCon = d.MaxIdleCon
+		}
+		if r.MaxOpenCon == 0 {
+			r.MaxOpenCon = d.MaxOpenCon
+		}
+		if r.File == "" {
+			r.File = d.File
+		}
+		if r.Params == nil && d.Params != nil {
+			r.Params = make(map[string]string, len(d.Params))
+			for k, v := range d.Params {
+				r.Params[k] = v
+			}
+		}
+		if r.QueryTimeout == 0 {
+			r.QueryTimeout = d.QueryTimeout
+		}
+		r.Driver = d.Driver
+		r.Replicas = nil
+		list[i] = r
+	}
+	return list
 }
 
 func (d Database) ParamStr() string {
@@ -304,6 +367,25 @@ type Config struct {
 	// Session valid time duration,units are seconds. Default 7200.
 	SessionLifeTime int `json:"session_life_time,omitempty" yaml:"session_life_time,omitempty" ini:"session_life_time,omitempty"`
 
+	// Session store, default is the database table goadmin_session.
+	SessionStore SessionStore `json:"session_store,omitempty" yaml:"session_store,omitempty" ini:"session_store,omitempty"`
+
+	// Csrf token valid time duration,units are seconds. Default 7200.
+	CSRFTokenLifeTime int `json:"csrf_token_life_time,omitempty" yaml:"csrf_token_life_time,omitempty" ini:"csrf_token_life_time,omitempty"`
+
+	// Store the csrf tokens with the session store, so that the tokens can be shared between instances.
+	CSRFTokenPersist bool `json:"csrf_token_persist,omitempty" yaml:"csrf_token_persist,omitempty" ini:"csrf_token_persist,omitempty"`
+
+	// Signing key of the bearer token, token authentication is disabled when it is empty.
+	// The issued tokens of a user are revoked when the password or roles are changed or the sessions are revoked.
+	AuthTokenKey string `json:"auth_token_key,omitempty" yaml:"auth_token_key,omitempty" ini:"auth_token_key,omitempty"`
+
+	// Bearer token valid time duration,units are seconds. Default 86400.
+	AuthTokenLifeTime int `json:"auth_token_life_time,omitempty" yaml:"auth_token_life_time,omitempty" ini:"auth_token_life_time,omitempty"`
+
+	// Slugs of the roles which must use the two-factor authentication, "*" means all users.
+	TOTPRequiredRoles []string `json:"totp_required_roles,omitempty" yaml:"totp_required_roles,omitempty" ini:"totp_required_roles,omitempty"`
+
 	// Assets visit link.
 	AssetUrl string `json:"asset_url,omitempty" yaml:"asset_url,omitempty" ini:"asset_url,omitempty"`
 
@@ -337,6 +419,24 @@ type Config struct {
 	// Limit login with different IPs
 	NoLimitLoginIP bool `json:"no_limit_login_ip,omitempty" yaml:"no_limit_login_ip,omitempty" ini:"no_limit_login_ip,omitempty"`
 
+	// Allow or deny the requests by the client ip, globally and per role.
+	IPAccess IPAccess `json:"ip_access,omitempty" yaml:"ip_access,omitempty" ini:"ip_access,omitempty"`
+
+	// Lock the username or the ip temporarily after too many failed login attempts.
+	LoginLockout LoginLockout `json:"login_lockout,omitempty" yaml:"login_lockout,omitempty" ini:"login_lockout,omitempty"`
+
+	// Password policy of the goadmin users.
+	PasswordPolicy PasswordPolicy `json:"password_policy,omitempty" yaml:"password_policy,omitempty" ini:"password_policy,omitempty"`
+
+	// LDAP/Active Directory authentication, used by the auth.LDAPProcessor.
+	LDAP LDAP `json:"ldap,omitempty" yaml:"ldap,omitempty" ini:"ldap,omitempty"`
+
+	// OpenID Connect providers of the single sign-on, a login button is shown for each provider.
+	OIDCProviders []OIDCProvider `json:"oidc_providers,omitempty" yaml:"oidc_providers,omitempty" ini:"oidc_providers,omitempty"`
+
+	// Only allow the single sign-on login, the password login is disabled.
+	SSOOnly bool `json:"sso_only,omitempty" yaml:"sso_only,omitempty" ini:"sso_only,omitempty"`
+
 	// When site off is true, website will be closed
 	SiteOff bool `json:"site_off,omitempty" yaml:"site_off,omitempty" ini:"site_off,omitempty"`
 
@@ -419,6 +519,150 @@ func (p PageAnimation) JSON() string {
 	return utils.JSON(p)
 }
 
+// SessionStore is the config of the session persistence driver.
+// session儲存方式，Driver可以為db(預設)、memory、file、redis
+// Path為file的儲存資料夾，Addr、Password、DB為redis的連線設置，Prefix為redis key的前綴
+// 所有驅動皆支援依用戶撤銷session，但session列表(/info/session)只支援db
+type SessionStore struct {
+	Driver   string `json:"driver,omitempty" yaml:"driver,omitempty" ini:"driver,omitempty"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty" ini:"path,omitempty"`
+	Addr     string `json:"addr,omitempty" yaml:"addr,omitempty" ini:"addr,omitempty"`
+	Password string `json:"password,omitempty" yaml:"password,omitempty" ini:"password,omitempty"`
+	DB       int    `json:"db,omitempty" yaml:"db,omitempty" ini:"db,omitempty"`
+	Prefix   string `json:"prefix,omitempty" yaml:"prefix,omitempty" ini:"prefix,omitempty"`
+}
+
+// LoginLockout is the config of the login throttling.
+// 登入失敗的限制，MaxUserFailures、MaxIPFailures分別為同一帳號、同一ip在FailureWindow(秒)內允許的失敗次數(0為不限制)
+// 每次失敗後需等待的時間以指數增加(1、2、4...秒)，達到失敗次數後鎖定LockoutTime(秒)，再次鎖定時鎖定時間加倍
+type LoginLockout struct {
+	MaxUserFailures int `json:"max_user_failures,omitempty" yaml:"max_user_failures,omitempty" ini:"max_user_failures,omitempty"`
+	MaxIPFailures   int `json:"max_ip_failures,omitempty" yaml:"max_ip_failures,omitempty" ini:"max_ip_failures,omitempty"`
+	LockoutTime     int `json:"lockout_time,omitempty" yaml:"lockout_time,omitempty" ini:"lockout_time,omitempty"`
+	FailureWindow   int `json:"failure_window,omitempty" yaml:"failure_window,omitempty" ini:"failure_window,omitempty"`
+}
+
+// Enabled check the login lockout is enabled or not.
+func (l LoginLockout) Enabled() bool {
+	return l.MaxUserFailures > 0 || l.MaxIPFailures > 0
+}
+
+// IPAccess is the config of the ip access rules, the items of the lists are CIDRs or single ips.
+// 依用戶端ip限制存取，Allow、Deny為CIDR(或單一ip)列表，符合Deny的ip一律拒絕，設置Allow時只有符合的ip可以存取
+// RoleRules為各角色的規則，ip不符合時無法使用該角色(以及繼承該角色的下層角色)的權限
+// 只有來自TrustedProxies的請求才會採用X-Forwarded-For、X-Real-Ip，否則一律使用連線的ip
+type IPAccess struct {
+	Allow          []string     `json:"allow,omitempty" yaml:"allow,omitempty" ini:"allow,omitempty"`
+	Deny           []string     `json:"deny,omitempty" yaml:"deny,omitempty" ini:"deny,omitempty"`
+	TrustedProxies []string     `json:"trusted_proxies,omitempty" yaml:"trusted_proxies,omitempty" ini:"trusted_proxies,omitempty"`
+	RoleRules      []IPRoleRule `json:"role_rules,omitempty" yaml:"role_rules,omitempty" ini:"role_rules,omitempty"`
+}
+
+// IPRoleRule is the ip access rule of the role(slug).
+type IPRoleRule struct {
+	Role  string   `json:"role,omitempty" yaml:"role,omitempty" ini:"role,omitempty"`
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty" ini:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty" yaml:"deny,omitempty" ini:"deny,omitempty"`
+}
+
+// Enabled check the ip access rules are set or not.
+func (i IPAccess) Enabled() bool {
+	return len(i.Allow) > 0 || len(i.Deny) > 0 || len(i.RoleRules) > 0
+}
+
+// Validate check all the CIDRs of the rules can be parsed.
+func (i IPAccess) Validate() error {
+	lists := [][]string{i.Allow, i.Deny, i.TrustedProxies}
+	for _, rule := range i.RoleRules {
+		if rule.Role == "" {
+			return errors.New("ip access: empty role of the role rule")
+		}
+		lists = append(lists, rule.Allow, rule.Deny)
+	}
+	for _, list := range lists {
+		if _, err := utils.ParseIPNets(list); err != nil {
+			return fmt.Errorf("ip access: %v", err)
+		}
+	}
+	return nil
+}
+
+// PasswordPolicy is the config of the password policy.
+// 密碼規則，MinLength為最小長度，Require開頭的設置為必須包含的字元類型，HistorySize為不可與最近幾次的密碼重複(0為不限制)，
+// MaxAge為密碼有效天數(0為不限制)，過期的用戶登入時必須先修改密碼
+type PasswordPolicy struct {
+	MinLength     int  `json:"min_length,omitempty" yaml:"min_length,omitempty" ini:"min_length,omitempty"`
+	RequireUpper  bool `json:"require_upper,omitempty" yaml:"require_upper,omitempty" ini:"require_upper,omitempty"`
+	RequireLower  bool `json:"require_lower,omitempty" yaml:"require_lower,omitempty" ini:"require_lower,omitempty"`
+	RequireDigit  bool `json:"require_digit,omitempty" yaml:"require_digit,omitempty" ini:"require_digit,omitempty"`
+	RequireSymbol bool `json:"require_symbol,omitempty" yaml:"require_symbol,omitempty" ini:"require_symbol,omitempty"`
+	HistorySize   int  `json:"history_size,omitempty" yaml:"history_size,omitempty" ini:"history_size,omitempty"`
+	MaxAge        int  `json:"max_age,omitempty" yaml:"max_age,omitempty" ini:"max_age,omitempty"`
+}
+
+// LDAP is the config of the LDAP/Active Directory authentication.
+// Url為目錄服務位址(ldap://或ldaps://)，以BindDN、BindPassword(空白為不驗證)在BaseDN下用UserFilter搜尋用戶，
+// UserFilter、GroupFilter中的{username}、{dn}會替換成跳脫後的帳號及用戶DN，找到用戶後再以用戶DN及密碼驗證
+// 用戶的群組取自GroupAttribute(預設memberOf)，設置GroupBaseDN時另外以GroupFilter搜尋群組
+// 群組依GroupRoles轉換成角色(slug)，沒有任何角色時使用DefaultRoles，仍然沒有角色的用戶無法登入
+// 第一次登入時自動建立用戶並綁定DN，SyncRoles為true時每次登入都會以目錄的群組更新用戶角色
+// 同名的本地用戶需先以auth.LinkIdentity綁定DN(小寫，provider為ldap)才能登入
+type LDAP struct {
+	Url                string          `json:"url,omitempty" yaml:"url,omitempty" ini:"url,omitempty"`
+	BindDN             string          `json:"bind_dn,omitempty" yaml:"bind_dn,omitempty" ini:"bind_dn,omitempty"`
+	BindPassword       string          `json:"bind_password,omitempty" yaml:"bind_password,omitempty" ini:"bind_password,omitempty"`
+	BaseDN             string          `json:"base_dn,omitempty" yaml:"base_dn,omitempty" ini:"base_dn,omitempty"`
+	UserFilter         string          `json:"user_filter,omitempty" yaml:"user_filter,omitempty" ini:"user_filter,omitempty"`
+	NameAttribute      string          `json:"name_attribute,omitempty" yaml:"name_attribute,omitempty" ini:"name_attribute,omitempty"`
+	GroupAttribute     string          `json:"group_attribute,omitempty" yaml:"group_attribute,omitempty" ini:"group_attribute,omitempty"`
+	GroupBaseDN        string          `json:"group_base_dn,omitempty" yaml:"group_base_dn,omitempty" ini:"group_base_dn,omitempty"`
+	GroupFilter        string          `json:"group_filter,omitempty" yaml:"group_filter,omitempty" ini:"group_filter,omitempty"`
+	StartTLS           bool            `json:"start_tls,omitempty" yaml:"start_tls,omitempty" ini:"start_tls,omitempty"`
+	InsecureSkipVerify bool            `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty" ini:"insecure_skip_verify,omitempty"`
+	Timeout            int             `json:"timeout,omitempty" yaml:"timeout,omitempty" ini:"timeout,omitempty"`
+	GroupRoles         []LDAPGroupRole `json:"group_roles,omitempty" yaml:"group_roles,omitempty" ini:"group_roles,omitempty"`
+	DefaultRoles       []string        `json:"default_roles,omitempty" yaml:"default_roles,omitempty" ini:"default_roles,omitempty"`
+	SyncRoles          bool            `json:"sync_roles,omitempty" yaml:"sync_roles,omitempty" ini:"sync_roles,omitempty"`
+}
+
+// LDAPGroupRole map the directory group to the role, the group is the DN of the group or
+// the value of its first RDN, e.g. "cn=admins,ou=groups,dc=example,dc=com" or "admins".
+// 目錄群組與角色(slug)的對應，Group可以是完整DN或第一個RDN的值，不分大小寫
+type LDAPGroupRole struct {
+	Group string `json:"group,omitempty" yaml:"group,omitempty" ini:"group,omitempty"`
+	Role  string `json:"role,omitempty" yaml:"role,omitempty" ini:"role,omitempty"`
+}
+
+// OIDCProvider is the config of an OpenID Connect provider.
+// Name為url中的識別名稱(/oidc/{name}/login)，Title為登入按鈕的文字，Issuer用於取得/.well-known/openid-configuration
+// RedirectURL為空時依請求的host產生，Scopes預設為openid、profile、email
+// UsernameClaim(預設preferred_username)、NameClaim(預設name)為用戶帳號及名稱的claim
+// RoleClaim為角色的claim(例如groups)，其值依ClaimRoles轉換成角色(slug)，沒有任何角色時使用DefaultRoles
+// 第一次登入時自動建立用戶並綁定sub，SyncRoles為true時每次登入都會更新用戶角色
+// 同名的本地用戶需先以auth.LinkIdentity綁定sub(provider為oidc:{name})才能登入
+type OIDCProvider struct {
+	Name          string          `json:"name,omitempty" yaml:"name,omitempty" ini:"name,omitempty"`
+	Title         string          `json:"title,omitempty" yaml:"title,omitempty" ini:"title,omitempty"`
+	Issuer        string          `json:"issuer,omitempty" yaml:"issuer,omitempty" ini:"issuer,omitempty"`
+	ClientID      string          `json:"client_id,omitempty" yaml:"client_id,omitempty" ini:"client_id,omitempty"`
+	ClientSecret  string          `json:"client_secret,omitempty" yaml:"client_secret,omitempty" ini:"client_secret,omitempty"`
+	RedirectURL   string          `json:"redirect_url,omitempty" yaml:"redirect_url,omitempty" ini:"redirect_url,omitempty"`
+	Scopes        []string        `json:"scopes,omitempty" yaml:"scopes,omitempty" ini:"scopes,omitempty"`
+	UsernameClaim string          `json:"username_claim,omitempty" yaml:"username_claim,omitempty" ini:"username_claim,omitempty"`
+	NameClaim     string          `json:"name_claim,omitempty" yaml:"name_claim,omitempty" ini:"name_claim,omitempty"`
+	RoleClaim     string          `json:"role_claim,omitempty" yaml:"role_claim,omitempty" ini:"role_claim,omitempty"`
+	ClaimRoles    []OIDCClaimRole `json:"claim_roles,omitempty" yaml:"claim_roles,omitempty" ini:"claim_roles,omitempty"`
+	DefaultRoles  []string        `json:"default_roles,omitempty" yaml:"default_roles,omitempty" ini:"default_roles,omitempty"`
+	SyncRoles     bool            `json:"sync_roles,omitempty" yaml:"sync_roles,omitempty" ini:"sync_roles,omitempty"`
+}
+
+// OIDCClaimRole map the value of the role claim to the role.
+// RoleClaim的值與角色(slug)的對應
+type OIDCClaimRole struct {
+	Value string `json:"value,omitempty" yaml:"value,omitempty" ini:"value,omitempty"`
+	Role  string `json:"role,omitempty" yaml:"role,omitempty" ini:"role,omitempty"`
+}
+
 // FileUploadEngine is a file upload engine.
 // 文件上傳引擎
 type FileUploadEngine struct {
@@ -570,6 +814,12 @@ func (c *Config) Copy() *Config {
 		ErrorLogOff:                   c.ErrorLogOff,
 		ColorScheme:                   c.ColorScheme,
 		SessionLifeTime:               c.SessionLifeTime,
+		SessionStore:                  c.SessionStore,
+		CSRFTokenLifeTime:             c.CSRFTokenLifeTime,
+		CSRFTokenPersist:              c.CSRFTokenPersist,
+		AuthTokenKey:                  c.AuthTokenKey,
+		AuthTokenLifeTime:             c.AuthTokenLifeTime,
+		TOTPRequiredRoles:             c.TOTPRequiredRoles,
 		AssetUrl:                      c.AssetUrl,
 		FileUploadEngine:              c.FileUploadEngine,
 		CustomHeadHtml:                c.CustomHeadHtml,
@@ -581,6 +831,12 @@ func (c *Config) Copy() *Config {
 		Extra:                         c.Extra,
 		Animation:                     c.Animation,
 		NoLimitLoginIP:                c.NoLimitLoginIP,
+		IPAccess:                      c.IPAccess,
+		LoginLockout:                  c.LoginLockout,
+		PasswordPolicy:                c.PasswordPolicy,
+		LDAP:                          c.LDAP,
+		OIDCProviders:                 c.OIDCProviders,
+		SSOOnly:                       c.SSOOnly,
 		Logger:                        c.Logger,
 		SiteOff:                       c.SiteOff,
 		HideConfigCenterEntrance:      c.HideConfigCenterEntrance,
@@ -775,6 +1031,8 @@ func (c *Config) EraseSens() *Config {
 			Driver: c.Databases[key].Driver,
 		}
 	}
+	c.AuthTokenKey = ""
+	c.SessionStore.Password = ""
 	return c
 }
 
@@ -878,6 +1136,55 @@ func SetDefault(cfg Config) Config {
 		// default two hours
 		cfg.SessionLifeTime = 7200
 	}
+	if cfg.CSRFTokenLifeTime == 0 {
+		// default two hours
+		cfg.CSRFTokenLifeTime = 7200
+	}
+	if cfg.AuthTokenLifeTime == 0 {
+		// default one day
+		cfg.AuthTokenLifeTime = 86400
+	}
+	if cfg.LoginLockout.LockoutTime == 0 {
+		// default fifteen minutes
+		cfg.LoginLockout.LockoutTime = 900
+	}
+	if cfg.LoginLockout.FailureWindow == 0 {
+		// default one hour
+		cfg.LoginLockout.FailureWindow = 3600
+	}
+	if cfg.LDAP.Url != "" {
+		if cfg.LDAP.UserFilter == "" {
+			cfg.LDAP.UserFilter = "(uid={username})"
+		}
+		if cfg.LDAP.NameAttribute == "" {
+			cfg.LDAP.NameAttribute = "cn"
+		}
+		if cfg.LDAP.GroupAttribute == "" {
+			cfg.LDAP.GroupAttribute = "memberOf"
+		}
+		if cfg.LDAP.GroupBaseDN != "" && cfg.LDAP.GroupFilter == "" {
+			cfg.LDAP.GroupFilter = "(|(member={dn})(uniqueMember={dn}))"
+		}
+		if cfg.LDAP.Timeout == 0 {
+			cfg.LDAP.Timeout = 10
+		}
+	}
+	cfg.OIDCProviders = append([]OIDCProvider(nil), cfg.OIDCProviders...)
+	for i := range cfg.OIDCProviders {
+		p := &cfg.OIDCProviders[i]
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "profile", "email"}
+		}
+		if p.UsernameClaim == "" {
+			p.UsernameClaim = "preferred_username"
+		}
+		if p.NameClaim == "" {
+			p.NameClaim = "name"
+		}
+		if p.Title == "" {
+			p.Title = p.Name
+		}
+	}
 	return cfg
 }
 
@@ -896,6 +1203,10 @@ func Set(cfg Config) *Config {
 
 	cfg = SetDefault(cfg)
 
+	if err := cfg.IPAccess.Validate(); err != nil {
+		panic(err)
+	}
+
 	//global url前綴
 	if cfg.UrlPrefix == "" {
 		cfg.prefix = "/"
@@ -1125,6 +1436,64 @@ func GetSessionLifeTime() int {
 	return globalCfg.SessionLifeTime
 }
 
+func GetSessionStore() SessionStore {
+	return globalCfg.SessionStore
+}
+
+func GetCSRFTokenLifeTime() int {
+	return globalCfg.CSRFTokenLifeTime
+}
+
+func GetCSRFTokenPersist() bool {
+	return globalCfg.CSRFTokenPersist
+}
+
+func GetAuthTokenKey() string {
+	return globalCfg.AuthTokenKey
+}
+
+func GetAuthTokenLifeTime() int {
+	return globalCfg.AuthTokenLifeTime
+}
+
+func GetTOTPRequiredRoles() []string {
+	return globalCfg.TOTPRequiredRoles
+}
+
+func GetIPAccess() IPAccess {
+	return globalCfg.IPAccess
+}
+
+func GetLoginLockout() LoginLockout {
+	return globalCfg.LoginLockout
+}
+
+func GetPasswordPolicy() PasswordPolicy {
+	return globalCfg.PasswordPolicy
+}
+
+func GetLDAP() LDAP {
+	return globalCfg.LDAP
+}
+
+func GetOIDCProviders() []OIDCProvider {
+	return globalCfg.OIDCProviders
+}
+
+// GetOIDCProvider return the OpenID Connect provider of the name.
+func GetOIDCProvider(name string) (OIDCProvider, bool) {
+	for _, p := range globalCfg.OIDCProviders {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return OIDCProvider{}, false
+}
+
+func GetSSOOnly() bool {
+	return globalCfg.SSOOnly
+}
+
 func GetAssetUrl() string {
 	return globalCfg.AssetUrl
 }
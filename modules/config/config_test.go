@@ -103,6 +103,22 @@ func TestSet(t *testing.T) {
 	assert.Equal(t, Get().Theme, "bcd")
 }
 
+func TestIPAccess_Validate(t *testing.T) {
+	assert.Equal(t, IPAccess{}.Enabled(), false)
+	assert.Equal(t, IPAccess{TrustedProxies: []string{"10.0.0.1"}}.Enabled(), false)
+
+	rules := IPAccess{
+		Deny:      []string{"1.2.3.4"},
+		RoleRules: []IPRoleRule{{Role: "finance", Allow: []string{"10.0.0.0/8"}}},
+	}
+	assert.Equal(t, rules.Enabled(), true)
+	assert.Equal(t, rules.Validate(), nil)
+
+	rules.RoleRules[0].Allow = []string{"10.0.0.0/40"}
+	assert.NotEqual(t, rules.Validate(), nil)
+	assert.NotEqual(t, IPAccess{RoleRules: []IPRoleRule{{Allow: []string{"10.0.0.1"}}}}.Validate(), nil)
+}
+
 func TestStore_URL(t *testing.T) {
 	testSetCfg(Config{
 		Store: Store{
@@ -142,6 +158,30 @@ func TestDatabase_ParamStr(t *testing.T) {
 	assert.Equal(t, cfg.ParamStr(), "?charset=utf8mb4&parseTime=true")
 }
 
+func TestDatabase_ReplicaConfigs(t *testing.T) {
+	cfg := Database{
+		Driver:   DriverMysql,
+		Host:     "127.0.0.1",
+		Port:     "3306",
+		User:     "root",
+		Pwd:      "root",
+		Name:     "godmin",
+		Dsn:      "root:root@tcp(127.0.0.1:3306)/godmin",
+		Params:   map[string]string{"parseTime": "true"},
+		Replicas: []Database{{Host: "10.0.0.2"}, {Host: "10.0.0.3", User: "reader"}},
+	}
+	replicas := cfg.ReplicaConfigs()
+	assert.Equal(t, len(replicas), 2)
+	assert.Equal(t, replicas[0].Host, "10.0.0.2")
+	assert.Equal(t, replicas[0].User, "root")
+	assert.Equal(t, replicas[0].Port, "3306")
+	assert.Equal(t, replicas[0].Driver, DriverMysql)
+	assert.Equal(t, replicas[0].Params, map[string]string{"parseTime": "true"})
+	// the dsn of the primary is not inherited
+	assert.Equal(t, replicas[0].Dsn, "")
+	assert.Equal(t, replicas[1].User, "reader")
+}
+
 func TestReadFromYaml(t *testing.T) {
 	cfg := ReadFromYaml("./config.yaml")
 	assert.Equal(t, cfg.Databases.GetDefault().Driver, "mssql")
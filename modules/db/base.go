@@ -1,14 +1,22 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
 )
 
 // Base is a common Connection.
 type Base struct {
-	DbList map[string]*sql.DB
-	Once   sync.Once
+	DbList   map[string]*sql.DB
+	Timeouts map[string]time.Duration
+	Once     sync.Once
+
+	// 連接的讀取副本(replica.go)
+	replicas map[string]*replicaSet
 }
 
 // Close implements the method Connection.Close.
@@ -18,11 +26,42 @@ func (db *Base) Close() []error {
 	for _, d := range db.DbList {
 		errs = append(errs, d.Close())
 	}
+	for _, set := range db.replicas {
+		errs = append(errs, set.close()...)
+	}
 	return errs
 }
 
-// GetDB implements the method Connection.GetDB.
-// 藉由參數key取得Base.DbList[key]
+// GetDB implements the method Connection.GetDB, it return the primary of the connection.
+// 藉由參數key取得Base.DbList[key](主資料庫)
 func (db *Base) GetDB(key string) *sql.DB {
 	return db.DbList[key]
 }
+
+// 設置連接的預設查詢逾時時間(config.Database.QueryTimeout)
+func (db *Base) setTimeout(conn string, cfg config.Database) {
+	if cfg.QueryTimeout <= 0 {
+		return
+	}
+	if db.Timeouts == nil {
+		db.Timeouts = make(map[string]time.Duration)
+	}
+	db.Timeouts[conn] = time.Duration(cfg.QueryTimeout) * time.Second
+}
+
+// withTimeout return the context with the default timeout of the connection, the deadline
+// of ctx is kept if it is earlier.
+// 加上連接的預設逾時時間，如果ctx的期限較早則保留原本的期限
+func (db *Base) withTimeout(ctx context.Context, conn string) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timeout := db.Timeouts[conn]
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= timeout {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,34 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestBase_WithTimeout(t *testing.T) {
+	base := Base{}
+	base.setTimeout("default", config.Database{QueryTimeout: 10})
+	base.setTimeout("other", config.Database{})
+
+	ctx, cancel := base.withTimeout(context.Background(), "other")
+	_, ok := ctx.Deadline()
+	assert.Equal(t, ok, false)
+	cancel()
+
+	ctx, cancel = base.withTimeout(context.Background(), "default")
+	deadline, ok := ctx.Deadline()
+	assert.Equal(t, ok, true)
+	assert.Equal(t, time.Until(deadline) <= 10*time.Second, true)
+	cancel()
+
+	// the earlier deadline of the given context is kept
+	parent, parentCancel := context.WithTimeout(context.Background(), time.Second)
+	defer parentCancel()
+	ctx, cancel = base.withTimeout(parent, "default")
+	assert.Equal(t, ctx, parent)
+	cancel()
+}
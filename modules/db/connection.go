@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -48,6 +49,43 @@ type Connection interface {
 
 	ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error)
 
+	// QueryRows is the streaming query method of sql, the rows are read one by one and must
+	// be closed after used.
+	// 逐筆讀取的查詢，適用於大量資料
+	QueryRows(ctx context.Context, query string, args ...interface{}) (*Rows, error)
+
+	// QueryRowsWithConnection is the streaming query method with given connection of sql.
+	QueryRowsWithConnection(ctx context.Context, conn, query string, args ...interface{}) (*Rows, error)
+
+	// QueryRowsWithTx is the streaming query method within the transaction.
+	QueryRowsWithTx(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*Rows, error)
+
+	// QueryContext is the query method of sql with the context, the default timeout of the
+	// connection(config.Database.QueryTimeout) is applied.
+	// 查詢(ctx取消或逾時時中止查詢)
+	QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// ExecContext is the exec method of sql with the context.
+	// 執行(ctx取消或逾時時中止執行)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+
+	// QueryWithConnectionContext is the query method with given connection and context of sql.
+	QueryWithConnectionContext(ctx context.Context, conn, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// ExecWithConnectionContext is the exec method with given connection and context of sql.
+	ExecWithConnectionContext(ctx context.Context, conn, query string, args ...interface{}) (sql.Result, error)
+
+	// QueryWithTxContext is the query method with the context within the transaction, only the
+	// deadline of the context is applied.
+	QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error)
+
+	// ExecWithTxContext is the exec method with the context within the transaction.
+	ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error)
+
+	// BeginTxWithContext starts a transaction of given connection and isolation level, the
+	// transaction is rolled back when the context is done.
+	BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error)
+
 	BeginTxWithReadUncommitted() *sql.Tx
 	BeginTxWithReadCommitted() *sql.Tx
 	BeginTxWithRepeatableRead() *sql.Tx
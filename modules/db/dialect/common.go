@@ -31,9 +31,12 @@ func (c commonDialect) Count(comp *SQLComponent) string {
 }
 
 // 取得資料
+// union時排序及筆數限制作用於合併後的結果
 func (c commonDialect) Select(comp *SQLComponent) string {
-	comp.Statement = "select " + comp.getFields(c.delimiter) + " from " + comp.TableName + comp.getJoins(c.delimiter) +
-		comp.getWheres(c.delimiter) + comp.getGroupBy() + comp.getOrderBy() + comp.getLimit() + comp.getOffset()
+	comp.prepareSelectArgs()
+	comp.Statement = "select " + comp.getDistinct() + comp.getFields(c.delimiter) + " from " + comp.TableName +
+		comp.getJoins(c.delimiter) + comp.getWheres(c.delimiter) + comp.getGroupBy() + comp.getHaving(c.delimiter) +
+		comp.getUnions() + comp.getOrderBy() + comp.getLimit() + comp.getOffset()
 	return comp.Statement
 }
 
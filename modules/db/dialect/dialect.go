@@ -87,25 +87,73 @@ type SQLComponent struct {
 	WhereRaws  string
 	UpdateRaws []RawUpdate
 	Group      string
+	Havings    []Where
+	Unions     []Union
+	Distinct   bool
 	Statement  string
 	Values     H
+
+	// 子查詢(資料表、join)、having及union的參數，產生select語法時依語法的順序與where的參數(Args)合併
+	TableArgs  []interface{}
+	JoinArgs   []interface{}
+	HavingArgs []interface{}
+	UnionArgs  []interface{}
 }
 
-// Where contains the operation and field.
+// The conjunctions of the conditions.
+const (
+	ConjAnd = "and"
+	ConjOr  = "or"
+)
+
+// Where contains the operation and field. The conditions of the Group are wrapped in
+// parentheses and the Raw condition is used as it is.
+// Conj為與前一個條件的連接(and、or)，空值為and
 type Where struct {
 	Operation string
 	Field     string
 	Qmark     string
+	Conj      string
+	Group     []Where
+	Raw       string
 }
 
-// Join contains the table and field and operation.
+// The types of the join.
+const (
+	JoinLeft  = "left"
+	JoinInner = "inner"
+	JoinRight = "right"
+	JoinCross = "cross"
+)
+
+// Join contains the table and field and operation. The Type is left join if empty, and the
+// Table is a subquery statement if Sub is true.
+// 除了第一個on條件之外，其他的on條件設置於Ons
 type Join struct {
+	Type      string
 	Table     string
+	Alias     string
+	Sub       bool
+	FieldA    string
+	Operation string
+	FieldB    string
+	Ons       []JoinOn
+}
+
+// JoinOn is the other condition of the join.
+type JoinOn struct {
+	Conj      string
 	FieldA    string
 	Operation string
 	FieldB    string
 }
 
+// Union contains the select statement to union.
+type Union struct {
+	All       bool
+	Statement string
+}
+
 // RawUpdate contains the expression and arguments.
 // 表達式及參數
 type RawUpdate struct {
@@ -149,6 +197,35 @@ func (sql *SQLComponent) getGroupBy() string {
 	return " group by " + sql.Group + " "
 }
 
+// 分組後的篩選條件
+func (sql *SQLComponent) getHaving(delimiter string) string {
+	if len(sql.Havings) == 0 {
+		return ""
+	}
+	return " having " + getConditions(delimiter, sql.Havings) + " "
+}
+
+// select distinct
+func (sql *SQLComponent) getDistinct() string {
+	if sql.Distinct {
+		return "distinct "
+	}
+	return ""
+}
+
+// 合併其他select語法的結果
+func (sql *SQLComponent) getUnions() string {
+	unions := ""
+	for _, union := range sql.Unions {
+		if union.All {
+			unions += " union all " + union.Statement
+		} else {
+			unions += " union " + union.Statement
+		}
+	}
+	return unions
+}
+
 // join其他表
 func (sql *SQLComponent) getJoins(delimiter string) string {
 	if len(sql.Leftjoins) == 0 {
@@ -156,11 +233,47 @@ func (sql *SQLComponent) getJoins(delimiter string) string {
 	}
 	joins := ""
 	for _, join := range sql.Leftjoins {
-		joins += " left join " + wrap(delimiter, join.Table) + " on " + join.FieldA + " " + join.Operation + " " + join.FieldB + " "
+		typ := join.Type
+		if typ == "" {
+			typ = JoinLeft
+		}
+		table := wrap(delimiter, join.Table)
+		if join.Sub {
+			table = "(" + join.Table + ")"
+		}
+		if join.Alias != "" {
+			table += " as " + wrap(delimiter, join.Alias)
+		}
+		joins += " " + typ + " join " + table
+		if typ == JoinCross {
+			joins += " "
+			continue
+		}
+		joins += " on " + join.FieldA + " " + join.Operation + " " + join.FieldB
+		for _, on := range join.Ons {
+			joins += " " + conj(on.Conj) + " " + on.FieldA + " " + on.Operation + " " + on.FieldB
+		}
+		joins += " "
 	}
 	return joins
 }
 
+// 合併select語法中各部分的參數(子查詢的資料表、join、where、having、union)，合併後清空避免重複加入
+func (sql *SQLComponent) prepareSelectArgs() {
+	if len(sql.TableArgs) == 0 && len(sql.JoinArgs) == 0 && len(sql.HavingArgs) == 0 && len(sql.UnionArgs) == 0 {
+		return
+	}
+	args := make([]interface{}, 0, len(sql.TableArgs)+len(sql.JoinArgs)+len(sql.Args)+
+		len(sql.HavingArgs)+len(sql.UnionArgs))
+	args = append(args, sql.TableArgs...)
+	args = append(args, sql.JoinArgs...)
+	args = append(args, sql.Args...)
+	args = append(args, sql.HavingArgs...)
+	args = append(args, sql.UnionArgs...)
+	sql.Args = args
+	sql.TableArgs, sql.JoinArgs, sql.HavingArgs, sql.UnionArgs = nil, nil, nil, nil
+}
+
 // 取得特定欄位
 func (sql *SQLComponent) getFields(delimiter string) string {
 	if len(sql.Fields) == 0 {
@@ -198,6 +311,44 @@ func wrap(delimiter, field string) string {
 	return delimiter + field + delimiter
 }
 
+// 條件的欄位，table.field只包裝欄位名稱，函式(ex: count(id))不包裝
+func wrapField(delimiter, field string) string {
+	if strings.Contains(field, "(") {
+		return field
+	}
+	arr := strings.Split(field, ".")
+	if len(arr) > 1 {
+		return arr[0] + "." + wrap(delimiter, arr[1])
+	}
+	return wrap(delimiter, field)
+}
+
+func conj(c string) string {
+	if c == "" {
+		return ConjAnd
+	}
+	return c
+}
+
+// 產生條件語法，Group的條件以括號包住，Raw直接使用
+func getConditions(delimiter string, conditions []Where) string {
+	res := ""
+	for i, where := range conditions {
+		if i > 0 {
+			res += " " + conj(where.Conj) + " "
+		}
+		switch {
+		case where.Raw != "":
+			res += where.Raw
+		case where.Group != nil:
+			res += "(" + getConditions(delimiter, where.Group) + ")"
+		default:
+			res += wrapField(delimiter, where.Field) + " " + where.Operation + " " + where.Qmark
+		}
+	}
+	return res
+}
+
 // where = ...
 func (sql *SQLComponent) getWheres(delimiter string) string {
 	if len(sql.Wheres) == 0 {
@@ -206,22 +357,12 @@ func (sql *SQLComponent) getWheres(delimiter string) string {
 		}
 		return ""
 	}
-	wheres := " where "
-	var arr []string
-	for _, where := range sql.Wheres {
-		//arr[0]為資料表名稱
-		arr = strings.Split(where.Field, ".")
-		if len(arr) > 1 {
-			wheres += arr[0] + "." + wrap(delimiter, arr[1]) + " " + where.Operation + " " + where.Qmark + " and "
-		} else {
-			wheres += wrap(delimiter, where.Field) + " " + where.Operation + " " + where.Qmark + " and "
-		}
-	}
+	wheres := " where " + getConditions(delimiter, sql.Wheres)
 
 	if sql.WhereRaws != "" {
-		return wheres + sql.WhereRaws
+		return wheres + " and " + sql.WhereRaws
 	}
-	return wheres[:len(wheres)-5]
+	return wheres
 }
 
 // update語法
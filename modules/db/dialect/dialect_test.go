@@ -0,0 +1,85 @@
+package dialect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelect(t *testing.T) {
+	comp := &SQLComponent{
+		Fields:    []string{"goadmin_users.id", "goadmin_users.name"},
+		Functions: []string{"", ""},
+		TableName: "goadmin_users",
+		Leftjoins: []Join{
+			{Type: JoinInner, Table: "goadmin_role_users", FieldA: "goadmin_role_users.user_id", Operation: "=",
+				FieldB: "goadmin_users.id", Ons: []JoinOn{{FieldA: "goadmin_role_users.role_id", Operation: ">", FieldB: "0"}}},
+			{Table: "select user_id from goadmin_session", Sub: true, Alias: "s", FieldA: "s.user_id", Operation: "=",
+				FieldB: "goadmin_users.id"},
+		},
+		Wheres: []Where{
+			{Field: "goadmin_users.id", Operation: ">", Qmark: "?"},
+			{Conj: ConjOr, Group: []Where{
+				{Field: "name", Operation: "=", Qmark: "?"},
+				{Conj: ConjOr, Raw: "exists (select 1)"},
+			}},
+		},
+		Args:       []interface{}{1, "jack"},
+		Group:      " `goadmin_users`.`id`",
+		Havings:    []Where{{Field: "count(goadmin_users.id)", Operation: ">", Qmark: "?"}},
+		HavingArgs: []interface{}{2},
+		Unions:     []Union{{All: true, Statement: "select id, name from goadmin_roles where id = ?"}},
+		UnionArgs:  []interface{}{3},
+		Distinct:   true,
+		Order:      " `id` desc",
+	}
+
+	GetDialectByDriver("mysql").Select(comp)
+
+	assert.Equal(t, "select distinct goadmin_users.`id`,goadmin_users.`name` from goadmin_users"+
+		" inner join `goadmin_role_users` on goadmin_role_users.user_id = goadmin_users.id and goadmin_role_users.role_id > 0 "+
+		" left join (select user_id from goadmin_session) as `s` on s.user_id = goadmin_users.id "+
+		" where goadmin_users.`id` > ? or (`name` = ? or exists (select 1))"+
+		" group by  `goadmin_users`.`id`  having count(goadmin_users.id) > ? "+
+		" union all select id, name from goadmin_roles where id = ? order by  `id` desc ", comp.Statement)
+	assert.Equal(t, []interface{}{1, "jack", 2, 3}, comp.Args)
+
+	// the arguments are merged only once
+	GetDialectByDriver("mysql").Select(comp)
+	assert.Equal(t, []interface{}{1, "jack", 2, 3}, comp.Args)
+}
+
+func TestSelectByDriver(t *testing.T) {
+	newComp := func() *SQLComponent {
+		return &SQLComponent{
+			TableName: "(select id from goadmin_users) as t",
+			TableArgs: []interface{}{"a"},
+			Leftjoins: []Join{{Type: JoinCross, Table: "goadmin_roles"}},
+			Wheres:    []Where{{Field: "t.id", Operation: "=", Qmark: "?"}},
+			Args:      []interface{}{1},
+		}
+	}
+
+	comp := newComp()
+	GetDialectByDriver("mssql").Select(comp)
+	assert.Equal(t, "select * from (select id from goadmin_users) as t cross join [goadmin_roles]  where t.[id] = ?",
+		comp.Statement)
+	assert.Equal(t, []interface{}{"a", 1}, comp.Args)
+
+	comp = newComp()
+	GetDialectByDriver("postgresql").Select(comp)
+	assert.Equal(t, `select * from (select id from goadmin_users) as t cross join "goadmin_roles"  where t."id" = ?`,
+		comp.Statement)
+}
+
+func TestWheresWithRaw(t *testing.T) {
+	comp := &SQLComponent{
+		TableName: "goadmin_users",
+		Wheres:    []Where{{Field: "id", Operation: "=", Qmark: "?"}},
+		WhereRaws: "name is not null",
+	}
+	assert.Equal(t, " where `id` = ? and name is not null", comp.getWheres("`"))
+
+	comp.Wheres = nil
+	assert.Equal(t, " where name is not null", comp.getWheres("`"))
+}
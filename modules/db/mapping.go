@@ -0,0 +1,410 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+const mappingTag = "db"
+
+type mappingField struct {
+	column    string
+	index     []int
+	omitempty bool
+	readonly  bool
+}
+
+var (
+	mappingCache sync.Map
+
+	scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType  = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	timeType    = reflect.TypeOf(time.Time{})
+
+	timeLayouts = []string{"2006-01-02 15:04:05", time.RFC3339Nano, "2006-01-02T15:04:05",
+		"2006-01-02 15:04:05.999999999-07:00", "2006-01-02"}
+)
+
+// ScanStruct set the fields of the struct pointed by dest with the values of the row.
+//
+// The fields are mapped to the columns by the tag "db":
+//
+//	type User struct {
+//		Id        int64          `db:"id,omitempty"`        // skipped when it is zero while inserting or updating
+//		UserName  string         `db:"username"`            // the column is username
+//		Avatar    sql.NullString                            // the column is avatar
+//		CreatedAt time.Time      `db:"created_at,readonly"` // never inserted or updated
+//		Roles     []Role         `db:"-"`                   // ignored
+//	}
+//
+// The fields without the tag are mapped by the snake case of the field name, the embedded
+// structs are flattened. The values are converted from the result of the query which has
+// been converted by the DatabaseType(converter.go), and the types which implement
+// sql.Scanner or driver.Valuer such as sql.NullString are supported.
+// 將查詢結果(單筆)的數值設置至dest指向的結構，以tag(db)對應結構的欄位與資料表的欄位，沒有tag時使用欄位名稱的蛇形命名
+func ScanStruct(row map[string]interface{}, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("wrong destination: must be a pointer of struct")
+	}
+	return scanStruct(row, v.Elem())
+}
+
+// ScanStructs set the slice pointed by dest with the rows, the element of the slice can be
+// a struct or a pointer of struct.
+// 將查詢結果(多筆)設置至dest指向的slice
+func ScanStructs(rows []map[string]interface{}, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice {
+		return errors.New("wrong destination: must be a pointer of slice")
+	}
+
+	var (
+		slice    = v.Elem()
+		elemType = slice.Type().Elem()
+		isPtr    = elemType.Kind() == reflect.Ptr
+	)
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return errors.New("wrong destination: the element must be a struct")
+	}
+
+	list := reflect.MakeSlice(slice.Type(), 0, len(rows))
+	for _, row := range rows {
+		elem := reflect.New(elemType)
+		if err := scanStruct(row, elem.Elem()); err != nil {
+			return err
+		}
+		if isPtr {
+			list = reflect.Append(list, elem)
+		} else {
+			list = reflect.Append(list, elem.Elem())
+		}
+	}
+	slice.Set(list)
+	return nil
+}
+
+// StructToH return the column/value pairs of the struct which can be used to insert or update,
+// the readonly fields and the zero omitempty fields are skipped.
+// 將結構轉換為dialect.H(新增、更新時使用)，略過readonly及零值的omitempty欄位
+func StructToH(value interface{}) (dialect.H, error) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, errors.New("wrong value: nil pointer")
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, errors.New("wrong value: must be a struct")
+	}
+
+	h := make(dialect.H)
+	for _, field := range mappingFields(v.Type()) {
+		if field.readonly {
+			continue
+		}
+		fv := v.FieldByIndex(field.index)
+		if field.omitempty && fv.IsZero() {
+			continue
+		}
+		val, err := fieldValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %v", field.column, err)
+		}
+		h[field.column] = val
+	}
+	return h, nil
+}
+
+func scanStruct(row map[string]interface{}, v reflect.Value) error {
+	lowerRow := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		lowerRow[strings.ToLower(key)] = value
+	}
+
+	for _, field := range mappingFields(v.Type()) {
+		value, ok := row[field.column]
+		if !ok {
+			// mssql及oracle等的欄位名稱可能為大寫
+			if value, ok = lowerRow[strings.ToLower(field.column)]; !ok {
+				continue
+			}
+		}
+		if err := setValue(v.FieldByIndex(field.index), value); err != nil {
+			return fmt.Errorf("column %s: %v", field.column, err)
+		}
+	}
+	return nil
+}
+
+// 取得結構中對應資料表欄位的欄位(有快取)
+func mappingFields(t reflect.Type) []mappingField {
+	if fields, ok := mappingCache.Load(t); ok {
+		return fields.([]mappingField)
+	}
+	fields := parseMappingFields(t, nil)
+	mappingCache.Store(t, fields)
+	return fields
+}
+
+func parseMappingFields(t reflect.Type, parent []int) []mappingField {
+	fields := make([]mappingField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(mappingTag)
+		// 未導出的欄位略過，但未導出的嵌入結構仍展開其導出的欄位
+		if tag == "-" || (sf.PkgPath != "" && !(sf.Anonymous && sf.Type.Kind() == reflect.Struct)) {
+			continue
+		}
+
+		index := make([]int, len(parent)+1)
+		copy(index, parent)
+		index[len(parent)] = i
+
+		// 嵌入的結構展開其欄位
+		if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct && !isColumnType(sf.Type) {
+			fields = append(fields, parseMappingFields(sf.Type, index)...)
+			continue
+		}
+
+		if sf.PkgPath != "" || !isColumnType(sf.Type) {
+			continue
+		}
+
+		field := mappingField{index: index}
+		options := strings.Split(tag, ",")
+		field.column = strings.TrimSpace(options[0])
+		if field.column == "" {
+			field.column = snakeCase(sf.Name)
+		}
+		for _, option := range options[1:] {
+			switch strings.TrimSpace(option) {
+			case "omitempty":
+				field.omitempty = true
+			case "readonly":
+				field.readonly = true
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// 判斷類別是否可以對應資料表欄位
+func isColumnType(t reflect.Type) bool {
+	if t == timeType || reflect.PtrTo(t).Implements(scannerType) || t.Implements(valuerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Ptr:
+		return isColumnType(t.Elem())
+	}
+	return false
+}
+
+// UserId => user_id, HTTPPath => http_path
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// 將查詢結果的數值轉換並設置至結構的欄位
+func setValue(v reflect.Value, value interface{}) error {
+	if v.CanAddr() {
+		if scanner, ok := v.Addr().Interface().(sql.Scanner); ok {
+			return scanner.Scan(value)
+		}
+	}
+
+	if value == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	if v.Kind() == reflect.Ptr {
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+
+	if v.Type() == timeType {
+		t, err := toTime(value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(toString(value))
+	case reflect.Bool:
+		switch val := value.(type) {
+		case bool:
+			v.SetBool(val)
+		case int64:
+			v.SetBool(val != 0)
+		case float64:
+			v.SetBool(val != 0)
+		default:
+			b, err := strconv.ParseBool(toString(value))
+			if err != nil {
+				return err
+			}
+			v.SetBool(b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt64(value)
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(i) {
+			return fmt.Errorf("value %d overflows %s", i, v.Type())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := toInt64(value)
+		if err != nil {
+			return err
+		}
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return fmt.Errorf("value %d overflows %s", i, v.Type())
+		}
+		v.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat64(value)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		switch val := value.(type) {
+		case []byte:
+			v.SetBytes(append([]byte(nil), val...))
+		default:
+			v.SetBytes([]byte(toString(value)))
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func toString(value interface{}) string {
+	switch val := value.(type) {
+	case string:
+		return val
+	case []byte:
+		return string(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case time.Time:
+		return val.Format("2006-01-02 15:04:05")
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func toInt64(value interface{}) (int64, error) {
+	switch val := value.(type) {
+	case int64:
+		return val, nil
+	case float64:
+		return int64(val), nil
+	case bool:
+		if val {
+			return 1, nil
+		}
+		return 0, nil
+	}
+	s := strings.TrimSpace(toString(value))
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	// decimal等類別的數值為[]uint8，例如: 10.00
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int64(f), nil
+}
+
+func toFloat64(value interface{}) (float64, error) {
+	switch val := value.(type) {
+	case float64:
+		return val, nil
+	case int64:
+		return float64(val), nil
+	}
+	return strconv.ParseFloat(strings.TrimSpace(toString(value)), 64)
+}
+
+func toTime(value interface{}) (time.Time, error) {
+	if t, ok := value.(time.Time); ok {
+		return t, nil
+	}
+	s := toString(value)
+	for _, layout := range timeLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("wrong time value: %s", s)
+}
+
+// 取得結構欄位的數值(新增、更新時使用)
+func fieldValue(v reflect.Value) (interface{}, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		if valuer, ok := v.Interface().(driver.Valuer); ok {
+			return valuer.Value()
+		}
+		return fieldValue(v.Elem())
+	}
+	if valuer, ok := v.Interface().(driver.Valuer); ok {
+		return valuer.Value()
+	}
+	return v.Interface(), nil
+}
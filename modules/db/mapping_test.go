@@ -0,0 +1,81 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	"github.com/magiconair/properties/assert"
+)
+
+type mappingBase struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+}
+
+type mappingUser struct {
+	mappingBase
+
+	Id       int64  `db:"id,omitempty"`
+	UserName string `db:"username"`
+	Avatar   sql.NullString
+	Salary   float64
+	Level    *int
+	Enabled  bool
+	Roles    []string `db:"-"`
+	remark   string
+}
+
+func TestScanStruct(t *testing.T) {
+	var user mappingUser
+	err := ScanStruct(map[string]interface{}{
+		"id":         int64(1),
+		"USERNAME":   "admin",
+		"avatar":     nil,
+		"salary":     []uint8("1200.50"),
+		"level":      int64(2),
+		"enabled":    int64(1),
+		"created_at": "2020-01-02 03:04:05",
+		"roles":      "a,b",
+	}, &user)
+
+	assert.Equal(t, err, nil)
+	assert.Equal(t, user.Id, int64(1))
+	assert.Equal(t, user.UserName, "admin")
+	assert.Equal(t, user.Avatar.Valid, false)
+	assert.Equal(t, user.Salary, 1200.5)
+	assert.Equal(t, *user.Level, 2)
+	assert.Equal(t, user.Enabled, true)
+	assert.Equal(t, user.CreatedAt, time.Date(2020, 1, 2, 3, 4, 5, 0, time.Local))
+	assert.Equal(t, len(user.Roles), 0)
+
+	var users []*mappingUser
+	err = ScanStructs([]map[string]interface{}{{"id": int64(1)}, {"id": int64(2), "avatar": "a.png"}}, &users)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(users), 2)
+	assert.Equal(t, users[1].Avatar, sql.NullString{String: "a.png", Valid: true})
+
+	assert.Equal(t, ScanStruct(map[string]interface{}{"level": "x"}, &user) != nil, true)
+	assert.Equal(t, ScanStruct(map[string]interface{}{}, user) != nil, true)
+}
+
+func TestStructToH(t *testing.T) {
+	level := 3
+	h, err := StructToH(mappingUser{UserName: "admin", Level: &level, remark: "x"})
+	assert.Equal(t, err, nil)
+	// the zero omitempty id and the readonly created_at are skipped
+	assert.Equal(t, h, dialect.H{
+		"username": "admin",
+		"avatar":   nil,
+		"salary":   float64(0),
+		"level":    3,
+		"enabled":  false,
+	})
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, snakeCase("UserId"), "user_id")
+	assert.Equal(t, snakeCase("HTTPPath"), "http_path")
+	assert.Equal(t, snakeCase("LastUsedIp"), "last_used_ip")
+	assert.Equal(t, snakeCase("Int2Value"), "int2_value")
+}
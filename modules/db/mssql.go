@@ -5,7 +5,9 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"regexp"
 	"strconv"
@@ -203,25 +205,25 @@ func (db *Mssql) parseSql(sql string) string {
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Mssql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
 	query = db.handleSqlBeforeExec(query)
-	return CommonQuery(db.DbList[con], query, args...)
+	return CommonQuery(db.readDB(context.Background(), con), query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
 func (db *Mssql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
 	query = db.handleSqlBeforeExec(query)
-	return CommonExec(db.DbList[con], query, args...)
+	return CommonExec(db.writeDB(con), query, args...)
 }
 
 // Query implements the method Connection.Query.
 func (db *Mssql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
 	query = db.handleSqlBeforeExec(query)
-	return CommonQuery(db.DbList["default"], query, args...)
+	return CommonQuery(db.readDB(context.Background(), "default"), query, args...)
 }
 
 // Exec implements the method Connection.Exec.
 func (db *Mssql) Exec(query string, args ...interface{}) (sql.Result, error) {
 	query = db.handleSqlBeforeExec(query)
-	return CommonExec(db.DbList["default"], query, args...)
+	return CommonExec(db.writeDB("default"), query, args...)
 }
 
 // InitDB implements the method Connection.InitDB.
@@ -229,36 +231,47 @@ func (db *Mssql) InitDB(cfglist map[string]config.Database) Connection {
 	db.Once.Do(func() {
 		for conn, cfg := range cfglist {
 
-			if cfg.Dsn == "" {
-
-				cfg.Dsn = fmt.Sprintf("user id=%s;password=%s;server=%s;port=%s;database=%s;"+cfg.ParamStr(),
-					cfg.User, cfg.Pwd, cfg.Host, cfg.Port, cfg.Name)
-			}
-
-			sqlDB, err := sql.Open("sqlserver", cfg.Dsn)
-
-			if sqlDB == nil {
-				panic("invalid connection")
-			}
-
+			sqlDB, err := openMssql(cfg)
 			if err != nil {
-				_ = sqlDB.Close()
 				panic(err.Error())
-			} else {
-				sqlDB.SetMaxIdleConns(cfg.MaxIdleCon)
-				sqlDB.SetMaxOpenConns(cfg.MaxOpenCon)
-
-				db.DbList[conn] = sqlDB
 			}
 
+			db.DbList[conn] = sqlDB
+			db.setTimeout(conn, cfg)
+
 			if err := sqlDB.Ping(); err != nil {
 				panic(err)
 			}
+
+			db.initReplicas(conn, cfg, openMssql)
 		}
 	})
 	return db
 }
 
+func openMssql(cfg config.Database) (*sql.DB, error) {
+	if cfg.Dsn == "" {
+
+		cfg.Dsn = fmt.Sprintf("user id=%s;password=%s;server=%s;port=%s;database=%s;"+cfg.ParamStr(),
+			cfg.User, cfg.Pwd, cfg.Host, cfg.Port, cfg.Name)
+	}
+
+	sqlDB, err := sql.Open("sqlserver", cfg.Dsn)
+
+	if sqlDB == nil {
+		return nil, errors.New("invalid connection")
+	}
+
+	if err != nil {
+		_ = sqlDB.Close()
+		return nil, err
+	}
+
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleCon)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenCon)
+	return sqlDB, nil
+}
+
 // BeginTxWithReadUncommitted starts a transaction with level LevelReadUncommitted.
 func (db *Mssql) BeginTxWithReadUncommitted() *sql.Tx {
 	return CommonBeginTxWithLevel(db.DbList["default"], sql.LevelReadUncommitted)
@@ -320,3 +333,57 @@ func (db *Mssql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.
 	query = db.handleSqlBeforeExec(query)
 	return CommonExecWithTx(tx, query, args...)
 }
+
+// QueryRows implements the method Connection.QueryRows.
+func (db *Mssql) QueryRows(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRows(ctx, db.readDB(ctx, "default"), db.handleSqlBeforeExec(query), args...)
+}
+
+// QueryRowsWithConnection implements the method Connection.QueryRowsWithConnection.
+func (db *Mssql) QueryRowsWithConnection(ctx context.Context, con string, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRows(ctx, db.readDB(ctx, con), db.handleSqlBeforeExec(query), args...)
+}
+
+// QueryRowsWithTx implements the method Connection.QueryRowsWithTx.
+func (db *Mssql) QueryRowsWithTx(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRowsWithTx(ctx, tx, db.handleSqlBeforeExec(query), args...)
+}
+
+// QueryContext implements the method Connection.QueryContext.
+func (db *Mssql) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return db.QueryWithConnectionContext(ctx, "default", query, args...)
+}
+
+// ExecContext implements the method Connection.ExecContext.
+func (db *Mssql) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecWithConnectionContext(ctx, "default", query, args...)
+}
+
+// QueryWithConnectionContext implements the method Connection.QueryWithConnectionContext.
+func (db *Mssql) QueryWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return db.readQuery(ctx, con, db.handleSqlBeforeExec(query), args...)
+}
+
+// ExecWithConnectionContext implements the method Connection.ExecWithConnectionContext.
+func (db *Mssql) ExecWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonExecContext(ctx, db.writeDB(con), db.handleSqlBeforeExec(query), args...)
+}
+
+// QueryWithTxContext implements the method Connection.QueryWithTxContext.
+func (db *Mssql) QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxContext(ctx, tx, db.handleSqlBeforeExec(query), args...)
+}
+
+// ExecWithTxContext implements the method Connection.ExecWithTxContext.
+func (db *Mssql) ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithTxContext(ctx, tx, db.handleSqlBeforeExec(query), args...)
+}
+
+// BeginTxWithContext implements the method Connection.BeginTxWithContext.
+func (db *Mssql) BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxContext(ctx, db.writeDB(conn), level)
+}
@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 )
@@ -49,58 +50,72 @@ func (db *Mysql) InitDB(cfgs map[string]config.Database) Connection {
 	db.Once.Do(func() {
 		for conn, cfg := range cfgs {
 
-			if cfg.Dsn == "" {
-				cfg.Dsn = cfg.User + ":" + cfg.Pwd + "@tcp(" + cfg.Host + ":" + cfg.Port + ")/" +
-					cfg.Name + cfg.ParamStr()
-			}
-
-			sqlDB, err := sql.Open("mysql", cfg.Dsn)
-
+			sqlDB, err := openMysql(cfg)
 			if err != nil {
-				if sqlDB != nil {
-					_ = sqlDB.Close()
-				}
 				panic(err)
-			} else {
-				// Largest set up the database connection reduce time wait
-				sqlDB.SetMaxIdleConns(cfg.MaxIdleCon)
-				sqlDB.SetMaxOpenConns(cfg.MaxOpenCon)
-
-				db.DbList[conn] = sqlDB
 			}
+
+			db.DbList[conn] = sqlDB
+			db.setTimeout(conn, cfg)
+
 			//啟動資料庫引擎
 			if err := sqlDB.Ping(); err != nil {
 				panic(err)
 			}
+
+			// 開啟讀取副本
+			db.initReplicas(conn, cfg, openMysql)
 		}
 	})
 	return db
 }
 
+// 依設定開啟資料庫連線(主資料庫及讀取副本)
+func openMysql(cfg config.Database) (*sql.DB, error) {
+	if cfg.Dsn == "" {
+		cfg.Dsn = cfg.User + ":" + cfg.Pwd + "@tcp(" + cfg.Host + ":" + cfg.Port + ")/" +
+			cfg.Name + cfg.ParamStr()
+	}
+
+	sqlDB, err := sql.Open("mysql", cfg.Dsn)
+
+	if err != nil {
+		if sqlDB != nil {
+			_ = sqlDB.Close()
+		}
+		return nil, err
+	}
+
+	// Largest set up the database connection reduce time wait
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleCon)
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenCon)
+	return sqlDB, nil
+}
+
 // QueryWithConnection implements the method Connection.QueryWithConnection.
-// 有給定參數連接(conn)名稱，透過參數con查詢db.DbList[con]資料並回傳
+// 有給定參數連接(conn)名稱，透過參數con查詢資料並回傳(有讀取副本時輪詢健康的副本)
 func (db *Mysql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
 	// CommonQuery查詢資料並回傳
-	return CommonQuery(db.DbList[con], query, args...)
+	return CommonQuery(db.readDB(context.Background(), con), query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
 // 有給定連接(conn)名稱
 func (db *Mysql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList[con], query, args...)
+	return CommonExec(db.writeDB(con), query, args...)
 }
 
 // Query implements the method Connection.Query.
-// 沒有給定連接(conn)名稱，透過參數查詢db.DbList["default"]資料並回傳
+// 沒有給定連接(conn)名稱，透過參數查詢default連接的資料並回傳(有讀取副本時輪詢健康的副本)
 func (db *Mysql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
 	// CommonQuery查詢資料並回傳
-	return CommonQuery(db.DbList["default"], query, args...)
+	return CommonQuery(db.readDB(context.Background(), "default"), query, args...)
 }
 
 // Exec implements the method Connection.Exec.
 // 沒有給定連接(conn)名稱
 func (db *Mysql) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList["default"], query, args...)
+	return CommonExec(db.writeDB("default"), query, args...)
 }
 
 // BeginTxWithReadUncommitted starts a transaction with level LevelReadUncommitted.
@@ -168,3 +183,57 @@ func (db *Mysql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.
 	// 與CommonExec一樣(差別在tx執行)
 	return CommonExecWithTx(tx, query, args...)
 }
+
+// QueryRows implements the method Connection.QueryRows.
+func (db *Mysql) QueryRows(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRows(ctx, db.readDB(ctx, "default"), query, args...)
+}
+
+// QueryRowsWithConnection implements the method Connection.QueryRowsWithConnection.
+func (db *Mysql) QueryRowsWithConnection(ctx context.Context, con string, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRows(ctx, db.readDB(ctx, con), query, args...)
+}
+
+// QueryRowsWithTx implements the method Connection.QueryRowsWithTx.
+func (db *Mysql) QueryRowsWithTx(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRowsWithTx(ctx, tx, query, args...)
+}
+
+// QueryContext implements the method Connection.QueryContext.
+func (db *Mysql) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return db.QueryWithConnectionContext(ctx, "default", query, args...)
+}
+
+// ExecContext implements the method Connection.ExecContext.
+func (db *Mysql) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecWithConnectionContext(ctx, "default", query, args...)
+}
+
+// QueryWithConnectionContext implements the method Connection.QueryWithConnectionContext.
+func (db *Mysql) QueryWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return db.readQuery(ctx, con, query, args...)
+}
+
+// ExecWithConnectionContext implements the method Connection.ExecWithConnectionContext.
+func (db *Mysql) ExecWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonExecContext(ctx, db.writeDB(con), query, args...)
+}
+
+// QueryWithTxContext implements the method Connection.QueryWithTxContext.
+func (db *Mysql) QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxContext(ctx, tx, query, args...)
+}
+
+// ExecWithTxContext implements the method Connection.ExecWithTxContext.
+func (db *Mysql) ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithTxContext(ctx, tx, query, args...)
+}
+
+// BeginTxWithContext implements the method Connection.BeginTxWithContext.
+func (db *Mysql) BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxContext(ctx, db.writeDB(conn), level)
+}
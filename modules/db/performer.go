@@ -22,6 +22,11 @@ func CommonQuery(db *sql.DB, query string, args ...interface{}) ([]map[string]in
 		panic(err)
 	}
 
+	return queryResults(rs)
+}
+
+// 讀取所有資料並關閉*sql.Rows
+func queryResults(rs *sql.Rows) ([]map[string]interface{}, error) {
 	//最後關閉 *sql.rows
 	defer func() {
 		if rs != nil {
@@ -29,42 +34,19 @@ func CommonQuery(db *sql.DB, query string, args ...interface{}) ([]map[string]in
 		}
 	}()
 
-	//取得欄位名稱
-	col, colErr := rs.Columns()
-
-	if colErr != nil {
-		return nil, colErr
-	}
-
-	// 取得欄位類別
-	typeVal, err := rs.ColumnTypes()
+	//取得欄位名稱、欄位類別
+	col, typeNames, err := columnTypes(rs)
 	if err != nil {
 		return nil, err
 	}
 
-	// TODO: regular expressions for sqlite, use the dialect module
-	// tell the drive to reduce the performance loss
 	results := make([]map[string]interface{}, 0)
 
-	r, _ := regexp.Compile(`\\((.*)\\)`)
 	for rs.Next() {
-		var colVar = make([]interface{}, len(col))
-		//typeName欄位類別名稱
-		for i := 0; i < len(col); i++ {
-			typeName := strings.ToUpper(r.ReplaceAllString(typeVal[i].DatabaseTypeName(), ""))
-			//converter.go中
-			//SetColVarType 設定欄位數值類型
-			SetColVarType(&colVar, i, typeName)
-		}
-		result := make(map[string]interface{})
-		if scanErr := rs.Scan(colVar...); scanErr != nil {
+		result, scanErr := scanRow(rs, col, typeNames)
+		if scanErr != nil {
 			return nil, scanErr
 		}
-		for j := 0; j < len(col); j++ {
-			typeName := strings.ToUpper(r.ReplaceAllString(typeVal[j].DatabaseTypeName(), ""))
-			// converter.go中
-			SetResultValue(&result, col[j], colVar[j], typeName)
-		}
 		results = append(results, result)
 	}
 	if err := rs.Err(); err != nil {
@@ -73,6 +55,46 @@ func CommonQuery(db *sql.DB, query string, args ...interface{}) ([]map[string]in
 	return results, nil
 }
 
+// TODO: regular expressions for sqlite, use the dialect module
+// tell the drive to reduce the performance loss
+var columnTypeReg, _ = regexp.Compile(`\\((.*)\\)`)
+
+// 取得欄位名稱及欄位類別名稱
+func columnTypes(rs *sql.Rows) ([]string, []string, error) {
+	col, err := rs.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typeVal, err := rs.ColumnTypes()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typeNames := make([]string, len(typeVal))
+	for i := 0; i < len(typeVal); i++ {
+		typeNames[i] = strings.ToUpper(columnTypeReg.ReplaceAllString(typeVal[i].DatabaseTypeName(), ""))
+	}
+	return col, typeNames, nil
+}
+
+// 讀取目前的資料列並依欄位類別轉換數值(converter.go)
+func scanRow(rs *sql.Rows, col, typeNames []string) (map[string]interface{}, error) {
+	var colVar = make([]interface{}, len(col))
+	for i := 0; i < len(col); i++ {
+		//SetColVarType 設定欄位數值類型
+		SetColVarType(&colVar, i, typeNames[i])
+	}
+	result := make(map[string]interface{})
+	if err := rs.Scan(colVar...); err != nil {
+		return nil, err
+	}
+	for j := 0; j < len(col); j++ {
+		SetResultValue(&result, col[j], colVar[j], typeNames[j])
+	}
+	return result, nil
+}
+
 // CommonExec is a common method of exec.
 // 執行sql命令
 func CommonExec(db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
@@ -94,48 +116,56 @@ func CommonQueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]map[str
 		panic(err)
 	}
 
-	defer func() {
-		if rs != nil {
-			_ = rs.Close()
-		}
-	}()
-
-	col, colErr := rs.Columns()
+	return queryResults(rs)
+}
 
-	if colErr != nil {
-		return nil, colErr
+// CommonQueryContext is a common method of query with the context. Unlike CommonQuery, the
+// error of the query is returned instead of panic, such as the context is canceled.
+// 與CommonQuery一樣(差別在查詢會隨ctx取消或逾時而中止，錯誤會回傳而不是panic)
+func CommonQueryContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rs, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	return queryResults(rs)
+}
 
-	typeVal, err := rs.ColumnTypes()
+// CommonExecContext is a common method of exec with the context.
+func CommonExecContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(ctx, query, args...)
+}
+
+// CommonQueryWithTxContext is a common method of query with the context within the transaction.
+func CommonQueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rs, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
+	return queryResults(rs)
+}
 
-	// TODO: regular expressions for sqlite, use the dialect module
-	// tell the drive to reduce the performance loss
-	results := make([]map[string]interface{}, 0)
+// CommonExecWithTxContext is a common method of exec with the context within the transaction.
+func CommonExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return tx.ExecContext(ctx, query, args...)
+}
 
-	r, _ := regexp.Compile(`\\((.*)\\)`)
-	for rs.Next() {
-		var colVar = make([]interface{}, len(col))
-		for i := 0; i < len(col); i++ {
-			typeName := strings.ToUpper(r.ReplaceAllString(typeVal[i].DatabaseTypeName(), ""))
-			SetColVarType(&colVar, i, typeName)
-		}
-		result := make(map[string]interface{})
-		if scanErr := rs.Scan(colVar...); scanErr != nil {
-			return nil, scanErr
-		}
-		for j := 0; j < len(col); j++ {
-			typeName := strings.ToUpper(r.ReplaceAllString(typeVal[j].DatabaseTypeName(), ""))
-			SetResultValue(&result, col[j], colVar[j], typeName)
-		}
-		results = append(results, result)
+// CommonQueryRows is a common method of the streaming query.
+// 查詢資料並回傳Rows，逐筆讀取資料而不是一次載入所有資料，使用後必須關閉
+func CommonQueryRows(ctx context.Context, db *sql.DB, query string, args ...interface{}) (*Rows, error) {
+	rs, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
-	if err := rs.Err(); err != nil {
+	return newRows(ctx, rs)
+}
+
+// CommonQueryRowsWithTx is a common method of the streaming query within the transaction.
+func CommonQueryRowsWithTx(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*Rows, error) {
+	rs, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
 		return nil, err
 	}
-	return results, nil
+	return newRows(ctx, rs)
 }
 
 // CommonExecWithTx is a common method of exec.
@@ -156,3 +186,10 @@ func CommonBeginTxWithLevel(db *sql.DB, level sql.IsolationLevel) *sql.Tx {
 	}
 	return tx
 }
+
+// CommonBeginTxContext starts a transaction with the context and given transaction isolation
+// level, the transaction is rolled back when the context is done.
+// 開始交易，ctx取消時交易會被回滾，錯誤會回傳而不是panic
+func CommonBeginTxContext(ctx context.Context, db *sql.DB, level sql.IsolationLevel) (*sql.Tx, error) {
+	return db.BeginTx(ctx, &sql.TxOptions{Isolation: level})
+}
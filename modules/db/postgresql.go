@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/modules/config"
@@ -38,22 +39,22 @@ func (db *Postgresql) GetDelimiter() string {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Postgresql) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList[con], filterQuery(query), args...)
+	return CommonQuery(db.readDB(context.Background(), con), filterQuery(query), args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
 func (db *Postgresql) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList[con], filterQuery(query), args...)
+	return CommonExec(db.writeDB(con), filterQuery(query), args...)
 }
 
 // Query implements the method Connection.Query.
 func (db *Postgresql) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList["default"], filterQuery(query), args...)
+	return CommonQuery(db.readDB(context.Background(), "default"), filterQuery(query), args...)
 }
 
 // Exec implements the method Connection.Exec.
 func (db *Postgresql) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList["default"], filterQuery(query), args...)
+	return CommonExec(db.writeDB("default"), filterQuery(query), args...)
 }
 
 func filterQuery(query string) string {
@@ -71,26 +72,33 @@ func (db *Postgresql) InitDB(cfgList map[string]config.Database) Connection {
 	db.Once.Do(func() {
 		for conn, cfg := range cfgList {
 
-			if cfg.Dsn == "" {
-				cfg.Dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s"+cfg.ParamStr(),
-					cfg.Host, cfg.Port, cfg.User, cfg.Pwd, cfg.Name)
-			}
-
-			sqlDB, err := sql.Open("postgres", cfg.Dsn)
+			sqlDB, err := openPostgresql(cfg)
 			if err != nil {
 				panic(err)
 			}
 
 			db.DbList[conn] = sqlDB
+			db.setTimeout(conn, cfg)
 
 			if err := sqlDB.Ping(); err != nil {
 				panic(err)
 			}
+
+			db.initReplicas(conn, cfg, openPostgresql)
 		}
 	})
 	return db
 }
 
+func openPostgresql(cfg config.Database) (*sql.DB, error) {
+	if cfg.Dsn == "" {
+		cfg.Dsn = fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s"+cfg.ParamStr(),
+			cfg.Host, cfg.Port, cfg.User, cfg.Pwd, cfg.Name)
+	}
+
+	return sql.Open("postgres", cfg.Dsn)
+}
+
 // BeginTxWithReadUncommitted starts a transaction with level LevelReadUncommitted.
 func (db *Postgresql) BeginTxWithReadUncommitted() *sql.Tx {
 	return CommonBeginTxWithLevel(db.DbList["default"], sql.LevelReadUncommitted)
@@ -150,3 +158,57 @@ func (db *Postgresql) QueryWithTx(tx *sql.Tx, query string, args ...interface{})
 func (db *Postgresql) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
 	return CommonExecWithTx(tx, filterQuery(query), args...)
 }
+
+// QueryRows implements the method Connection.QueryRows.
+func (db *Postgresql) QueryRows(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRows(ctx, db.readDB(ctx, "default"), filterQuery(query), args...)
+}
+
+// QueryRowsWithConnection implements the method Connection.QueryRowsWithConnection.
+func (db *Postgresql) QueryRowsWithConnection(ctx context.Context, con string, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRows(ctx, db.readDB(ctx, con), filterQuery(query), args...)
+}
+
+// QueryRowsWithTx implements the method Connection.QueryRowsWithTx.
+func (db *Postgresql) QueryRowsWithTx(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRowsWithTx(ctx, tx, filterQuery(query), args...)
+}
+
+// QueryContext implements the method Connection.QueryContext.
+func (db *Postgresql) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return db.QueryWithConnectionContext(ctx, "default", query, args...)
+}
+
+// ExecContext implements the method Connection.ExecContext.
+func (db *Postgresql) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecWithConnectionContext(ctx, "default", query, args...)
+}
+
+// QueryWithConnectionContext implements the method Connection.QueryWithConnectionContext.
+func (db *Postgresql) QueryWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return db.readQuery(ctx, con, filterQuery(query), args...)
+}
+
+// ExecWithConnectionContext implements the method Connection.ExecWithConnectionContext.
+func (db *Postgresql) ExecWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonExecContext(ctx, db.writeDB(con), filterQuery(query), args...)
+}
+
+// QueryWithTxContext implements the method Connection.QueryWithTxContext.
+func (db *Postgresql) QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxContext(ctx, tx, filterQuery(query), args...)
+}
+
+// ExecWithTxContext implements the method Connection.ExecWithTxContext.
+func (db *Postgresql) ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithTxContext(ctx, tx, filterQuery(query), args...)
+}
+
+// BeginTxWithContext implements the method Connection.BeginTxWithContext.
+func (db *Postgresql) BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxContext(ctx, db.writeDB(conn), level)
+}
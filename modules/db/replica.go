@@ -0,0 +1,235 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+)
+
+// defaultReplicaCheckInterval is the default interval of the health check of the replicas.
+const defaultReplicaCheckInterval = 10 * time.Second
+
+type primaryContextKey struct{}
+
+// WithPrimary return a context which let the reads executed with it be routed to the primary,
+// it is used to read your writes after a mutation.
+// 回傳指定讀取主資料庫的context，寫入後需要立即讀取寫入的資料時使用
+func WithPrimary(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+func isPrimaryContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	v, _ := ctx.Value(primaryContextKey{}).(bool)
+	return v
+}
+
+type replica struct {
+	db      *sql.DB
+	healthy int32
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
+
+func (r *replica) setHealthy(healthy bool) {
+	var v int32
+	if healthy {
+		v = 1
+	}
+	atomic.StoreInt32(&r.healthy, v)
+}
+
+// replicaSet is the read replicas of a connection.
+// 連接的讀取副本，以輪詢的方式選擇健康的副本，並定期檢查副本的狀態
+type replicaSet struct {
+	replicas       []*replica
+	next           uint32
+	readAfterWrite time.Duration
+	lastWrite      int64
+	stop           chan struct{}
+	stopOnce       sync.Once
+}
+
+func newReplicaSet(cfg config.Database) *replicaSet {
+	return &replicaSet{
+		replicas:       make([]*replica, 0),
+		readAfterWrite: time.Duration(cfg.ReadAfterWrite) * time.Second,
+		stop:           make(chan struct{}),
+	}
+}
+
+func (s *replicaSet) add(db *sql.DB, healthy bool) {
+	r := &replica{db: db}
+	r.setHealthy(healthy)
+	s.replicas = append(s.replicas, r)
+}
+
+// pick return the next healthy replica, it return nil if there is no healthy replica or the
+// primary should be read after a write.
+// 輪詢選擇下一個健康的副本，沒有健康的副本或寫入後的期間內回傳nil(讀取主資料庫)
+func (s *replicaSet) pick() *sql.DB {
+	if s.readAfterWrite > 0 &&
+		time.Since(time.Unix(0, atomic.LoadInt64(&s.lastWrite))) < s.readAfterWrite {
+		return nil
+	}
+	count := len(s.replicas)
+	for i := 0; i < count; i++ {
+		r := s.replicas[int(atomic.AddUint32(&s.next, 1)-1)%count]
+		if r.isHealthy() {
+			return r.db
+		}
+	}
+	return nil
+}
+
+// 記錄寫入的時間
+func (s *replicaSet) markWrite() {
+	if s.readAfterWrite > 0 {
+		atomic.StoreInt64(&s.lastWrite, time.Now().UnixNano())
+	}
+}
+
+// eject mark the replica as unhealthy until the next successful health check.
+// 剔除副本，直到下次健康檢查成功
+func (s *replicaSet) eject(db *sql.DB) {
+	for _, r := range s.replicas {
+		if r.db == db {
+			r.setHealthy(false)
+			logger.Warn("database replica is ejected")
+			return
+		}
+	}
+}
+
+// check ping all the replicas and update their health.
+func (s *replicaSet) check(timeout time.Duration) {
+	for _, r := range s.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		err := r.db.PingContext(ctx)
+		cancel()
+		if err != nil && r.isHealthy() {
+			logger.Warn("database replica is ejected: ", err)
+		}
+		r.setHealthy(err == nil)
+	}
+}
+
+// 定期檢查副本的狀態，直到呼叫close
+func (s *replicaSet) startCheck(interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultReplicaCheckInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.check(interval)
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+func (s *replicaSet) close() []error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	errs := make([]error, 0)
+	for _, r := range s.replicas {
+		errs = append(errs, r.db.Close())
+	}
+	return errs
+}
+
+// isConnError check the error is caused by the broken connection or not.
+func isConnError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if err == driver.ErrBadConn {
+		return true
+	}
+	_, ok := err.(net.Error)
+	return ok
+}
+
+// initReplicas open the replicas of the connection and start the health check, the replica
+// which fails to connect is kept unhealthy until it pass the health check.
+// 開啟連接的讀取副本並開始健康檢查，連線失敗的副本在通過健康檢查前不會被使用
+func (db *Base) initReplicas(conn string, cfg config.Database, open func(cfg config.Database) (*sql.DB, error)) {
+	if len(cfg.Replicas) == 0 {
+		return
+	}
+
+	set := newReplicaSet(cfg)
+	for _, replicaCfg := range cfg.ReplicaConfigs() {
+		sqlDB, err := open(replicaCfg)
+		if err != nil {
+			logger.Error("open database replica error: ", err)
+			continue
+		}
+		err = sqlDB.Ping()
+		if err != nil {
+			logger.Warn("database replica is unhealthy: ", err)
+		}
+		set.add(sqlDB, err == nil)
+	}
+
+	if db.replicas == nil {
+		db.replicas = make(map[string]*replicaSet)
+	}
+	db.replicas[conn] = set
+	set.startCheck(time.Duration(cfg.ReplicaCheckInterval) * time.Second)
+}
+
+// readDB return the database for reading, which is a healthy replica if any, or the primary.
+// 取得讀取用的資料庫(健康的副本，沒有則為主資料庫)，ctx指定讀取主資料庫時回傳主資料庫
+func (db *Base) readDB(ctx context.Context, conn string) *sql.DB {
+	if set, ok := db.replicas[conn]; ok && !isPrimaryContext(ctx) {
+		if replicaDB := set.pick(); replicaDB != nil {
+			return replicaDB
+		}
+	}
+	return db.DbList[conn]
+}
+
+// writeDB return the primary for writing and record the write.
+// 取得寫入用的資料庫(主資料庫)並記錄寫入的時間
+func (db *Base) writeDB(conn string) *sql.DB {
+	if set, ok := db.replicas[conn]; ok {
+		set.markWrite()
+	}
+	return db.DbList[conn]
+}
+
+// readQuery query in the database for reading, the replica is ejected and the query is retried
+// in the primary if the connection of the replica is broken.
+// 在讀取用的資料庫查詢，副本連線錯誤時剔除該副本並改在主資料庫查詢
+func (db *Base) readQuery(ctx context.Context, conn, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	target := db.readDB(ctx, conn)
+	res, err := CommonQueryContext(ctx, target, query, args...)
+	if err != nil && target != db.DbList[conn] && isConnError(err) {
+		db.replicas[conn].eject(target)
+		return CommonQueryContext(ctx, db.DbList[conn], query, args...)
+	}
+	return res, err
+}
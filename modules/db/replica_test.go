@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestReplicaRouting(t *testing.T) {
+	open := func(cfg config.Database) (*sql.DB, error) {
+		return sql.Open("sqlite3", cfg.File)
+	}
+	primary, _ := open(config.Database{File: ":memory:"})
+
+	base := Base{DbList: map[string]*sql.DB{"default": primary}}
+	base.initReplicas("default", config.Database{
+		File:           ":memory:",
+		Replicas:       []config.Database{{}, {}},
+		ReadAfterWrite: 1,
+	}, open)
+	defer base.Close()
+
+	set := base.replicas["default"]
+	assert.Equal(t, len(set.replicas), 2)
+
+	// the reads are routed to the replicas in turn
+	first := base.readDB(context.Background(), "default")
+	second := base.readDB(context.Background(), "default")
+	assert.Equal(t, first == primary, false)
+	assert.Equal(t, second == primary, false)
+	assert.Equal(t, first == second, false)
+	assert.Equal(t, base.readDB(context.Background(), "default"), first)
+
+	// read your writes
+	assert.Equal(t, base.readDB(WithPrimary(context.Background()), "default"), primary)
+	assert.Equal(t, base.readDB(Table("goadmin_users").UsePrimary().getContext(), "default"), primary)
+
+	set.eject(first)
+	assert.Equal(t, base.readDB(context.Background(), "default"), second)
+	assert.Equal(t, base.readDB(context.Background(), "default"), second)
+
+	set.eject(second)
+	assert.Equal(t, base.readDB(context.Background(), "default"), primary)
+
+	// the ejected replicas are back after the health check
+	set.check(time.Second)
+	assert.Equal(t, base.readDB(context.Background(), "default") == primary, false)
+
+	// the reads are routed to the primary after a write
+	assert.Equal(t, base.writeDB("default"), primary)
+	assert.Equal(t, base.readDB(context.Background(), "default"), primary)
+	set.lastWrite = time.Now().Add(-2 * time.Second).UnixNano()
+	assert.Equal(t, base.readDB(context.Background(), "default") == primary, false)
+
+	_ = set.replicas[0].db.Close()
+	set.check(time.Second)
+	assert.Equal(t, set.replicas[0].isHealthy(), false)
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrStopIteration can be returned by the callback of SQL.Each and SQL.Chunk to stop the
+// iteration without error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// Rows is the iterator of the streaming query result, the values are converted in the same
+// way as Query. The Rows must be closed after used.
+// 逐筆讀取查詢結果，數值的轉換與Query相同(converter.go)，使用後必須呼叫Close
+//
+//	rows, err := conn.QueryRows(ctx, "select * from goadmin_users")
+//	if err != nil {
+//		return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//		row := rows.Row()
+//	}
+//	return rows.Err()
+type Rows struct {
+	ctx       context.Context
+	rs        *sql.Rows
+	columns   []string
+	typeNames []string
+	row       map[string]interface{}
+	err       error
+}
+
+func newRows(ctx context.Context, rs *sql.Rows) (*Rows, error) {
+	col, typeNames, err := columnTypes(rs)
+	if err != nil {
+		_ = rs.Close()
+		return nil, err
+	}
+	return &Rows{
+		ctx:       ctx,
+		rs:        rs,
+		columns:   col,
+		typeNames: typeNames,
+	}, nil
+}
+
+// Next prepare the next row, it return false if there is no more row, an error occurred
+// or the context is done.
+// 讀取下一筆資料，沒有資料、發生錯誤或context已取消時回傳false
+func (r *Rows) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	if r.ctx != nil {
+		if err := r.ctx.Err(); err != nil {
+			r.err = err
+			return false
+		}
+	}
+	if !r.rs.Next() {
+		return false
+	}
+	r.row, r.err = scanRow(r.rs, r.columns, r.typeNames)
+	return r.err == nil
+}
+
+// Row return the current row.
+func (r *Rows) Row() map[string]interface{} {
+	return r.row
+}
+
+// Columns return the column names.
+func (r *Rows) Columns() []string {
+	return r.columns
+}
+
+// Err return the error occurred during the iteration.
+func (r *Rows) Err() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.rs.Err()
+}
+
+// Close close the rows.
+func (r *Rows) Close() error {
+	return r.rs.Close()
+}
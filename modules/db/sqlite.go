@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 )
@@ -35,44 +36,51 @@ func (db *Sqlite) GetDelimiter() string {
 
 // QueryWithConnection implements the method Connection.QueryWithConnection.
 func (db *Sqlite) QueryWithConnection(con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList[con], query, args...)
+	return CommonQuery(db.readDB(context.Background(), con), query, args...)
 }
 
 // ExecWithConnection implements the method Connection.ExecWithConnection.
 func (db *Sqlite) ExecWithConnection(con string, query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList[con], query, args...)
+	return CommonExec(db.writeDB(con), query, args...)
 }
 
 // Query implements the method Connection.Query.
 func (db *Sqlite) Query(query string, args ...interface{}) ([]map[string]interface{}, error) {
-	return CommonQuery(db.DbList["default"], query, args...)
+	return CommonQuery(db.readDB(context.Background(), "default"), query, args...)
 }
 
 // Exec implements the method Connection.Exec.
 func (db *Sqlite) Exec(query string, args ...interface{}) (sql.Result, error) {
-	return CommonExec(db.DbList["default"], query, args...)
+	return CommonExec(db.writeDB("default"), query, args...)
 }
 
 // InitDB implements the method Connection.InitDB.
 func (db *Sqlite) InitDB(cfgList map[string]config.Database) Connection {
 	db.Once.Do(func() {
 		for conn, cfg := range cfgList {
-			sqlDB, err := sql.Open("sqlite3", cfg.File+cfg.ParamStr())
+			sqlDB, err := openSqlite(cfg)
 
 			if err != nil {
 				panic(err)
 			} else {
 				db.DbList[conn] = sqlDB
+				db.setTimeout(conn, cfg)
 			}
 
 			if err := sqlDB.Ping(); err != nil {
 				panic(err)
 			}
+
+			db.initReplicas(conn, cfg, openSqlite)
 		}
 	})
 	return db
 }
 
+func openSqlite(cfg config.Database) (*sql.DB, error) {
+	return sql.Open("sqlite3", cfg.File+cfg.ParamStr())
+}
+
 // BeginTxWithReadUncommitted starts a transaction with level LevelReadUncommitted.
 func (db *Sqlite) BeginTxWithReadUncommitted() *sql.Tx {
 	return CommonBeginTxWithLevel(db.DbList["default"], sql.LevelReadUncommitted)
@@ -132,3 +140,57 @@ func (db *Sqlite) QueryWithTx(tx *sql.Tx, query string, args ...interface{}) ([]
 func (db *Sqlite) ExecWithTx(tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
 	return CommonExecWithTx(tx, query, args...)
 }
+
+// QueryRows implements the method Connection.QueryRows.
+func (db *Sqlite) QueryRows(ctx context.Context, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRows(ctx, db.readDB(ctx, "default"), query, args...)
+}
+
+// QueryRowsWithConnection implements the method Connection.QueryRowsWithConnection.
+func (db *Sqlite) QueryRowsWithConnection(ctx context.Context, con string, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRows(ctx, db.readDB(ctx, con), query, args...)
+}
+
+// QueryRowsWithTx implements the method Connection.QueryRowsWithTx.
+func (db *Sqlite) QueryRowsWithTx(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (*Rows, error) {
+	return CommonQueryRowsWithTx(ctx, tx, query, args...)
+}
+
+// QueryContext implements the method Connection.QueryContext.
+func (db *Sqlite) QueryContext(ctx context.Context, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return db.QueryWithConnectionContext(ctx, "default", query, args...)
+}
+
+// ExecContext implements the method Connection.ExecContext.
+func (db *Sqlite) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecWithConnectionContext(ctx, "default", query, args...)
+}
+
+// QueryWithConnectionContext implements the method Connection.QueryWithConnectionContext.
+func (db *Sqlite) QueryWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return db.readQuery(ctx, con, query, args...)
+}
+
+// ExecWithConnectionContext implements the method Connection.ExecWithConnectionContext.
+func (db *Sqlite) ExecWithConnectionContext(ctx context.Context, con string, query string, args ...interface{}) (sql.Result, error) {
+	ctx, cancel := db.withTimeout(ctx, con)
+	defer cancel()
+	return CommonExecContext(ctx, db.writeDB(con), query, args...)
+}
+
+// QueryWithTxContext implements the method Connection.QueryWithTxContext.
+func (db *Sqlite) QueryWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	return CommonQueryWithTxContext(ctx, tx, query, args...)
+}
+
+// ExecWithTxContext implements the method Connection.ExecWithTxContext.
+func (db *Sqlite) ExecWithTxContext(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (sql.Result, error) {
+	return CommonExecWithTxContext(ctx, tx, query, args...)
+}
+
+// BeginTxWithContext implements the method Connection.BeginTxWithContext.
+func (db *Sqlite) BeginTxWithContext(ctx context.Context, conn string, level sql.IsolationLevel) (*sql.Tx, error) {
+	return CommonBeginTxContext(ctx, db.writeDB(conn), level)
+}
@@ -5,6 +5,7 @@
 package db
 
 import (
+	"context"
 	dbsql "database/sql"
 	"errors"
 	"fmt"
@@ -25,6 +26,8 @@ type SQL struct {
 	dialect dialect.Dialect //sql CRUD等方法(不同資料庫引擎的方法)
 	conn    string
 	tx      *dbsql.Tx
+	ctx     context.Context
+	primary bool
 }
 
 // SQLPool is a object pool of SQL.
@@ -39,6 +42,8 @@ var SQLPool = sync.Pool{
 				Wheres:     make([]dialect.Where, 0),
 				Leftjoins:  make([]dialect.Join, 0),
 				UpdateRaws: make([]dialect.RawUpdate, 0),
+				Havings:    make([]dialect.Where, 0),
+				Unions:     make([]dialect.Union, 0),
 				WhereRaws:  "",
 				Order:      "",
 				Group:      "",
@@ -114,6 +119,51 @@ func (sql *SQL) WithTx(tx *dbsql.Tx) *SQL {
 	return sql
 }
 
+// WithContext set the context of SQL, the terminal methods and the transactions are canceled
+// when the context is done. The default timeout of the connection is applied as well, except
+// for the streaming query(Rows, Each and Chunk) which may last long.
+// 設置context，context取消或逾時時中止查詢、執行及交易(逐筆讀取不套用連接的預設逾時時間)
+func (sql *SQL) WithContext(ctx context.Context) *SQL {
+	sql.ctx = ctx
+	return sql
+}
+
+// UsePrimary let the reads of SQL be routed to the primary instead of the read replicas, it is
+// used to read your writes after a mutation.
+// 指定在主資料庫讀取(不使用讀取副本)，寫入後需要立即讀取寫入的資料時使用
+func (sql *SQL) UsePrimary() *SQL {
+	sql.primary = true
+	return sql
+}
+
+// 取得SQL的context，沒有設置時使用context.Background()
+func (sql *SQL) getContext() context.Context {
+	ctx := sql.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if sql.primary {
+		return WithPrimary(ctx)
+	}
+	return ctx
+}
+
+// 有tx在tx中查詢，反之在給定的連接(conn)查詢
+func (sql *SQL) query(query string, args ...interface{}) ([]map[string]interface{}, error) {
+	if sql.tx != nil {
+		return sql.diver.QueryWithTxContext(sql.getContext(), sql.tx, query, args...)
+	}
+	return sql.diver.QueryWithConnectionContext(sql.getContext(), sql.conn, query, args...)
+}
+
+// 有tx在tx中執行，反之在給定的連接(conn)執行
+func (sql *SQL) exec(query string, args ...interface{}) (dbsql.Result, error) {
+	if sql.tx != nil {
+		return sql.diver.ExecWithTxContext(sql.getContext(), sql.tx, query, args...)
+	}
+	return sql.diver.ExecWithConnectionContext(sql.getContext(), sql.conn, query, args...)
+}
+
 // TableName set table of SQL.
 // 將SQL(struct)資訊清除後將參數設置至SQL.TableName回傳
 func (sql *SQL) Table(table string) *SQL {
@@ -184,6 +234,43 @@ func (sql *SQL) GroupByRaw(group string) *SQL {
 	return sql
 }
 
+// Having add the having operation and argument value.
+// 分組後的篩選條件，欄位可以是函式(ex: count(id))
+func (sql *SQL) Having(field string, operation string, arg interface{}) *SQL {
+	sql.Havings = append(sql.Havings, dialect.Where{
+		Field:     field,
+		Operation: operation,
+		Qmark:     "?",
+	})
+	sql.HavingArgs = append(sql.HavingArgs, arg)
+	return sql
+}
+
+// OrHaving add the having operation joined by "or".
+func (sql *SQL) OrHaving(field string, operation string, arg interface{}) *SQL {
+	sql.Havings = append(sql.Havings, dialect.Where{
+		Field:     field,
+		Operation: operation,
+		Qmark:     "?",
+		Conj:      dialect.ConjOr,
+	})
+	sql.HavingArgs = append(sql.HavingArgs, arg)
+	return sql
+}
+
+// HavingRaw add the raw having condition and arguments.
+func (sql *SQL) HavingRaw(raw string, args ...interface{}) *SQL {
+	sql.Havings = append(sql.Havings, dialect.Where{Raw: raw})
+	sql.HavingArgs = append(sql.HavingArgs, args...)
+	return sql
+}
+
+// Distinct set select distinct.
+func (sql *SQL) Distinct() *SQL {
+	sql.SQLComponent.Distinct = true
+	return sql
+}
+
 // Skip set offset value.
 func (sql *SQL) Skip(offset int) *SQL {
 	sql.Offset = strconv.Itoa(offset)
@@ -238,6 +325,95 @@ func (sql *SQL) WhereNotIn(field string, arg []interface{}) *SQL {
 	return sql
 }
 
+// OrWhere add the where operation and argument value joined by "or". The "and" has higher
+// precedence than "or", use WhereGroup to wrap the conditions in parentheses.
+// 以or連接的條件，and的優先順序高於or，需要時以WhereGroup將條件包在括號中
+func (sql *SQL) OrWhere(field string, operation string, arg interface{}) *SQL {
+	sql.Wheres = append(sql.Wheres, dialect.Where{
+		Field:     field,
+		Operation: operation,
+		Qmark:     "?",
+		Conj:      dialect.ConjOr,
+	})
+	sql.Args = append(sql.Args, arg)
+	return sql
+}
+
+// WhereGroup add the conditions set by the function which are wrapped in parentheses.
+// ex: Where("a", "=", 1).WhereGroup(func(group *SQL) { group.Where("b", "=", 2).OrWhere("c", "=", 3) })
+// => where a = ? and (b = ? or c = ?)
+func (sql *SQL) WhereGroup(fn func(group *SQL)) *SQL {
+	return sql.whereGroup(dialect.ConjAnd, fn)
+}
+
+// OrWhereGroup add the conditions set by the function which are wrapped in parentheses
+// and joined by "or".
+func (sql *SQL) OrWhereGroup(fn func(group *SQL)) *SQL {
+	return sql.whereGroup(dialect.ConjOr, fn)
+}
+
+func (sql *SQL) whereGroup(conj string, fn func(group *SQL)) *SQL {
+	group := &SQL{
+		SQLComponent: dialect.SQLComponent{
+			Wheres: make([]dialect.Where, 0),
+			Args:   make([]interface{}, 0),
+		},
+		diver:   sql.diver,
+		dialect: sql.dialect,
+	}
+	fn(group)
+	wheres := group.Wheres
+	if group.WhereRaws != "" {
+		wheres = append(wheres, dialect.Where{Raw: group.WhereRaws})
+	}
+	if len(wheres) == 0 {
+		return sql
+	}
+	sql.Wheres = append(sql.Wheres, dialect.Where{Conj: conj, Group: wheres})
+	sql.Args = append(sql.Args, group.Args...)
+	return sql
+}
+
+// WhereInSub add the where operation of "in" with the subquery.
+// ex: WhereInSub("id", db.Table("goadmin_role_users").Select("user_id").Where("role_id", "=", 1))
+func (sql *SQL) WhereInSub(field string, sub *SQL) *SQL {
+	return sql.WhereSub(field, "in", sub)
+}
+
+// WhereNotInSub add the where operation of "not in" with the subquery.
+func (sql *SQL) WhereNotInSub(field string, sub *SQL) *SQL {
+	return sql.WhereSub(field, "not in", sub)
+}
+
+// WhereSub add the where operation which compares the field with the result of the subquery.
+func (sql *SQL) WhereSub(field string, operation string, sub *SQL) *SQL {
+	statement, args := sql.subQuery(sub)
+	sql.Wheres = append(sql.Wheres, dialect.Where{
+		Field:     field,
+		Operation: operation,
+		Qmark:     "(" + statement + ")",
+	})
+	sql.Args = append(sql.Args, args...)
+	return sql
+}
+
+// WhereExists add the where exists condition of the subquery.
+func (sql *SQL) WhereExists(sub *SQL) *SQL {
+	return sql.whereExists("exists", sub)
+}
+
+// WhereNotExists add the where not exists condition of the subquery.
+func (sql *SQL) WhereNotExists(sub *SQL) *SQL {
+	return sql.whereExists("not exists", sub)
+}
+
+func (sql *SQL) whereExists(operation string, sub *SQL) *SQL {
+	statement, args := sql.subQuery(sub)
+	sql.Wheres = append(sql.Wheres, dialect.Where{Raw: operation + " (" + statement + ")"})
+	sql.Args = append(sql.Args, args...)
+	return sql
+}
+
 // Find query the sql result with given id assuming that primary key name is "id".
 // 該資料表主鍵為id，藉由id取的符合資料
 func (sql *SQL) Find(arg interface{}) (map[string]interface{}, error) {
@@ -252,6 +428,17 @@ func (sql *SQL) Count() (int64, error) {
 		driver = sql.diver.Name()
 	)
 
+	// distinct、group by、having、union的筆數為結果的筆數，以select count(*) from (查詢) t計算
+	if sql.SQLComponent.Distinct || sql.Group != "" || len(sql.Havings) > 0 || len(sql.Unions) > 0 {
+		sub := &SQL{SQLComponent: sql.SQLComponent, dialect: sql.dialect}
+		// 沒有筆數限制時排序不影響筆數，且mssql的子查詢不允許單獨使用order by
+		if sub.Limit == "" && sub.Offset == "" {
+			sub.Order = ""
+		}
+		sql.clean()
+		sql.FromSub(sub, "t")
+	}
+
 	if res, err = sql.Select("count(*)").First(); err != nil {
 		return 0, err
 	}
@@ -371,6 +558,126 @@ func (sql *SQL) LeftJoin(table string, fieldA string, operation string, fieldB s
 	return sql
 }
 
+// InnerJoin add a inner join info.
+func (sql *SQL) InnerJoin(table string, fieldA string, operation string, fieldB string) *SQL {
+	return sql.Join(dialect.JoinInner, table, fieldA, operation, fieldB)
+}
+
+// RightJoin add a right join info. The right join is not supported by the sqlite before 3.39.
+func (sql *SQL) RightJoin(table string, fieldA string, operation string, fieldB string) *SQL {
+	return sql.Join(dialect.JoinRight, table, fieldA, operation, fieldB)
+}
+
+// CrossJoin add a cross join info.
+func (sql *SQL) CrossJoin(table string) *SQL {
+	return sql.Join(dialect.JoinCross, table, "", "", "")
+}
+
+// Join add a join info of given type(dialect.JoinLeft, dialect.JoinInner, dialect.JoinRight
+// or dialect.JoinCross).
+func (sql *SQL) Join(typ, table string, fieldA string, operation string, fieldB string) *SQL {
+	sql.Leftjoins = append(sql.Leftjoins, dialect.Join{
+		Type:      typ,
+		FieldA:    fieldA,
+		FieldB:    fieldB,
+		Table:     table,
+		Operation: operation,
+	})
+	return sql
+}
+
+// JoinSub add a join info of the subquery with the alias.
+// ex: JoinSub(dialect.JoinInner, sub, "r", "r.user_id", "=", "goadmin_users.id")
+func (sql *SQL) JoinSub(typ string, sub *SQL, alias string, fieldA string, operation string, fieldB string) *SQL {
+	statement, args := sql.subQuery(sub)
+	sql.Leftjoins = append(sql.Leftjoins, dialect.Join{
+		Type:      typ,
+		FieldA:    fieldA,
+		FieldB:    fieldB,
+		Table:     statement,
+		Alias:     alias,
+		Sub:       true,
+		Operation: operation,
+	})
+	sql.JoinArgs = append(sql.JoinArgs, args...)
+	return sql
+}
+
+// On add the other condition to the last join.
+// ex: InnerJoin("b", "a.id", "=", "b.a_id").On("a.type", "=", "b.type")
+func (sql *SQL) On(fieldA string, operation string, fieldB string) *SQL {
+	return sql.on(dialect.ConjAnd, fieldA, operation, fieldB)
+}
+
+// OrOn add the other condition joined by "or" to the last join.
+func (sql *SQL) OrOn(fieldA string, operation string, fieldB string) *SQL {
+	return sql.on(dialect.ConjOr, fieldA, operation, fieldB)
+}
+
+func (sql *SQL) on(conj, fieldA string, operation string, fieldB string) *SQL {
+	if len(sql.Leftjoins) == 0 {
+		panic("on: no join")
+	}
+	last := &sql.Leftjoins[len(sql.Leftjoins)-1]
+	last.Ons = append(last.Ons, dialect.JoinOn{
+		Conj:      conj,
+		FieldA:    fieldA,
+		Operation: operation,
+		FieldB:    fieldB,
+	})
+	return sql
+}
+
+// FromSub set the subquery with the alias as the table.
+// ex: WithDriver(conn).FromSub(sub, "t").Where("t.count", ">", 1).All()
+func (sql *SQL) FromSub(sub *SQL, alias string) *SQL {
+	statement, args := sql.subQuery(sub)
+	delimiter := sql.currentDialect(sub).GetDelimiter()
+	if delimiter == "[" {
+		alias = "[" + alias + "]"
+	} else {
+		alias = delimiter + alias + delimiter
+	}
+	sql.TableName = "(" + statement + ") as " + alias
+	sql.TableArgs = args
+	return sql
+}
+
+// Union add the select statement of other SQL, the duplicate rows are removed.
+// 合併其他查詢的結果，排序及筆數限制作用於合併後的結果
+func (sql *SQL) Union(other *SQL) *SQL {
+	return sql.union(false, other)
+}
+
+// UnionAll add the select statement of other SQL and keep the duplicate rows.
+func (sql *SQL) UnionAll(other *SQL) *SQL {
+	return sql.union(true, other)
+}
+
+func (sql *SQL) union(all bool, other *SQL) *SQL {
+	statement, args := sql.subQuery(other)
+	sql.Unions = append(sql.Unions, dialect.Union{All: all, Statement: statement})
+	sql.UnionArgs = append(sql.UnionArgs, args...)
+	return sql
+}
+
+// subQuery 以目前SQL的dialect產生子查詢的語法及參數
+func (sql *SQL) subQuery(sub *SQL) (string, []interface{}) {
+	statement := sql.currentDialect(sub).Select(&sub.SQLComponent)
+	return statement, sub.Args
+}
+
+// 未設置driver時(ex: Table)使用子查詢或預設資料庫的dialect
+func (sql *SQL) currentDialect(sub *SQL) dialect.Dialect {
+	if sql.dialect != nil {
+		return sql.dialect
+	}
+	if sub != nil && sub.dialect != nil {
+		return sub.dialect
+	}
+	return dialect.GetDialect()
+}
+
 // *******************************
 // Transaction method
 // *******************************
@@ -382,7 +689,10 @@ type TxFn func(tx *dbsql.Tx) (error, map[string]interface{})
 // catch the error.
 func (sql *SQL) WithTransaction(fn TxFn) (res map[string]interface{}, err error) {
 
-	tx := sql.diver.BeginTxAndConnection(sql.conn)
+	tx, err := sql.diver.BeginTxWithContext(sql.getContext(), sql.conn, dbsql.LevelDefault)
+	if err != nil {
+		return nil, err
+	}
 
 	defer func() {
 		if p := recover(); p != nil {
@@ -406,7 +716,10 @@ func (sql *SQL) WithTransaction(fn TxFn) (res map[string]interface{}, err error)
 // of given transaction level and catch the error.
 func (sql *SQL) WithTransactionByLevel(level dbsql.IsolationLevel, fn TxFn) (res map[string]interface{}, err error) {
 
-	tx := sql.diver.BeginTxWithLevelAndConnection(sql.conn, level)
+	tx, err := sql.diver.BeginTxWithContext(sql.getContext(), sql.conn, level)
+	if err != nil {
+		return nil, err
+	}
 
 	defer func() {
 		if p := recover(); p != nil {
@@ -448,11 +761,7 @@ func (sql *SQL) First() (map[string]interface{}, error) {
 	)
 
 	//假設有tx在tx中執行查詢，反之一般資料庫執行
-	if sql.tx != nil {
-		res, err = sql.diver.QueryWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.QueryWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	res, err = sql.query(sql.Statement, sql.Args...)
 
 	if err != nil {
 		return nil, err
@@ -472,10 +781,104 @@ func (sql *SQL) All() ([]map[string]interface{}, error) {
 
 	sql.dialect.Select(&sql.SQLComponent)
 
+	return sql.query(sql.Statement, sql.Args...)
+}
+
+// FirstInto query the first row and set it to the struct pointed by dest, see ScanStruct for
+// the mapping of the fields.
+// 查詢第一筆資料並設置至dest指向的結構(以tag(db)對應欄位)
+func (sql *SQL) FirstInto(dest interface{}) error {
+	res, err := sql.First()
+	if err != nil {
+		return err
+	}
+	return ScanStruct(res, dest)
+}
+
+// AllInto query all the rows and set them to the slice pointed by dest, the element of the
+// slice can be a struct or a pointer of struct.
+// 查詢所有資料並設置至dest指向的slice
+func (sql *SQL) AllInto(dest interface{}) error {
+	res, err := sql.All()
+	if err != nil {
+		return err
+	}
+	return ScanStructs(res, dest)
+}
+
+// Rows query the result and return the iterator which reads the rows one by one, the Rows
+// must be closed after used.
+// 查詢並回傳逐筆讀取的Rows，適用於大量資料，使用後必須關閉
+func (sql *SQL) Rows() (*Rows, error) {
+	defer RecycleSQL(sql)
+
+	sql.dialect.Select(&sql.SQLComponent)
+
+	ctx := sql.getContext()
+
 	if sql.tx != nil {
-		return sql.diver.QueryWithTx(sql.tx, sql.Statement, sql.Args...)
+		return sql.diver.QueryRowsWithTx(ctx, sql.tx, sql.Statement, sql.Args...)
+	}
+	return sql.diver.QueryRowsWithConnection(ctx, sql.conn, sql.Statement, sql.Args...)
+}
+
+// Each call the function with every row of the result, the rows are read one by one. The
+// iteration stops when the function return an error, which is returned unless it is
+// ErrStopIteration.
+// 逐筆讀取資料並呼叫fn，fn回傳錯誤時停止(ErrStopIteration不視為錯誤)
+func (sql *SQL) Each(fn func(row map[string]interface{}) error) error {
+	rows, err := sql.Rows()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		if err := fn(rows.Row()); err != nil {
+			if err == ErrStopIteration {
+				return nil
+			}
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Chunk call the function with every size rows of the result, the rows are read one by one
+// by the same query and only size rows are kept in memory.
+// 逐筆讀取資料，每size筆呼叫一次fn(最後一次可能少於size筆)，記憶體中最多只保留size筆資料
+func (sql *SQL) Chunk(size int, fn func(rows []map[string]interface{}) error) error {
+	if size <= 0 {
+		panic("wrong chunk size")
+	}
+
+	chunk := make([]map[string]interface{}, 0, size)
+	call := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		err := fn(chunk)
+		chunk = make([]map[string]interface{}, 0, size)
+		return err
+	}
+
+	err := sql.Each(func(row map[string]interface{}) error {
+		chunk = append(chunk, row)
+		if len(chunk) < size {
+			return nil
+		}
+		return call()
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := call(); err != nil && err != ErrStopIteration {
+		return err
 	}
-	return sql.diver.QueryWithConnection(sql.conn, sql.Statement, sql.Args...)
+	return nil
 }
 
 // ShowColumns show columns info.
@@ -486,14 +889,14 @@ func (sql *SQL) ShowColumns() ([]map[string]interface{}, error) {
 
 	// QueryWithConnection有給定連接(conn)名稱，透過參數con查詢db.DbList[sql.conn]資料並回傳
 	// ShowColumns透過參數回傳 "show columns in " + sql.TableNam
-	return sql.diver.QueryWithConnection(sql.conn, sql.dialect.ShowColumns(sql.TableName))
+	return sql.diver.QueryWithConnectionContext(sql.getContext(), sql.conn, sql.dialect.ShowColumns(sql.TableName))
 }
 
 // ShowTables show table info.
 func (sql *SQL) ShowTables() ([]string, error) {
 	defer RecycleSQL(sql)
 
-	models, err := sql.diver.QueryWithConnection(sql.conn, sql.dialect.ShowTables())
+	models, err := sql.diver.QueryWithConnectionContext(sql.getContext(), sql.conn, sql.dialect.ShowTables())
 
 	if err != nil {
 		return []string{}, err
@@ -540,11 +943,7 @@ func (sql *SQL) Update(values dialect.H) (int64, error) {
 		err error
 	)
 
-	if sql.tx != nil {
-		res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	res, err = sql.exec(sql.Statement, sql.Args...)
 
 	if err != nil {
 		return 0, err
@@ -557,6 +956,18 @@ func (sql *SQL) Update(values dialect.H) (int64, error) {
 	return res.LastInsertId()
 }
 
+// UpdateStruct exec the update method with the fields of the struct, the readonly fields and
+// the zero omitempty fields are skipped.
+// 以結構的欄位更新資料(略過readonly及零值的omitempty欄位)
+func (sql *SQL) UpdateStruct(value interface{}) (int64, error) {
+	values, err := StructToH(value)
+	if err != nil {
+		RecycleSQL(sql)
+		return 0, err
+	}
+	return sql.Update(values)
+}
+
 // Delete exec the delete method.
 func (sql *SQL) Delete() error {
 	defer RecycleSQL(sql)
@@ -568,11 +979,7 @@ func (sql *SQL) Delete() error {
 		err error
 	)
 
-	if sql.tx != nil {
-		res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	res, err = sql.exec(sql.Statement, sql.Args...)
 
 	if err != nil {
 		return err
@@ -596,11 +1003,7 @@ func (sql *SQL) Exec() (int64, error) {
 		err error
 	)
 
-	if sql.tx != nil {
-		res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	res, err = sql.exec(sql.Statement, sql.Args...)
 
 	if err != nil {
 		return 0, err
@@ -637,11 +1040,8 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 			sql.TableName == "goadmin_roles" ||
 			sql.TableName == "goadmin_users" {
 
-			if sql.tx != nil {
-				resMap, err = sql.diver.QueryWithTx(sql.tx, sql.Statement+" RETURNING id", sql.Args...)
-			} else {
-				resMap, err = sql.diver.QueryWithConnection(sql.conn, sql.Statement+" RETURNING id", sql.Args...)
-			}
+			// 寫入的語句必須在主資料庫執行
+			resMap, err = sql.UsePrimary().query(sql.Statement+" RETURNING id", sql.Args...)
 
 			if err != nil {
 				return 0, err
@@ -654,13 +1054,8 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 		}
 	}
 
-	if sql.tx != nil {
-		// QueryWithTx是transaction的執行方法
-		res, err = sql.diver.ExecWithTx(sql.tx, sql.Statement, sql.Args...)
-	} else {
-		// ExecWithConnection有給定連接(conn)名稱
-		res, err = sql.diver.ExecWithConnection(sql.conn, sql.Statement, sql.Args...)
-	}
+	// 有tx在tx中執行，反之在給定的連接(conn)執行
+	res, err = sql.exec(sql.Statement, sql.Args...)
 	if err != nil {
 		return 0, err
 	}
@@ -673,6 +1068,18 @@ func (sql *SQL) Insert(values dialect.H) (int64, error) {
 	return res.LastInsertId()
 }
 
+// InsertStruct exec the insert method with the fields of the struct and return the id, the
+// readonly fields and the zero omitempty fields(such as the auto increment id) are skipped.
+// 以結構的欄位新增資料並回傳id(略過readonly及零值的omitempty欄位，例如自動遞增的id)
+func (sql *SQL) InsertStruct(value interface{}) (int64, error) {
+	values, err := StructToH(value)
+	if err != nil {
+		RecycleSQL(sql)
+		return 0, err
+	}
+	return sql.Insert(values)
+}
+
 func (sql *SQL) wrap(field string) string {
 	if sql.diver.Name() == "mssql" {
 		return fmt.Sprintf(`[%s]`, field)
@@ -695,6 +1102,13 @@ func (sql *SQL) clean() {
 	sql.Limit = ""
 	sql.WhereRaws = ""
 	sql.UpdateRaws = make([]dialect.RawUpdate, 0)
+	sql.Havings = make([]dialect.Where, 0)
+	sql.Unions = make([]dialect.Union, 0)
+	sql.SQLComponent.Distinct = false
+	sql.TableArgs = nil
+	sql.JoinArgs = nil
+	sql.HavingArgs = nil
+	sql.UnionArgs = nil
 	sql.Statement = ""
 }
 
@@ -713,6 +1127,8 @@ func RecycleSQL(sql *SQL) {
 	sql.conn = ""
 	sql.diver = nil
 	sql.tx = nil
+	sql.ctx = nil
+	sql.primary = false
 	sql.dialect = nil
 
 	//清空的sql 資訊放入SQLPool中
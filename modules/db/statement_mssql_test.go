@@ -48,3 +48,6 @@ func TestMssqlSQL_Delete(t *testing.T)      { testSQLDelete(t, driverTestMssqlCo
 func TestMssqlSQL_Exec(t *testing.T)        { testSQLExec(t, driverTestMssqlConn) }
 func TestMssqlSQL_Insert(t *testing.T)      { testSQLInsert(t, driverTestMssqlConn) }
 func TestMssqlSQL_Wrap(t *testing.T)        { testSQLWrap(t, driverTestMssqlConn) }
+func TestMssqlSQL_Each(t *testing.T)        { testSQLEach(t, driverTestMssqlConn) }
+func TestMssqlSQL_Chunk(t *testing.T)       { testSQLChunk(t, driverTestMssqlConn) }
+func TestMssqlSQL_WithContext(t *testing.T) { testSQLWithContext(t, driverTestMssqlConn) }
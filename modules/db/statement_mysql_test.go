@@ -60,3 +60,6 @@ func TestMysqlSQL_Delete(t *testing.T)      { testSQLDelete(t, driverTestMysqlCo
 func TestMysqlSQL_Exec(t *testing.T)        { testSQLExec(t, driverTestMysqlConn) }
 func TestMysqlSQL_Insert(t *testing.T)      { testSQLInsert(t, driverTestMysqlConn) }
 func TestMysqlSQL_Wrap(t *testing.T)        { testSQLWrap(t, driverTestMysqlConn) }
+func TestMysqlSQL_Each(t *testing.T)        { testSQLEach(t, driverTestMysqlConn) }
+func TestMysqlSQL_Chunk(t *testing.T)       { testSQLChunk(t, driverTestMysqlConn) }
+func TestMysqlSQL_WithContext(t *testing.T) { testSQLWithContext(t, driverTestMysqlConn) }
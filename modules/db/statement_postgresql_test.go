@@ -61,3 +61,6 @@ func TestPgSQL_Delete(t *testing.T)      { testSQLDelete(t, driverTestPgConn) }
 func TestPgSQL_Exec(t *testing.T)        { testSQLExec(t, driverTestPgConn) }
 func TestPgSQL_Insert(t *testing.T)      { testSQLInsert(t, driverTestPgConn) }
 func TestPgSQL_Wrap(t *testing.T)        { testSQLWrap(t, driverTestPgConn) }
+func TestPgSQL_Each(t *testing.T)        { testSQLEach(t, driverTestPgConn) }
+func TestPgSQL_Chunk(t *testing.T)       { testSQLChunk(t, driverTestPgConn) }
+func TestPgSQL_WithContext(t *testing.T) { testSQLWithContext(t, driverTestPgConn) }
@@ -42,3 +42,6 @@ func TestSQLiteSQL_Delete(t *testing.T)      { testSQLDelete(t, driverTestSQLite
 func TestSQLiteSQL_Exec(t *testing.T)        { testSQLExec(t, driverTestSQLiteConn) }
 func TestSQLiteSQL_Insert(t *testing.T)      { testSQLInsert(t, driverTestSQLiteConn) }
 func TestSQLiteSQL_Wrap(t *testing.T)        { testSQLWrap(t, driverTestSQLiteConn) }
+func TestSQLiteSQL_Each(t *testing.T)        { testSQLEach(t, driverTestSQLiteConn) }
+func TestSQLiteSQL_Chunk(t *testing.T)       { testSQLChunk(t, driverTestSQLiteConn) }
+func TestSQLiteSQL_WithContext(t *testing.T) { testSQLWithContext(t, driverTestSQLiteConn) }
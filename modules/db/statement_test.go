@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/mssql"
 	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/postgres"
@@ -23,6 +24,17 @@ func testSQLWhereIn(t *testing.T, conn Connection) {
 func testSQLCount(t *testing.T, conn Connection) {
 	count, _ := WithDriver(conn).Table("goadmin_users").Count()
 	assert.Equal(t, count, int64(2))
+
+	count, _ = WithDriver(conn).Table("goadmin_role_users").Select("role_id").Distinct().Count()
+	assert.Equal(t, count, int64(2))
+
+	count, _ = WithDriver(conn).Table("goadmin_role_users").Select("role_id").GroupBy("role_id").
+		HavingRaw("count(*) > ?", 0).Count()
+	assert.Equal(t, count, int64(2))
+
+	count, _ = WithDriver(conn).Table("goadmin_users").Select("id").
+		UnionAll(WithDriver(conn).Table("goadmin_users").Select("id")).Count()
+	assert.Equal(t, count, int64(4))
 }
 
 // TODO
@@ -102,3 +114,42 @@ func testSQLInsert(t *testing.T, conn Connection) {}
 
 // TODO
 func testSQLWrap(t *testing.T, conn Connection) {}
+
+func testSQLEach(t *testing.T, conn Connection) {
+	count := 0
+	err := WithDriver(conn).Table("goadmin_users").Each(func(row map[string]interface{}) error {
+		count++
+		return nil
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, 2)
+
+	count = 0
+	err = WithDriver(conn).Table("goadmin_users").Each(func(row map[string]interface{}) error {
+		count++
+		return ErrStopIteration
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, count, 1)
+}
+
+func testSQLChunk(t *testing.T, conn Connection) {
+	sizes := make([]int, 0)
+	err := WithDriver(conn).Table("goadmin_users").Chunk(1, func(rows []map[string]interface{}) error {
+		sizes = append(sizes, len(rows))
+		return nil
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, sizes, []int{1, 1})
+}
+
+func testSQLWithContext(t *testing.T, conn Connection) {
+	item, err := WithDriver(conn).WithContext(context.Background()).Table("goadmin_users").First()
+	assert.Equal(t, err, nil)
+	assert.Equal(t, item["id"], int64(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = WithDriver(conn).WithContext(ctx).Table("goadmin_users").All()
+	assert.Equal(t, err, context.Canceled)
+}
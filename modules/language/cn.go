@@ -288,6 +288,149 @@ var cn = LangSet{
 	"system.theme_name":       "主题",
 	"system.theme_version":    "主题版本",
 
+	"validation failed":          "参数验证失败",
+	"is required":                "为必填",
+	"must be a positive integer": "必须为正整数",
+	"must be asc or desc":        "必须为asc或desc",
+	"unknown field":              "未知的字段",
+
+	"two factor authentication":                       "两步验证",
+	"two factor authentication is required":           "需要开启两步验证",
+	"please enter the code of your authenticator app": "请输入身份验证器应用中的验证码",
+	"scan the qrcode with your authenticator app":     "请使用身份验证器应用扫描二维码，或手动输入密钥",
+	"secret":     "密钥",
+	"code":       "验证码",
+	"verify":     "验证",
+	"wrong code": "验证码错误",
+	"too many wrong codes, please login again": "验证码错误次数过多，请重新登录",
+	"recovery codes":                        "恢复码",
+	"save the recovery codes":               "请将恢复码保存在安全的地方，每个恢复码可代替验证码使用一次",
+	"regenerate recovery codes":             "重新生成恢复码",
+	"enable two factor authentication":      "开启两步验证",
+	"disable two factor authentication":     "关闭两步验证",
+	"two factor authentication is enabled":  "两步验证已开启",
+	"two factor authentication is disabled": "两步验证未开启",
+	"continue":                              "继续",
+
+	"login lockout": "登录锁定",
+	"too many login attempts, please try again later": "登录尝试次数过多，请稍后再试",
+	"type":            "类型",
+	"ip":              "IP",
+	"target":          "对象",
+	"failures":        "失败次数",
+	"lock times":      "锁定次数",
+	"last failure at": "最后失败时间",
+	"locked until":    "锁定至",
+	"unlock":          "解锁",
+	"unlock success":  "解锁成功",
+
+	"password must be at least %d characters":               "密码长度至少为%d个字符",
+	"password must contain an uppercase letter":             "密码必须包含大写字母",
+	"password must contain a lowercase letter":              "密码必须包含小写字母",
+	"password must contain a digit":                         "密码必须包含数字",
+	"password must contain a symbol":                        "密码必须包含符号",
+	"password can not be the same as the last %d passwords": "密码不能与最近%d次的密码相同",
+	"password expired, please set a new password":           "密码已过期，请设置新密码",
+	"new password":                      "新密码",
+	"login expired, please login again": "登录已过期，请重新登录",
+
+	"sso provider not found":                        "单点登录服务不存在",
+	"sso login failed":                              "单点登录失败",
+	"password login is disabled":                    "已禁用密码登录，请使用单点登录",
+	"user exists and is not linked to the identity": "用户已存在且未绑定此身份，请联系管理员",
+
+	"row scope":          "行级数据权限",
+	"row scope table":    "数据表",
+	"row scope field":    "字段",
+	"row scope operator": "运算符",
+	"row scope value":    "值",
+	"the prefix of the table generator, for example: manager": "数据表生成器的前缀，例如：manager",
+	"choose a role or a permission":                           "请选择一个角色或权限",
+	"the values of in operator are separated by comma, variables: {user.id}, {user.username}, {user.name}, {user.roles}": "in 运算符的多个值以逗号分隔，可使用变量：{user.id}、{user.username}、{user.name}、{user.roles}",
+
+	"audit log":   "数据变更记录",
+	"audit table": "数据表",
+	"record id":   "记录ID",
+	"changes":     "变更内容",
+	"old values":  "变更前",
+	"new values":  "变更后",
+	"insert":      "新增",
+	"update":      "更新",
+
+	"recycle bin":             "回收站",
+	"restore":                 "还原",
+	"purge":                   "永久删除",
+	"are you sure to restore": "确定要还原吗",
+	"are you sure to purge":   "确定要永久删除吗",
+	"restore fail":            "还原失败",
+	"purge fail":              "永久删除失败",
+
+	"parent roles":          "上级角色",
+	"effective permissions": "有效权限",
+	"inherit the permissions of the parent roles": "继承上级角色的权限及菜单",
+	"role inheritance cycle":                      "上级角色形成循环继承",
+
+	"test permission":        "测试权限",
+	"permission path syntax": "以!开头为拒绝规则，glob:/info/* 或 regex:/info/.+ 可使用通配符",
+	"no route matched":       "没有匹配的路由",
+	"grant":                  "允许",
+	"deny":                   "拒绝",
+
+	"log in as":                          "以此用户登录",
+	"are you sure to log in as the user": "确定要以此用户的身份登录吗？",
+	"impersonate fail":                   "以此用户登录失败",
+	"exit impersonation fail":            "退出用户身份失败",
+	"%s is logged in as %s":              "%s 正以 %s 的身份登录",
+	"exit impersonation":                 "退出用户身份",
+	"impersonator":                       "代理登录者",
+	"login_as":                           "以用户身份登录",
+	"logout_as":                          "退出用户身份",
+
+	"sessions":                        "会话",
+	"user agent":                      "浏览器",
+	"last seen":                       "最后活动时间",
+	"current session":                 "当前会话",
+	"revoke all sessions of the user": "撤销该用户所有会话",
+	"are you sure to revoke all sessions of the user": "确定要撤销该用户所有会话吗？",
+	"revoke success": "撤销成功",
+	"revoke fail":    "撤销失败",
+
+	"api keys":                           "API密钥",
+	"api key":                            "API密钥",
+	"service account":                    "服务账号",
+	"expires at":                         "过期时间",
+	"never":                              "永不",
+	"last used at":                       "最后使用时间",
+	"last used ip":                       "最后使用IP",
+	"rotate":                             "更换",
+	"rotate fail":                        "更换失败",
+	"are you sure to rotate the api key": "确定要更换API密钥吗？旧的密钥将立即失效",
+	"the api key is only shown once":     "API密钥只会显示一次，请立即复制",
+	"send the api key in the header":     "请在请求头中携带API密钥",
+	"the api key only has the selected permissions": "API密钥只拥有所选且账号也拥有的权限",
+	"never expires if empty":                        "为空时永不过期",
+
+	"the change is submitted and waiting for approval": "变更已提交，等待审核",
+	"notice":                               "提示",
+	"change request":                       "变更申请",
+	"change requests":                      "变更申请",
+	"change request not found":             "变更申请不存在",
+	"the change request has been reviewed": "变更申请已被审核",
+	"applicant":                            "申请者",
+	"reviewer":                             "审核者",
+	"reviewed at":                          "审核时间",
+	"status":                               "状态",
+	"comment":                              "意见",
+	"comment is required":                  "请填写意见",
+	"field":                                "字段",
+	"review":                               "审核",
+	"approve":                              "核准",
+	"reject":                               "驳回",
+	"review fail":                          "审核失败",
+	"pending":                              "待审核",
+	"approved":                             "已核准",
+	"rejected":                             "已驳回",
+
 	"tool.tool":                 "工具",
 	"tool.table":                "表格",
 	"tool.connection":           "连接",
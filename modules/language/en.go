@@ -253,6 +253,150 @@ var en = LangSet{
 	"system.theme_name":       "Theme",
 	"system.theme_version":    "Theme Version",
 
+	"validation failed":          "Validation Failed",
+	"is required":                "Is Required",
+	"must be a positive integer": "Must Be A Positive Integer",
+	"must be asc or desc":        "Must Be Asc Or Desc",
+	"unknown field":              "Unknown Field",
+
+	"two factor authentication":                       "Two Factor Authentication",
+	"two factor authentication is required":           "Two Factor Authentication Is Required",
+	"please enter the code of your authenticator app": "Please enter the code of your authenticator app",
+	"scan the qrcode with your authenticator app":     "Scan the QR code with your authenticator app, or enter the secret manually",
+	"secret":     "Secret",
+	"code":       "Code",
+	"verify":     "Verify",
+	"wrong code": "Wrong Code",
+	"too many wrong codes, please login again": "Too many wrong codes, please login again",
+	"recovery codes":                        "Recovery Codes",
+	"save the recovery codes":               "Save these recovery codes in a safe place, each code can be used once instead of the authenticator code",
+	"regenerate recovery codes":             "Regenerate Recovery Codes",
+	"enable two factor authentication":      "Enable Two Factor Authentication",
+	"disable two factor authentication":     "Disable Two Factor Authentication",
+	"two factor authentication is enabled":  "Two factor authentication is enabled",
+	"two factor authentication is disabled": "Two factor authentication is disabled",
+	"continue":                              "Continue",
+
+	"login lockout": "Login Lockout",
+	"too many login attempts, please try again later": "Too many login attempts, please try again later",
+	"type":            "Type",
+	"ip":              "IP",
+	"target":          "Target",
+	"failures":        "Failures",
+	"lock times":      "Lock Times",
+	"last failure at": "Last Failure At",
+	"locked until":    "Locked Until",
+	"unlock":          "Unlock",
+	"unlock success":  "Unlock Success",
+
+	"password must be at least %d characters":               "Password must be at least %d characters",
+	"password must contain an uppercase letter":             "Password must contain an uppercase letter",
+	"password must contain a lowercase letter":              "Password must contain a lowercase letter",
+	"password must contain a digit":                         "Password must contain a digit",
+	"password must contain a symbol":                        "Password must contain a symbol",
+	"password can not be the same as the last %d passwords": "Password can not be the same as the last %d passwords",
+	"password expired, please set a new password":           "Password expired, please set a new password",
+	"new password":                      "New Password",
+	"login expired, please login again": "Login expired, please login again",
+
+	"sso provider not found":                        "SSO provider not found",
+	"sso login failed":                              "SSO login failed",
+	"password login is disabled":                    "Password login is disabled, please use single sign-on",
+	"user exists and is not linked to the identity": "The user already exists and is not linked to this identity, please contact the administrator",
+
+	"row scope":          "Row Scope",
+	"row scope table":    "Table",
+	"row scope field":    "Field",
+	"row scope operator": "Operator",
+	"row scope value":    "Value",
+	"the prefix of the table generator, for example: manager": "The prefix of the table generator, for example: manager",
+	"choose a role or a permission":                           "Choose a role or a permission",
+	"the values of in operator are separated by comma, variables: {user.id}, {user.username}, {user.name}, {user.roles}": "The values of in operator are separated by comma, variables: {user.id}, {user.username}, {user.name}, {user.roles}",
+
+	"audit log":   "Audit Log",
+	"audit table": "Table",
+	"record id":   "Record ID",
+	"changes":     "Changes",
+	"old values":  "Old Values",
+	"new values":  "New Values",
+	"insert":      "Insert",
+	"update":      "Update",
+
+	"recycle bin":             "Recycle Bin",
+	"restore":                 "Restore",
+	"purge":                   "Purge",
+	"are you sure to restore": "Are you sure to restore",
+	"are you sure to purge":   "Are you sure to purge permanently",
+	"restore fail":            "Restore fail",
+	"purge fail":              "Purge fail",
+
+	"parent roles":          "Parent Roles",
+	"effective permissions": "Effective Permissions",
+	"inherit the permissions of the parent roles": "inherit the permissions and menus of the parent roles",
+	"role inheritance cycle":                      "the parent roles make an inheritance cycle",
+
+	"test permission":        "test permission",
+	"permission path syntax": "prefix ! to deny, glob:/info/* or regex:/info/.+ to match with wildcards",
+	"no route matched":       "no route matched",
+	"grant":                  "grant",
+	"deny":                   "deny",
+
+	"log in as":                          "log in as",
+	"are you sure to log in as the user": "are you sure to log in as the user?",
+	"impersonate fail":                   "log in as the user failed",
+	"exit impersonation fail":            "exit impersonation failed",
+	"%s is logged in as %s":              "%s is logged in as %s",
+	"exit impersonation":                 "exit impersonation",
+	"impersonator":                       "impersonator",
+	"login_as":                           "log in as",
+	"logout_as":                          "exit impersonation",
+
+	"sessions":                        "sessions",
+	"user agent":                      "user agent",
+	"last seen":                       "last seen",
+	"current session":                 "current session",
+	"revoke all sessions of the user": "revoke all sessions of the user",
+	"are you sure to revoke all sessions of the user": "are you sure to revoke all sessions of the user?",
+	"revoke success": "revoke success",
+	"revoke fail":    "revoke fail",
+
+	"api keys":                           "api keys",
+	"api key":                            "api key",
+	"service account":                    "service account",
+	"expires at":                         "expires at",
+	"never":                              "never",
+	"last used at":                       "last used at",
+	"last used ip":                       "last used ip",
+	"rotate":                             "rotate",
+	"rotate fail":                        "rotate fail",
+	"are you sure to rotate the api key": "are you sure to rotate the api key? the old key will be invalid immediately",
+	"the api key is only shown once":     "the api key is only shown once, please copy it now",
+	"send the api key in the header":     "send the api key in the header",
+	"the api key only has the selected permissions": "the api key only has the selected permissions which the account also has",
+	"never expires if empty":                        "never expires if empty",
+
+	"the change is submitted and waiting for approval": "The change is submitted and waiting for approval",
+	"notice":                               "Notice",
+	"change request":                       "Change Request",
+	"change requests":                      "Change Requests",
+	"change request not found":             "change request not found",
+	"the change request has been reviewed": "the change request has been reviewed",
+	"applicant":                            "Applicant",
+	"reviewer":                             "Reviewer",
+	"reviewed at":                          "Reviewed At",
+	"status":                               "Status",
+	"comment":                              "Comment",
+	"comment is required":                  "comment is required",
+	"field":                                "Field",
+	"review":                               "Review",
+	"approve":                              "Approve",
+	"reject":                               "Reject",
+	"review fail":                          "review fail",
+	"pending":                              "Pending",
+	"approved":                             "Approved",
+	"rejected":                             "Rejected",
+	"permission denied":                    "permission denied",
+
 	"tool.tool":                   "Tool",
 	"tool.table":                  "Table",
 	"tool.connection":             "Connection",
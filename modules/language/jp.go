@@ -264,6 +264,149 @@ var jp = LangSet{
 	"system.theme_name":       "Theme",
 	"system.theme_version":    "Theme Version",
 
+	"validation failed":          "検証に失敗しました",
+	"is required":                "必須です",
+	"must be a positive integer": "正の整数である必要があります",
+	"must be asc or desc":        "ascまたはdescである必要があります",
+	"unknown field":              "不明なフィールド",
+
+	"two factor authentication":                       "二段階認証",
+	"two factor authentication is required":           "二段階認証が必要です",
+	"please enter the code of your authenticator app": "認証アプリのコードを入力してください",
+	"scan the qrcode with your authenticator app":     "認証アプリでQRコードをスキャンするか、シークレットを手動で入力してください",
+	"secret":     "シークレット",
+	"code":       "コード",
+	"verify":     "確認",
+	"wrong code": "コードが間違っています",
+	"too many wrong codes, please login again": "コードの誤りが多すぎます。再度ログインしてください",
+	"recovery codes":                        "リカバリーコード",
+	"save the recovery codes":               "リカバリーコードを安全な場所に保存してください。各コードは認証コードの代わりに一度だけ使用できます",
+	"regenerate recovery codes":             "リカバリーコードを再生成",
+	"enable two factor authentication":      "二段階認証を有効にする",
+	"disable two factor authentication":     "二段階認証を無効にする",
+	"two factor authentication is enabled":  "二段階認証は有効です",
+	"two factor authentication is disabled": "二段階認証は無効です",
+	"continue":                              "続ける",
+
+	"login lockout": "ログインロック",
+	"too many login attempts, please try again later": "ログイン試行回数が多すぎます。しばらくしてから再試行してください",
+	"type":            "タイプ",
+	"ip":              "IP",
+	"target":          "対象",
+	"failures":        "失敗回数",
+	"lock times":      "ロック回数",
+	"last failure at": "最終失敗日時",
+	"locked until":    "ロック期限",
+	"unlock":          "ロック解除",
+	"unlock success":  "ロックを解除しました",
+
+	"password must be at least %d characters":               "パスワードは%d文字以上必要です",
+	"password must contain an uppercase letter":             "パスワードには大文字を含める必要があります",
+	"password must contain a lowercase letter":              "パスワードには小文字を含める必要があります",
+	"password must contain a digit":                         "パスワードには数字を含める必要があります",
+	"password must contain a symbol":                        "パスワードには記号を含める必要があります",
+	"password can not be the same as the last %d passwords": "パスワードは直近%d回のパスワードと同じにできません",
+	"password expired, please set a new password":           "パスワードの有効期限が切れました。新しいパスワードを設定してください",
+	"new password":                      "新しいパスワード",
+	"login expired, please login again": "ログインの有効期限が切れました。再度ログインしてください",
+
+	"sso provider not found":                        "SSOプロバイダーが見つかりません",
+	"sso login failed":                              "SSOログインに失敗しました",
+	"password login is disabled":                    "パスワードログインは無効です。シングルサインオンを使用してください",
+	"user exists and is not linked to the identity": "ユーザーは既に存在し、このIDに紐付けられていません。管理者に連絡してください",
+
+	"row scope":          "行レベルデータ権限",
+	"row scope table":    "テーブル",
+	"row scope field":    "フィールド",
+	"row scope operator": "演算子",
+	"row scope value":    "値",
+	"the prefix of the table generator, for example: manager": "テーブルジェネレーターのプレフィックス。例：manager",
+	"choose a role or a permission":                           "ロールまたは権限を一つ選択してください",
+	"the values of in operator are separated by comma, variables: {user.id}, {user.username}, {user.name}, {user.roles}": "in 演算子の複数の値はカンマで区切ります。変数：{user.id}、{user.username}、{user.name}、{user.roles}",
+
+	"audit log":   "データ変更履歴",
+	"audit table": "テーブル",
+	"record id":   "レコードID",
+	"changes":     "変更内容",
+	"old values":  "変更前",
+	"new values":  "変更後",
+	"insert":      "追加",
+	"update":      "更新",
+
+	"recycle bin":             "ごみ箱",
+	"restore":                 "復元",
+	"purge":                   "完全に削除",
+	"are you sure to restore": "復元してもよろしいですか",
+	"are you sure to purge":   "完全に削除してもよろしいですか",
+	"restore fail":            "復元に失敗しました",
+	"purge fail":              "完全削除に失敗しました",
+
+	"parent roles":          "親ロール",
+	"effective permissions": "有効な権限",
+	"inherit the permissions of the parent roles": "親ロールの権限とメニューを継承します",
+	"role inheritance cycle":                      "親ロールが循環継承になります",
+
+	"test permission":        "権限をテスト",
+	"permission path syntax": "!で始まると拒否ルール、glob:/info/* または regex:/info/.+ でワイルドカードを使用",
+	"no route matched":       "一致するルートがありません",
+	"grant":                  "許可",
+	"deny":                   "拒否",
+
+	"log in as":                          "このユーザーとしてログイン",
+	"are you sure to log in as the user": "このユーザーとしてログインしますか？",
+	"impersonate fail":                   "このユーザーとしてのログインに失敗しました",
+	"exit impersonation fail":            "なりすましの終了に失敗しました",
+	"%s is logged in as %s":              "%s は %s としてログインしています",
+	"exit impersonation":                 "なりすましを終了",
+	"impersonator":                       "なりすまし元",
+	"login_as":                           "ユーザーとしてログイン",
+	"logout_as":                          "なりすましを終了",
+
+	"sessions":                        "セッション",
+	"user agent":                      "ユーザーエージェント",
+	"last seen":                       "最終アクセス",
+	"current session":                 "現在のセッション",
+	"revoke all sessions of the user": "このユーザーの全セッションを無効化",
+	"are you sure to revoke all sessions of the user": "このユーザーの全セッションを無効化しますか？",
+	"revoke success": "無効化しました",
+	"revoke fail":    "無効化に失敗しました",
+
+	"api keys":                           "APIキー",
+	"api key":                            "APIキー",
+	"service account":                    "サービスアカウント",
+	"expires at":                         "有効期限",
+	"never":                              "なし",
+	"last used at":                       "最終使用日時",
+	"last used ip":                       "最終使用IP",
+	"rotate":                             "ローテーション",
+	"rotate fail":                        "ローテーションに失敗しました",
+	"are you sure to rotate the api key": "APIキーをローテーションしますか？古いキーはすぐに無効になります",
+	"the api key is only shown once":     "APIキーは一度だけ表示されます。今すぐコピーしてください",
+	"send the api key in the header":     "APIキーをリクエストヘッダーで送信してください",
+	"the api key only has the selected permissions": "APIキーは選択した権限のうち、アカウントも持つ権限のみを持ちます",
+	"never expires if empty":                        "空の場合は無期限",
+
+	"the change is submitted and waiting for approval": "変更は送信され、承認待ちです",
+	"notice":                               "お知らせ",
+	"change request":                       "変更申請",
+	"change requests":                      "変更申請",
+	"change request not found":             "変更申請が見つかりません",
+	"the change request has been reviewed": "変更申請は既に審査されています",
+	"applicant":                            "申請者",
+	"reviewer":                             "審査者",
+	"reviewed at":                          "審査日時",
+	"status":                               "状態",
+	"comment":                              "コメント",
+	"comment is required":                  "コメントを入力してください",
+	"field":                                "フィールド",
+	"review":                               "審査",
+	"approve":                              "承認",
+	"reject":                               "却下",
+	"review fail":                          "審査に失敗しました",
+	"pending":                              "承認待ち",
+	"approved":                             "承認済み",
+	"rejected":                             "却下済み",
+
 	"tool.tool":                   "Tool",
 	"tool.table":                  "Table",
 	"tool.connection":             "Connection",
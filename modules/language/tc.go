@@ -282,6 +282,149 @@ var tc = LangSet{
 	"system.theme_name":       "主題",
 	"system.theme_version":    "主題版本",
 
+	"validation failed":          "參數驗證失敗",
+	"is required":                "為必填",
+	"must be a positive integer": "必須為正整數",
+	"must be asc or desc":        "必須為asc或desc",
+	"unknown field":              "未知的欄位",
+
+	"two factor authentication":                       "兩步驟驗證",
+	"two factor authentication is required":           "需要開啟兩步驟驗證",
+	"please enter the code of your authenticator app": "請輸入驗證器應用程式中的驗證碼",
+	"scan the qrcode with your authenticator app":     "請使用驗證器應用程式掃描QR Code，或手動輸入密鑰",
+	"secret":     "密鑰",
+	"code":       "驗證碼",
+	"verify":     "驗證",
+	"wrong code": "驗證碼錯誤",
+	"too many wrong codes, please login again": "驗證碼錯誤次數過多，請重新登入",
+	"recovery codes":                        "恢復碼",
+	"save the recovery codes":               "請將恢復碼保存在安全的地方，每個恢復碼可代替驗證碼使用一次",
+	"regenerate recovery codes":             "重新產生恢復碼",
+	"enable two factor authentication":      "開啟兩步驟驗證",
+	"disable two factor authentication":     "關閉兩步驟驗證",
+	"two factor authentication is enabled":  "兩步驟驗證已開啟",
+	"two factor authentication is disabled": "兩步驟驗證未開啟",
+	"continue":                              "繼續",
+
+	"login lockout": "登入鎖定",
+	"too many login attempts, please try again later": "登入嘗試次數過多，請稍後再試",
+	"type":            "類型",
+	"ip":              "IP",
+	"target":          "對象",
+	"failures":        "失敗次數",
+	"lock times":      "鎖定次數",
+	"last failure at": "最後失敗時間",
+	"locked until":    "鎖定至",
+	"unlock":          "解鎖",
+	"unlock success":  "解鎖成功",
+
+	"password must be at least %d characters":               "密碼長度至少為%d個字元",
+	"password must contain an uppercase letter":             "密碼必須包含大寫字母",
+	"password must contain a lowercase letter":              "密碼必須包含小寫字母",
+	"password must contain a digit":                         "密碼必須包含數字",
+	"password must contain a symbol":                        "密碼必須包含符號",
+	"password can not be the same as the last %d passwords": "密碼不能與最近%d次的密碼相同",
+	"password expired, please set a new password":           "密碼已過期，請設定新密碼",
+	"new password":                      "新密碼",
+	"login expired, please login again": "登入已過期，請重新登入",
+
+	"sso provider not found":                        "單一登入服務不存在",
+	"sso login failed":                              "單一登入失敗",
+	"password login is disabled":                    "已停用密碼登入，請使用單一登入",
+	"user exists and is not linked to the identity": "用戶已存在且未綁定此身分，請聯絡管理員",
+
+	"row scope":          "行級資料權限",
+	"row scope table":    "資料表",
+	"row scope field":    "欄位",
+	"row scope operator": "運算子",
+	"row scope value":    "值",
+	"the prefix of the table generator, for example: manager": "資料表生成器的前綴，例如：manager",
+	"choose a role or a permission":                           "請選擇一個角色或權限",
+	"the values of in operator are separated by comma, variables: {user.id}, {user.username}, {user.name}, {user.roles}": "in 運算子的多個值以逗號分隔，可使用變數：{user.id}、{user.username}、{user.name}、{user.roles}",
+
+	"audit log":   "資料變更紀錄",
+	"audit table": "資料表",
+	"record id":   "資料ID",
+	"changes":     "變更內容",
+	"old values":  "變更前",
+	"new values":  "變更後",
+	"insert":      "新增",
+	"update":      "更新",
+
+	"recycle bin":             "回收站",
+	"restore":                 "還原",
+	"purge":                   "永久刪除",
+	"are you sure to restore": "確定要還原嗎",
+	"are you sure to purge":   "確定要永久刪除嗎",
+	"restore fail":            "還原失敗",
+	"purge fail":              "永久刪除失敗",
+
+	"parent roles":          "上層角色",
+	"effective permissions": "有效權限",
+	"inherit the permissions of the parent roles": "繼承上層角色的權限及菜單",
+	"role inheritance cycle":                      "上層角色形成循環繼承",
+
+	"test permission":        "測試權限",
+	"permission path syntax": "以!開頭為拒絕規則，glob:/info/* 或 regex:/info/.+ 可使用萬用字元",
+	"no route matched":       "沒有符合的路由",
+	"grant":                  "允許",
+	"deny":                   "拒絕",
+
+	"log in as":                          "以此用戶登入",
+	"are you sure to log in as the user": "確定要以此用戶的身分登入嗎？",
+	"impersonate fail":                   "以此用戶登入失敗",
+	"exit impersonation fail":            "結束用戶身分失敗",
+	"%s is logged in as %s":              "%s 正以 %s 的身分登入",
+	"exit impersonation":                 "結束用戶身分",
+	"impersonator":                       "代理登入者",
+	"login_as":                           "以用戶身分登入",
+	"logout_as":                          "結束用戶身分",
+
+	"sessions":                        "工作階段",
+	"user agent":                      "瀏覽器",
+	"last seen":                       "最後活動時間",
+	"current session":                 "目前工作階段",
+	"revoke all sessions of the user": "撤銷該用戶所有工作階段",
+	"are you sure to revoke all sessions of the user": "確定要撤銷該用戶所有工作階段嗎？",
+	"revoke success": "撤銷成功",
+	"revoke fail":    "撤銷失敗",
+
+	"api keys":                           "API金鑰",
+	"api key":                            "API金鑰",
+	"service account":                    "服務帳號",
+	"expires at":                         "過期時間",
+	"never":                              "永不",
+	"last used at":                       "最後使用時間",
+	"last used ip":                       "最後使用IP",
+	"rotate":                             "更換",
+	"rotate fail":                        "更換失敗",
+	"are you sure to rotate the api key": "確定要更換API金鑰嗎？舊的金鑰將立即失效",
+	"the api key is only shown once":     "API金鑰只會顯示一次，請立即複製",
+	"send the api key in the header":     "請在請求標頭中攜帶API金鑰",
+	"the api key only has the selected permissions": "API金鑰只擁有所選且帳號也擁有的權限",
+	"never expires if empty":                        "空白時永不過期",
+
+	"the change is submitted and waiting for approval": "變更已提交，等待審核",
+	"notice":                               "提示",
+	"change request":                       "變更申請",
+	"change requests":                      "變更申請",
+	"change request not found":             "變更申請不存在",
+	"the change request has been reviewed": "變更申請已被審核",
+	"applicant":                            "申請者",
+	"reviewer":                             "審核者",
+	"reviewed at":                          "審核時間",
+	"status":                               "狀態",
+	"comment":                              "意見",
+	"comment is required":                  "請填寫意見",
+	"field":                                "欄位",
+	"review":                               "審核",
+	"approve":                              "核准",
+	"reject":                               "駁回",
+	"review fail":                          "審核失敗",
+	"pending":                              "待審核",
+	"approved":                             "已核准",
+	"rejected":                             "已駁回",
+
 	"tool.tool":                   "工具",
 	"tool.table":                  "表格",
 	"tool.connection":             "連接",
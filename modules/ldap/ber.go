@@ -0,0 +1,227 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package ldap
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// The classes and the flag of the BER tag.
+const (
+	ClassUniversal   byte = 0x00
+	ClassApplication byte = 0x40
+	ClassContext     byte = 0x80
+	TypeConstructed  byte = 0x20
+)
+
+// The universal tags used by the protocol.
+const (
+	TagBoolean     byte = 0x01
+	TagInteger     byte = 0x02
+	TagOctetString byte = 0x04
+	TagEnumerated  byte = 0x0a
+	TagSequence    byte = 0x30
+	TagSet         byte = 0x31
+)
+
+// 封包最大長度，避免錯誤的長度造成大量記憶體配置
+const maxPacketLength = 16 << 20
+
+var errMalformed = errors.New("ldap: malformed packet")
+
+// Packet is a BER encoded element, Children is used when the tag is constructed.
+// BER編碼的元素，constructed類型的元素使用Children，其他使用Value
+type Packet struct {
+	Tag      byte
+	Value    []byte
+	Children []*Packet
+}
+
+// NewPacket return a primitive packet.
+func NewPacket(tag byte, value []byte) *Packet {
+	return &Packet{Tag: tag, Value: value}
+}
+
+// NewString return a primitive packet of the string.
+func NewString(tag byte, value string) *Packet {
+	return &Packet{Tag: tag, Value: []byte(value)}
+}
+
+// NewInteger return a primitive packet of the integer.
+func NewInteger(tag byte, value int64) *Packet {
+	var b []byte
+	for {
+		b = append([]byte{byte(value)}, b...)
+		value >>= 8
+		if (value == 0 && b[0]&0x80 == 0) || (value == -1 && b[0]&0x80 != 0) {
+			break
+		}
+	}
+	return &Packet{Tag: tag, Value: b}
+}
+
+// NewBoolean return a primitive packet of the boolean.
+func NewBoolean(value bool) *Packet {
+	if value {
+		return &Packet{Tag: TagBoolean, Value: []byte{0xff}}
+	}
+	return &Packet{Tag: TagBoolean, Value: []byte{0x00}}
+}
+
+// NewConstructed return a constructed packet with the children.
+func NewConstructed(tag byte, children ...*Packet) *Packet {
+	return &Packet{Tag: tag | TypeConstructed, Children: children}
+}
+
+// Append add the children to the packet.
+func (p *Packet) Append(children ...*Packet) *Packet {
+	p.Children = append(p.Children, children...)
+	return p
+}
+
+// IsConstructed check the packet is constructed.
+func (p *Packet) IsConstructed() bool {
+	return p.Tag&TypeConstructed != 0
+}
+
+// String return the value as a string.
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+// Int return the value as an integer.
+func (p *Packet) Int() int64 {
+	var v int64
+	for i, b := range p.Value {
+		if i == 0 && b&0x80 != 0 {
+			v = -1
+		}
+		v = v<<8 | int64(b)
+	}
+	return v
+}
+
+// Child return the i-th child or nil.
+func (p *Packet) Child(i int) *Packet {
+	if i < len(p.Children) {
+		return p.Children[i]
+	}
+	return nil
+}
+
+// Bytes return the BER encoding of the packet.
+func (p *Packet) Bytes() []byte {
+	value := p.Value
+	if p.IsConstructed() {
+		value = nil
+		for _, child := range p.Children {
+			value = append(value, child.Bytes()...)
+		}
+	}
+	return append(append([]byte{p.Tag}, encodeLength(len(value))...), value...)
+}
+
+func encodeLength(l int) []byte {
+	if l < 0x80 {
+		return []byte{byte(l)}
+	}
+	var b []byte
+	for ; l > 0; l >>= 8 {
+		b = append([]byte{byte(l)}, b...)
+	}
+	return append([]byte{0x80 | byte(len(b))}, b...)
+}
+
+// ReadPacket read a packet from the reader.
+// 從連線讀取一個完整的元素(僅支援單一位元組的tag)
+func ReadPacket(r *bufio.Reader) (*Packet, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 4 {
+			return nil, errMalformed
+		}
+		length = 0
+		for i := 0; i < n; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxPacketLength {
+		return nil, errMalformed
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return nil, err
+	}
+	return parsePacket(tag, value)
+}
+
+// ParsePacket decode the BER encoded data.
+func ParsePacket(data []byte) (*Packet, error) {
+	p, rest, err := decode(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 {
+		return nil, errMalformed
+	}
+	return p, nil
+}
+
+func parsePacket(tag byte, value []byte) (*Packet, error) {
+	p := &Packet{Tag: tag}
+	if tag&TypeConstructed == 0 {
+		p.Value = value
+		return p, nil
+	}
+	for len(value) > 0 {
+		child, rest, err := decode(value)
+		if err != nil {
+			return nil, err
+		}
+		p.Children = append(p.Children, child)
+		value = rest
+	}
+	return p, nil
+}
+
+func decode(data []byte) (*Packet, []byte, error) {
+	if len(data) < 2 {
+		return nil, nil, errMalformed
+	}
+	tag, first := data[0], data[1]
+	data = data[2:]
+	length := int(first)
+	if first&0x80 != 0 {
+		n := int(first & 0x7f)
+		if n == 0 || n > 4 || len(data) < n {
+			return nil, nil, errMalformed
+		}
+		length = 0
+		for _, b := range data[:n] {
+			length = length<<8 | int(b)
+		}
+		data = data[n:]
+	}
+	if length > len(data) {
+		return nil, nil, errMalformed
+	}
+	p, err := parsePacket(tag, data[:length])
+	return p, data[length:], err
+}
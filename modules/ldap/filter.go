@@ -0,0 +1,193 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+package ldap
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// The context tags of the search filter.
+const (
+	FilterAnd            byte = 0
+	FilterOr             byte = 1
+	FilterNot            byte = 2
+	FilterEqualityMatch  byte = 3
+	FilterSubstrings     byte = 4
+	FilterGreaterOrEqual byte = 5
+	FilterLessOrEqual    byte = 6
+	FilterPresent        byte = 7
+	FilterApproxMatch    byte = 8
+
+	SubstringInitial byte = 0
+	SubstringAny     byte = 1
+	SubstringFinal   byte = 2
+)
+
+var errFilter = errors.New("ldap: invalid filter")
+
+// EscapeFilter escape the special characters of the value used in a filter.
+// 跳脫過濾條件中的特殊字元，避免用戶輸入改變過濾條件(LDAP injection)
+func EscapeFilter(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '\\', '*', '(', ')', 0:
+			b.WriteByte('\\')
+			b.WriteString(hex.EncodeToString([]byte{c}))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// CompileFilter compile the string representation of the filter(RFC 4515) to a packet.
+// 將字串形式的過濾條件，例如(&(objectClass=person)(uid=admin))，轉換成BER元素
+func CompileFilter(filter string) (*Packet, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, errFilter
+	}
+	if filter[0] != '(' {
+		filter = "(" + filter + ")"
+	}
+	p, rest, err := compileFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errFilter
+	}
+	return p, nil
+}
+
+func compileFilter(filter string) (*Packet, string, error) {
+	if len(filter) < 3 || filter[0] != '(' {
+		return nil, "", errFilter
+	}
+	filter = filter[1:]
+
+	switch filter[0] {
+	case '&', '|':
+		tag := FilterAnd
+		if filter[0] == '|' {
+			tag = FilterOr
+		}
+		p := NewConstructed(ClassContext | tag)
+		filter = filter[1:]
+		for len(filter) > 0 && filter[0] == '(' {
+			child, rest, err := compileFilter(filter)
+			if err != nil {
+				return nil, "", err
+			}
+			p.Append(child)
+			filter = rest
+		}
+		if len(filter) == 0 || filter[0] != ')' {
+			return nil, "", errFilter
+		}
+		return p, filter[1:], nil
+	case '!':
+		child, rest, err := compileFilter(filter[1:])
+		if err != nil {
+			return nil, "", err
+		}
+		if len(rest) == 0 || rest[0] != ')' {
+			return nil, "", errFilter
+		}
+		return NewConstructed(ClassContext|FilterNot, child), rest[1:], nil
+	}
+
+	end := strings.IndexByte(filter, ')')
+	if end < 0 {
+		return nil, "", errFilter
+	}
+	p, err := compileItem(filter[:end])
+	return p, filter[end+1:], err
+}
+
+func compileItem(item string) (*Packet, error) {
+	eq := strings.IndexByte(item, '=')
+	if eq < 1 {
+		return nil, errFilter
+	}
+	attr, value := item[:eq], item[eq+1:]
+
+	tag := FilterEqualityMatch
+	switch attr[len(attr)-1] {
+	case '~':
+		tag = FilterApproxMatch
+	case '>':
+		tag = FilterGreaterOrEqual
+	case '<':
+		tag = FilterLessOrEqual
+	}
+	if tag != FilterEqualityMatch {
+		attr = attr[:len(attr)-1]
+	}
+	if attr == "" || strings.ContainsAny(attr, "()&|!*\\") {
+		return nil, errFilter
+	}
+
+	if tag == FilterEqualityMatch && value == "*" {
+		return NewString(ClassContext|FilterPresent, attr), nil
+	}
+
+	parts := strings.Split(value, "*")
+	if tag == FilterEqualityMatch && len(parts) > 1 {
+		subs := NewConstructed(TagSequence)
+		for i, part := range parts {
+			if part == "" {
+				continue
+			}
+			v, err := unescapeFilter(part)
+			if err != nil {
+				return nil, err
+			}
+			subTag := SubstringAny
+			if i == 0 {
+				subTag = SubstringInitial
+			} else if i == len(parts)-1 {
+				subTag = SubstringFinal
+			}
+			subs.Append(NewString(ClassContext|subTag, v))
+		}
+		return NewConstructed(ClassContext|FilterSubstrings, NewString(TagOctetString, attr), subs), nil
+	}
+	if len(parts) > 1 {
+		return nil, errFilter
+	}
+
+	v, err := unescapeFilter(value)
+	if err != nil {
+		return nil, err
+	}
+	return NewConstructed(ClassContext|tag, NewString(TagOctetString, attr), NewString(TagOctetString, v)), nil
+}
+
+func unescapeFilter(value string) (string, error) {
+	if !strings.Contains(value, "\\") {
+		return value, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			b.WriteByte(value[i])
+			continue
+		}
+		if i+3 > len(value) {
+			return "", errFilter
+		}
+		c, err := hex.DecodeString(value[i+1 : i+3])
+		if err != nil {
+			return "", errFilter
+		}
+		b.Write(c)
+		i += 2
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,336 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package ldap is a minimal LDAPv3 client which supports the simple bind, the search
+// and the StartTLS operations.
+// 精簡的LDAP v3用戶端，僅支援simple bind、search及StartTLS，用於後台的LDAP/AD登入
+package ldap
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The application tags of the protocol operations.
+const (
+	ApplicationBindRequest           byte = 0
+	ApplicationBindResponse          byte = 1
+	ApplicationUnbindRequest         byte = 2
+	ApplicationSearchRequest         byte = 3
+	ApplicationSearchResultEntry     byte = 4
+	ApplicationSearchResultDone      byte = 5
+	ApplicationSearchResultReference byte = 19
+	ApplicationExtendedRequest       byte = 23
+	ApplicationExtendedResponse      byte = 24
+)
+
+// The result codes used by the client.
+const (
+	ResultSuccess            = 0
+	ResultSizeLimitExceeded  = 4
+	ResultInvalidCredentials = 49
+)
+
+// The scopes of the search.
+const (
+	ScopeBaseObject   = 0
+	ScopeSingleLevel  = 1
+	ScopeWholeSubtree = 2
+)
+
+// OIDStartTLS is the name of the StartTLS extended operation.
+const OIDStartTLS = "1.3.6.1.4.1.1466.20037"
+
+// Error is the error result returned by the server.
+type Error struct {
+	ResultCode int64
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("ldap: result code %d: %s", e.ResultCode, e.Message)
+}
+
+// IsErrorWithCode check the err is an Error of the result code.
+func IsErrorWithCode(err error, code int64) bool {
+	e, ok := err.(*Error)
+	return ok && e.ResultCode == code
+}
+
+// Entry is an entry of the search result.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// GetAttributeValues return the values of the attribute, the name is case insensitive.
+func (e *Entry) GetAttributeValues(name string) []string {
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// GetAttributeValue return the first value of the attribute.
+func (e *Entry) GetAttributeValue(name string) string {
+	if v := e.GetAttributeValues(name); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// SearchRequest is the parameters of a search.
+type SearchRequest struct {
+	BaseDN     string
+	Scope      int
+	SizeLimit  int
+	Filter     string
+	Attributes []string
+}
+
+// Conn is a connection to the directory server, the operations are executed one by one.
+// 與目錄服務的連線，操作依序執行(不支援同時多個請求)
+type Conn struct {
+	lock    sync.Mutex
+	conn    net.Conn
+	reader  *bufio.Reader
+	msgID   int64
+	timeout time.Duration
+}
+
+// Dial connect to the server of the url, the scheme is ldap or ldaps, the default ports are
+// 389 and 636. The tlsConfig is used by ldaps.
+// 連線至url(ldap://host:389或ldaps://host:636)，timeout同時為每次操作的時間限制
+func Dial(rawUrl string, timeout time.Duration, tlsConfig *tls.Config) (*Conn, error) {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "ldap":
+			host = net.JoinHostPort(u.Hostname(), "389")
+		case "ldaps":
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		conn, err = dialer.Dial("tcp", host)
+	case "ldaps":
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, tlsConfigFor(tlsConfig, u.Hostname()))
+	default:
+		return nil, errors.New("ldap: unsupported scheme " + u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConn(conn, timeout), nil
+}
+
+// NewConn return a Conn of the established connection.
+func NewConn(conn net.Conn, timeout time.Duration) *Conn {
+	return &Conn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		timeout: timeout,
+	}
+}
+
+func tlsConfigFor(cfg *tls.Config, serverName string) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	} else {
+		cfg = cfg.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = serverName
+	}
+	return cfg
+}
+
+// StartTLS upgrade the connection to TLS.
+func (c *Conn) StartTLS(tlsConfig *tls.Config) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	res, err := c.request(NewConstructed(ClassApplication|ApplicationExtendedRequest,
+		NewString(ClassContext|0, OIDStartTLS)), ApplicationExtendedResponse)
+	if err != nil {
+		return err
+	}
+	if err := resultError(res[0]); err != nil {
+		return err
+	}
+
+	host, _, _ := net.SplitHostPort(c.conn.RemoteAddr().String())
+	tlsConn := tls.Client(c.conn, tlsConfigFor(tlsConfig, host))
+	if c.timeout > 0 {
+		_ = tlsConn.SetDeadline(time.Now().Add(c.timeout))
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		return err
+	}
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	return nil
+}
+
+// Bind authenticate the connection with the dn and the password. An empty password is
+// rejected since the server treats it as an unauthenticated bind.
+// 以dn及密碼驗證，密碼為空時直接回傳錯誤(伺服器會視為匿名登入而成功)
+func (c *Conn) Bind(dn, password string) error {
+	if password == "" {
+		return &Error{ResultCode: ResultInvalidCredentials, Message: "empty password"}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	res, err := c.request(NewConstructed(ClassApplication|ApplicationBindRequest,
+		NewInteger(TagInteger, 3),
+		NewString(TagOctetString, dn),
+		NewString(ClassContext|0, password)), ApplicationBindResponse)
+	if err != nil {
+		return err
+	}
+	return resultError(res[0])
+}
+
+// Search search the directory and return the entries.
+func (c *Conn) Search(req SearchRequest) ([]*Entry, error) {
+	filter, err := CompileFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := NewConstructed(TagSequence)
+	for _, attr := range req.Attributes {
+		attrs.Append(NewString(TagOctetString, attr))
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	res, err := c.request(NewConstructed(ClassApplication|ApplicationSearchRequest,
+		NewString(TagOctetString, req.BaseDN),
+		NewInteger(TagEnumerated, int64(req.Scope)),
+		NewInteger(TagEnumerated, 0),
+		NewInteger(TagInteger, int64(req.SizeLimit)),
+		NewInteger(TagInteger, int64(c.timeout/time.Second)),
+		NewBoolean(false),
+		filter,
+		attrs), ApplicationSearchResultDone)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	for _, op := range res[1:] {
+		if op.Tag&^(ClassApplication|TypeConstructed) == ApplicationSearchResultEntry {
+			entries = append(entries, parseEntry(op))
+		}
+	}
+	if err := resultError(res[0]); err != nil && !IsErrorWithCode(err, ResultSizeLimitExceeded) {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Close send the unbind request and close the connection.
+func (c *Conn) Close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.msgID++
+	_, _ = c.conn.Write(NewConstructed(TagSequence,
+		NewInteger(TagInteger, c.msgID),
+		NewPacket(ClassApplication|ApplicationUnbindRequest, nil)).Bytes())
+	return c.conn.Close()
+}
+
+// request send the operation and read the responses until the response of the tag, the
+// final response is the first element of the result and the others follow it.
+// 送出請求並讀取回應，直到收到tag的回應為止，最後的回應放在回傳值的第一個
+func (c *Conn) request(op *Packet, tag byte) ([]*Packet, error) {
+	c.msgID++
+	if c.timeout > 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(c.timeout))
+		defer func() { _ = c.conn.SetDeadline(time.Time{}) }()
+	}
+
+	if _, err := c.conn.Write(NewConstructed(TagSequence, NewInteger(TagInteger, c.msgID), op).Bytes()); err != nil {
+		return nil, err
+	}
+
+	res := []*Packet{nil}
+	for {
+		msg, err := ReadPacket(c.reader)
+		if err != nil {
+			return nil, err
+		}
+		if len(msg.Children) < 2 || msg.Children[0].Int() != c.msgID {
+			// 忽略非此請求的訊息(例如Notice of Disconnection)
+			if len(msg.Children) >= 2 && msg.Children[0].Int() == 0 {
+				return nil, resultError(msg.Children[1])
+			}
+			continue
+		}
+		resOp := msg.Children[1]
+		if resOp.Tag&^(ClassApplication|TypeConstructed) == tag {
+			res[0] = resOp
+			return res, nil
+		}
+		res = append(res, resOp)
+	}
+}
+
+func resultError(op *Packet) error {
+	if op == nil || len(op.Children) < 3 {
+		return errMalformed
+	}
+	if code := op.Children[0].Int(); code != ResultSuccess {
+		return &Error{ResultCode: code, Message: op.Children[2].String()}
+	}
+	return nil
+}
+
+func parseEntry(op *Packet) *Entry {
+	entry := &Entry{Attributes: make(map[string][]string)}
+	if dn := op.Child(0); dn != nil {
+		entry.DN = dn.String()
+	}
+	if attrs := op.Child(1); attrs != nil {
+		for _, attr := range attrs.Children {
+			name := attr.Child(0)
+			if name == nil {
+				continue
+			}
+			var values []string
+			if vals := attr.Child(1); vals != nil {
+				for _, v := range vals.Children {
+					values = append(values, v.String())
+				}
+			}
+			entry.Attributes[name.String()] = values
+		}
+	}
+	return entry
+}
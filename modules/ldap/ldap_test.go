@@ -0,0 +1,84 @@
+package ldap_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/ldap"
+	"github.com/GoAdminGroup/go-admin/modules/ldap/ldaptest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacket(t *testing.T) {
+	for _, v := range []int64{0, 1, 127, 128, 255, 256, -1, -129, 1 << 40} {
+		p, err := ldap.ParsePacket(ldap.NewInteger(ldap.TagInteger, v).Bytes())
+		assert.Equal(t, err, nil)
+		assert.Equal(t, p.Int(), v)
+	}
+
+	long := make([]byte, 300)
+	p, err := ldap.ParsePacket(ldap.NewConstructed(ldap.TagSequence, ldap.NewPacket(ldap.TagOctetString, long)).Bytes())
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(p.Child(0).Value), 300)
+
+	_, err = ldap.ParsePacket([]byte{0x30, 0x05, 0x04})
+	assert.NotEqual(t, err, nil)
+}
+
+func TestCompileFilter(t *testing.T) {
+	assert.Equal(t, ldap.EscapeFilter("a*(b)\\"), "a\\2a\\28b\\29\\5c")
+
+	p, err := ldap.CompileFilter("(&(objectClass=person)(!(uid=a\\2ab))(cn=ad*in)(mail=*))")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(p.Children), 4)
+	assert.Equal(t, p.Child(1).Child(0).Child(1).String(), "a*b")
+	assert.Equal(t, len(p.Child(2).Child(1).Children), 2)
+	assert.Equal(t, p.Child(3).String(), "mail")
+
+	for _, filter := range []string{"", "(uid=a", "(=a)", "(&(uid=a)", "(uid>=a*)", "(uid=\\zz)"} {
+		_, err := ldap.CompileFilter(filter)
+		assert.NotEqual(t, err, nil, filter)
+	}
+}
+
+func TestConn(t *testing.T) {
+	server := ldaptest.NewServer(
+		ldaptest.Entry{DN: "cn=admin,dc=example,dc=com", Password: "secret"},
+		ldaptest.Entry{DN: "uid=jack,ou=people,dc=example,dc=com", Password: "jack", Attributes: map[string][]string{
+			"uid":      {"jack"},
+			"cn":       {"Jack"},
+			"memberOf": {"cn=admins,ou=groups,dc=example,dc=com", "cn=dev,ou=groups,dc=example,dc=com"},
+		}},
+	)
+	defer server.Close()
+
+	conn, err := ldap.Dial(server.URL(), time.Second, nil)
+	assert.Equal(t, err, nil)
+	defer func() { _ = conn.Close() }()
+
+	err = conn.Bind("cn=admin,dc=example,dc=com", "wrong")
+	assert.Equal(t, ldap.IsErrorWithCode(err, ldap.ResultInvalidCredentials), true)
+	err = conn.Bind("cn=admin,dc=example,dc=com", "")
+	assert.Equal(t, ldap.IsErrorWithCode(err, ldap.ResultInvalidCredentials), true)
+	assert.Equal(t, conn.Bind("cn=admin,dc=example,dc=com", "secret"), nil)
+
+	entries, err := conn.Search(ldap.SearchRequest{
+		BaseDN:     "dc=example,dc=com",
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     "(uid=" + ldap.EscapeFilter("jack") + ")",
+		Attributes: []string{"cn", "memberof"},
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].DN, "uid=jack,ou=people,dc=example,dc=com")
+	assert.Equal(t, entries[0].GetAttributeValue("CN"), "Jack")
+	assert.Equal(t, len(entries[0].GetAttributeValues("memberOf")), 2)
+	assert.Equal(t, entries[0].GetAttributeValue("uid"), "")
+
+	entries, err = conn.Search(ldap.SearchRequest{
+		BaseDN: "dc=example,dc=com",
+		Filter: "(uid=" + ldap.EscapeFilter("*") + ")",
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(entries), 0)
+}
@@ -0,0 +1,233 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package ldaptest provides an in-memory directory server for the tests of the ldap client
+// and the ldap authentication.
+// 測試用的記憶體目錄服務，支援simple bind及search
+package ldaptest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/GoAdminGroup/go-admin/modules/ldap"
+)
+
+// Entry is an entry of the directory, Password is used by the simple bind.
+type Entry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// Server is a directory server listening on a local port.
+type Server struct {
+	Entries []Entry
+
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewServer start a server with the entries.
+func NewServer(entries ...Entry) *Server {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	s := &Server{Entries: entries, listener: l}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// URL return the ldap url of the server.
+func (s *Server) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+// Close stop the server.
+func (s *Server) Close() {
+	_ = s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	var (
+		reader = bufio.NewReader(conn)
+		bound  = ""
+	)
+
+	for {
+		msg, err := ldap.ReadPacket(reader)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+		id, op := msg.Children[0].Int(), msg.Children[1]
+
+		switch op.Tag &^ (ldap.ClassApplication | ldap.TypeConstructed) {
+		case ldap.ApplicationBindRequest:
+			dn, password := op.Child(1).String(), op.Child(2).String()
+			code := ldap.ResultInvalidCredentials
+			if e := s.find(dn); e != nil && password != "" && e.Password == password {
+				code, bound = ldap.ResultSuccess, dn
+			}
+			s.write(conn, id, result(ldap.ApplicationBindResponse, code))
+		case ldap.ApplicationSearchRequest:
+			if bound == "" {
+				s.write(conn, id, result(ldap.ApplicationSearchResultDone, 50))
+				continue
+			}
+			base := strings.ToLower(op.Child(0).String())
+			for _, e := range s.Entries {
+				if !strings.HasSuffix(strings.ToLower(e.DN), base) || !match(op.Child(6), e) {
+					continue
+				}
+				s.write(conn, id, entryPacket(e, op.Child(7)))
+			}
+			s.write(conn, id, result(ldap.ApplicationSearchResultDone, ldap.ResultSuccess))
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			s.write(conn, id, result(ldap.ApplicationExtendedResponse, 2))
+		}
+	}
+}
+
+func (s *Server) find(dn string) *Entry {
+	for i := range s.Entries {
+		if strings.EqualFold(s.Entries[i].DN, dn) {
+			return &s.Entries[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) write(conn net.Conn, id int64, op *ldap.Packet) {
+	_, _ = conn.Write(ldap.NewConstructed(ldap.TagSequence, ldap.NewInteger(ldap.TagInteger, id), op).Bytes())
+}
+
+func result(tag byte, code int) *ldap.Packet {
+	return ldap.NewConstructed(ldap.ClassApplication|tag,
+		ldap.NewInteger(ldap.TagEnumerated, int64(code)),
+		ldap.NewString(ldap.TagOctetString, ""),
+		ldap.NewString(ldap.TagOctetString, ""))
+}
+
+func entryPacket(e Entry, attrs *ldap.Packet) *ldap.Packet {
+	list := ldap.NewConstructed(ldap.TagSequence)
+	for name, values := range e.Attributes {
+		if !wanted(attrs, name) {
+			continue
+		}
+		vals := ldap.NewConstructed(ldap.TagSet)
+		for _, v := range values {
+			vals.Append(ldap.NewString(ldap.TagOctetString, v))
+		}
+		list.Append(ldap.NewConstructed(ldap.TagSequence, ldap.NewString(ldap.TagOctetString, name), vals))
+	}
+	return ldap.NewConstructed(ldap.ClassApplication|ldap.ApplicationSearchResultEntry,
+		ldap.NewString(ldap.TagOctetString, e.DN), list)
+}
+
+func wanted(attrs *ldap.Packet, name string) bool {
+	if attrs == nil || len(attrs.Children) == 0 {
+		return true
+	}
+	for _, a := range attrs.Children {
+		if strings.EqualFold(a.String(), name) || a.String() == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func values(e Entry, name string) []string {
+	for k, v := range e.Attributes {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return nil
+}
+
+// match evaluate the filter with case insensitive comparison.
+func match(filter *ldap.Packet, e Entry) bool {
+	if filter == nil {
+		return false
+	}
+	switch filter.Tag &^ (ldap.ClassContext | ldap.TypeConstructed) {
+	case ldap.FilterAnd:
+		for _, f := range filter.Children {
+			if !match(f, e) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, f := range filter.Children {
+			if match(f, e) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !match(filter.Child(0), e)
+	case ldap.FilterPresent:
+		return len(values(e, filter.String())) > 0
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch:
+		for _, v := range values(e, filter.Child(0).String()) {
+			if strings.EqualFold(v, filter.Child(1).String()) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterSubstrings:
+		for _, v := range values(e, filter.Child(0).String()) {
+			if matchSubstrings(strings.ToLower(v), filter.Child(1)) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func matchSubstrings(v string, subs *ldap.Packet) bool {
+	for _, sub := range subs.Children {
+		s := strings.ToLower(sub.String())
+		switch sub.Tag &^ ldap.ClassContext {
+		case ldap.SubstringInitial:
+			if !strings.HasPrefix(v, s) {
+				return false
+			}
+			v = v[len(s):]
+		case ldap.SubstringAny:
+			i := strings.Index(v, s)
+			if i < 0 {
+				return false
+			}
+			v = v[i+len(s):]
+		case ldap.SubstringFinal:
+			if !strings.HasSuffix(v, s) {
+				return false
+			}
+		}
+	}
+	return true
+}
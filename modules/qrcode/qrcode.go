@@ -0,0 +1,461 @@
+// Copyright 2019 GoAdmin Core Team. All rights reserved.
+// Use of this source code is governed by a Apache-2.0 style
+// license that can be found in the LICENSE file.
+
+// Package qrcode is a minimal QR code encoder(byte mode, error correction level L,
+// version 1 to 10) which renders the code as svg, so that the content like the totp
+// secret will not be sent to a third party service.
+package qrcode
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrTooLong is returned when the text can not be encoded in version 10.
+var ErrTooLong = errors.New("qrcode: text too long")
+
+// 各版本(1~10)在錯誤修正等級L下的總碼字數、每個區塊的錯誤修正碼字數、區塊數
+var (
+	rawCodewords = [...]int{0, 26, 44, 70, 100, 134, 172, 196, 242, 292, 346}
+	eccPerBlock  = [...]int{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18}
+	numBlocks    = [...]int{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4}
+	alignments   = [...][]int{nil, nil, {6, 18}, {6, 22}, {6, 26}, {6, 30}, {6, 34},
+		{6, 22, 38}, {6, 24, 42}, {6, 26, 46}, {6, 28, 50}}
+)
+
+// Code is an encoded QR code.
+type Code struct {
+	Size    int
+	modules [][]bool
+	isFunc  [][]bool
+}
+
+// Encode encode the text to a QR code.
+// 將參數text以byte模式編碼，自動選擇最小的版本及最佳的遮罩
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+
+	version := 0
+	for v := 1; v <= 10; v++ {
+		countBits := 8
+		if v >= 10 {
+			countBits = 16
+		}
+		if 4+countBits+len(data)*8 <= dataCodewords(v)*8 {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	codewords := addEcc(version, encodeData(version, data))
+
+	size := version*4 + 17
+	c := &Code{Size: size, modules: newGrid(size), isFunc: newGrid(size)}
+	c.drawFunctionPatterns(version)
+	c.drawCodewords(codewords)
+
+	best, minPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); minPenalty < 0 || p < minPenalty {
+			best, minPenalty = mask, p
+		}
+		c.applyMask(mask)
+	}
+	c.applyMask(best)
+	c.drawFormatBits(best)
+
+	return c, nil
+}
+
+// Get return true if the module at x(column), y(row) is dark.
+func (c *Code) Get(x, y int) bool {
+	return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x]
+}
+
+// SVG render the code as a svg image, every module is scale pixels.
+// 將QR code輸出成svg(包含4個模組寬的空白邊界)
+func (c *Code) SVG(scale int) string {
+	const border = 4
+	full := (c.Size + border*2) * scale
+	var b strings.Builder
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" width="` + strconv.Itoa(full) + `" height="` +
+		strconv.Itoa(full) + `" viewBox="0 0 ` + strconv.Itoa(c.Size+border*2) + ` ` + strconv.Itoa(c.Size+border*2) +
+		`" shape-rendering="crispEdges"><rect width="100%" height="100%" fill="#ffffff"/><path fill="#000000" d="`)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				b.WriteString("M" + strconv.Itoa(x+border) + "," + strconv.Itoa(y+border) + "h1v1h-1z")
+			}
+		}
+	}
+	b.WriteString(`"/></svg>`)
+	return b.String()
+}
+
+func newGrid(size int) [][]bool {
+	grid := make([][]bool, size)
+	for i := range grid {
+		grid[i] = make([]bool, size)
+	}
+	return grid
+}
+
+func dataCodewords(version int) int {
+	return rawCodewords[version] - eccPerBlock[version]*numBlocks[version]
+}
+
+// 模式指示(0100)、字元數、資料、結束符及填充
+func encodeData(version int, data []byte) []byte {
+	var bits []bool
+	appendBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, (v>>uint(i))&1 == 1)
+		}
+	}
+
+	countBits := 8
+	if version >= 10 {
+		countBits = 16
+	}
+	appendBits(4, 4)
+	appendBits(len(data), countBits)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+
+	capacity := dataCodewords(version) * 8
+	for i := 0; i < 4 && len(bits) < capacity; i++ {
+		bits = append(bits, false)
+	}
+	for len(bits)%8 != 0 {
+		bits = append(bits, false)
+	}
+
+	result := make([]byte, 0, capacity/8)
+	for i := 0; i < len(bits); i += 8 {
+		var b byte
+		for j := 0; j < 8; j++ {
+			if bits[i+j] {
+				b |= 1 << uint(7-j)
+			}
+		}
+		result = append(result, b)
+	}
+	for pad := byte(0xEC); len(result) < capacity/8; pad ^= 0xEC ^ 0x11 {
+		result = append(result, pad)
+	}
+	return result
+}
+
+// 分割區塊、計算Reed-Solomon錯誤修正碼並交錯排列
+func addEcc(version int, data []byte) []byte {
+	blocks, ecc, raw := numBlocks[version], eccPerBlock[version], rawCodewords[version]
+	numShort := blocks - raw%blocks
+	shortLen := raw / blocks
+
+	divisor := rsDivisor(ecc)
+	all := make([][]byte, blocks)
+	k := 0
+	for i := 0; i < blocks; i++ {
+		n := shortLen - ecc
+		if i >= numShort {
+			n++
+		}
+		dat := append([]byte{}, data[k:k+n]...)
+		k += n
+		block := append(dat, rsRemainder(dat, divisor)...)
+		if i < numShort {
+			// 短區塊補上一個空位，方便交錯排列
+			block = append(block[:n], append([]byte{0}, block[n:]...)...)
+		}
+		all[i] = block
+	}
+
+	result := make([]byte, 0, raw)
+	for i := 0; i < len(all[0]); i++ {
+		for j := range all {
+			if i != shortLen-ecc || j >= numShort {
+				result = append(result, all[j][i])
+			}
+		}
+	}
+	return result
+}
+
+func rsDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			result[j] = gfMul(result[j], root)
+			if j+1 < degree {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return result
+}
+
+func rsRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= gfMul(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+// GF(2^8)乘法，既約多項式為0x11D
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+func (c *Code) setFunc(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunc[y][x] = true
+}
+
+// 繪製定位圖形、時序圖形、對齊圖形、版本資訊以及保留格式資訊區域
+func (c *Code) drawFunctionPatterns(version int) {
+	for i := 0; i < c.Size; i++ {
+		c.setFunc(6, i, i%2 == 0)
+		c.setFunc(i, 6, i%2 == 0)
+	}
+
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	pos := alignments[version]
+	for i := range pos {
+		for j := range pos {
+			if (i == 0 && j == 0) || (i == 0 && j == len(pos)-1) || (i == len(pos)-1 && j == 0) {
+				continue
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.setFunc(pos[i]+dx, pos[j]+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	c.drawFormatBits(0)
+
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>uint(i))&1 == 1
+			a, b := c.Size-11+i%3, i/3
+			c.setFunc(a, b, dark)
+			c.setFunc(b, a, dark)
+		}
+	}
+}
+
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < c.Size && yy >= 0 && yy < c.Size {
+				dist := max(abs(dx), abs(dy))
+				c.setFunc(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+// 格式資訊：錯誤修正等級L(01)及遮罩，BCH編碼後與0x5412做XOR
+func (c *Code) drawFormatBits(mask int) {
+	data := 1<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>uint(i))&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunc(8, i, bit(i))
+	}
+	c.setFunc(8, 7, bit(6))
+	c.setFunc(8, 8, bit(7))
+	c.setFunc(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunc(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunc(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunc(8, c.Size-15+i, bit(i))
+	}
+	c.setFunc(8, c.Size-8, true)
+}
+
+// 由右下角開始以兩欄為一組、上下交替放置資料
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.isFunc[y][x] && i < len(data)*8 {
+					c.modules[y][x] = (data[i>>3]>>uint(7-i&7))&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// 對非功能區域套用遮罩，套用兩次即還原
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !c.isFunc[y][x] {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// 計算遮罩的扣分(連續同色、2x2同色、類定位圖形、明暗比例)
+func (c *Code) penalty() int {
+	result := 0
+	dark := 0
+	for a := 0; a < c.Size; a++ {
+		runRow, runCol := 1, 1
+		for b := 0; b < c.Size; b++ {
+			if c.modules[a][b] {
+				dark++
+			}
+			if b > 0 {
+				if c.modules[a][b] == c.modules[a][b-1] {
+					runRow++
+				} else {
+					runRow = 1
+				}
+				if c.modules[b][a] == c.modules[b-1][a] {
+					runCol++
+				} else {
+					runCol = 1
+				}
+				if runRow == 5 {
+					result += 3
+				} else if runRow > 5 {
+					result++
+				}
+				if runCol == 5 {
+					result += 3
+				} else if runCol > 5 {
+					result++
+				}
+			}
+			if a > 0 && b > 0 {
+				v := c.modules[a][b]
+				if v == c.modules[a-1][b] && v == c.modules[a][b-1] && v == c.modules[a-1][b-1] {
+					result += 3
+				}
+			}
+			if b+11 <= c.Size {
+				if c.finderLike(func(i int) bool { return c.modules[a][b+i] }) {
+					result += 40
+				}
+				if c.finderLike(func(i int) bool { return c.modules[b+i][a] }) {
+					result += 40
+				}
+			}
+		}
+	}
+	total := c.Size * c.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	if k > 0 {
+		result += k * 10
+	}
+	return result
+}
+
+var finderPatterns = [2][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+func (c *Code) finderLike(get func(i int) bool) bool {
+	for _, p := range finderPatterns {
+		match := true
+		for i, v := range p {
+			if get(i) != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
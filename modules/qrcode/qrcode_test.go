@@ -0,0 +1,61 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// "HELLO WORLD" 1-M
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	assert.Equal(t, rsRemainder(data, rsDivisor(10)), []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23})
+}
+
+func TestFormatBits(t *testing.T) {
+	c := &Code{Size: 21, modules: newGrid(21), isFunc: newGrid(21)}
+	c.drawFormatBits(0)
+
+	// level L, mask 0: 111011111000100
+	bits := ""
+	for x := 0; x <= 7; x++ {
+		if x == 6 {
+			continue
+		}
+		if c.Get(x, 8) {
+			bits += "1"
+		} else {
+			bits += "0"
+		}
+	}
+	for y := 8; y >= 0; y-- {
+		if y == 6 {
+			continue
+		}
+		if c.Get(8, y) {
+			bits += "1"
+		} else {
+			bits += "0"
+		}
+	}
+	assert.Equal(t, bits, "111011111000100")
+}
+
+func TestEncode(t *testing.T) {
+	c, err := Encode("otpauth://totp/GoAdmin:admin?secret=GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ&issuer=GoAdmin")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, c.Size, 37)
+
+	// finder patterns
+	for _, p := range [][2]int{{0, 0}, {c.Size - 7, 0}, {0, c.Size - 7}} {
+		assert.Equal(t, c.Get(p[0], p[1]), true)
+		assert.Equal(t, c.Get(p[0]+1, p[1]+1), false)
+		assert.Equal(t, c.Get(p[0]+3, p[1]+3), true)
+	}
+
+	assert.Equal(t, strings.HasPrefix(c.SVG(4), "<svg"), true)
+
+	_, err = Encode(strings.Repeat("a", 300))
+	assert.Equal(t, err, ErrTooLong)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/NebulousLabs/fastrand"
 	"html/template"
 	"math"
+	"net"
 	"net/url"
 	"os"
 	"regexp"
@@ -101,6 +102,46 @@ func JSON(a interface{}) string {
 	return string(b)
 }
 
+// ParseIPNets parse the list of the CIDRs, a single ip is treated as the network of itself.
+// 解析CIDR列表，單一ip(例如192.168.1.1)視為只包含該ip的網段
+func ParseIPNets(list []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(list))
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid ip: %s", item)
+			}
+			bits := net.IPv6len * 8
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, net.IPv4len*8
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// ContainsIP check the ip is in any of the networks.
+func ContainsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 func ParseBool(s string) bool {
 	b1, _ := strconv.ParseBool(s)
 	return b1
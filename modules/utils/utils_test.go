@@ -3,6 +3,7 @@ package utils
 import (
 	"github.com/stretchr/testify/assert"
 	"html/template"
+	"net"
 	"testing"
 )
 
@@ -48,3 +49,18 @@ func TestCompareVersion(t *testing.T) {
 	assert.Equal(t, true, CompareVersion("=v1.2.4", "v1.2.4"))
 	assert.Equal(t, true, CompareVersion("= v1.2.4", "v1.2.4"))
 }
+
+func TestParseIPNets(t *testing.T) {
+	nets, err := ParseIPNets([]string{"10.0.0.0/8", " 192.168.1.5 ", "", "2001:db8::/32"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, len(nets))
+	assert.Equal(t, true, ContainsIP(nets, net.ParseIP("10.1.2.3")))
+	assert.Equal(t, true, ContainsIP(nets, net.ParseIP("192.168.1.5")))
+	assert.Equal(t, false, ContainsIP(nets, net.ParseIP("192.168.1.6")))
+	assert.Equal(t, true, ContainsIP(nets, net.ParseIP("2001:db8::1")))
+
+	_, err = ParseIPNets([]string{"10.0.0.0/33"})
+	assert.NotEqual(t, nil, err)
+	_, err = ParseIPNets([]string{"office"})
+	assert.NotEqual(t, nil, err)
+}
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/service"
 	"github.com/GoAdminGroup/go-admin/plugins"
@@ -43,19 +44,30 @@ func (admin *Admin) InitPlugin(services service.List) {
 	// GeneratorList類別為map[string]Generator，Generator類別為func(ctx *context.Context) Table
 	// Combine透過參數判斷GeneratorList已經有該key、value，如果不存在則加入該鍵與值
 	admin.tableList.Combine(table.GeneratorList{
-		"manager":        st.GetManagerTable,
-		"permission":     st.GetPermissionTable,
-		"roles":          st.GetRolesTable,
-		"op":             st.GetOpTable,
-		"menu":           st.GetMenuTable,
-		"normal_manager": st.GetNormalManagerTable,
-		"site":           st.GetSiteTable,
-		"generate":       st.GetGenerateForm,
+		"manager":         st.GetManagerTable,
+		"permission":      st.GetPermissionTable,
+		"roles":           st.GetRolesTable,
+		"op":              st.GetOpTable,
+		"audit_log":       st.GetAuditLogTable,
+		"change_requests": st.GetChangeRequestTable,
+		"login_lockout":   st.GetLoginLockoutTable,
+		"session":         st.GetSessionTable,
+		"api_keys":        st.GetAPIKeyTable,
+		"row_scopes":      st.GetRowScopeTable,
+		"menu":            st.GetMenuTable,
+		"normal_manager":  st.GetNormalManagerTable,
+		"site":            st.GetSiteTable,
+		"generate":        st.GetGenerateForm,
 	})
 
 	// 將參數admin.Services, admin.Conn, admin.tableList設置Admin.guardian(struct)後回傳
 	admin.guardian = guard.New(admin.Services, admin.Conn, admin.tableList, admin.UI.NavButtons)
 
+	// 設置csrf_token_persist時，csrf token與session使用相同的儲存驅動
+	if c.CSRFTokenPersist {
+		auth.GetTokenService(services.Get(auth.TokenServiceKey)).UseDriver(auth.GetSessionDriver(admin.Conn))
+	}
+
 	// 將參數設置至Config(struct)
 	handlerCfg := controller.Config{
 		Config:     c,
@@ -74,6 +86,9 @@ func (admin *Admin) InitPlugin(services service.List) {
 
 	table.SetServices(services)
 
+	// 定時永久刪除回收站中超過保留期限的資料
+	table.StartTrashPurge(admin.tableList)
+
 	action.InitOperationHandlerSetter(admin.GetAddOperationFn())
 }
 
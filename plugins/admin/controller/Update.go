@@ -2,8 +2,10 @@ package controller
 
 import (
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 )
 
 // Update update the table row of given id.
@@ -13,6 +15,11 @@ func (h *Handler) Update(ctx *context.Context) {
 
 	err := param.Panel.UpdateData(param.Value)
 
+	if err == table.ErrChangePending {
+		response.OkWithMsg(ctx, language.Get(changePendingMsg))
+		return
+	}
+
 	if err != nil {
 		response.Error(ctx, err.Error())
 		return
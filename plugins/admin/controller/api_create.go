@@ -1,16 +1,29 @@
 package controller
 
 import (
+	"errors"
+	"mime/multipart"
+
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/file"
+	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template/types"
 )
 
 func (h *Handler) ApiCreate(ctx *context.Context) {
 	param := guard.GetNewFormParam(ctx)
 
+	// 檢查必填欄位
+	if errs := checkMustFields(param.Panel.GetForm().FieldList, param.Value(), param.MultiForm.File, true); errs.HasError() {
+		response.ValidationError(ctx, "validation failed", errs)
+		return
+	}
+
 	if len(param.MultiForm.File) > 0 {
 		err := file.GetFileEngine(h.config.FileUploadEngine.Name).Upload(param.MultiForm)
 		if err != nil {
@@ -20,14 +33,67 @@ func (h *Handler) ApiCreate(ctx *context.Context) {
 	}
 
 	err := param.Panel.InsertData(param.Value())
+	if err == table.ErrChangePending {
+		response.OkWithMsg(ctx, language.Get(changePendingMsg))
+		return
+	}
 	if err != nil {
-		response.Error(ctx, err.Error())
+		apiDataError(ctx, err)
 		return
 	}
 
 	response.Ok(ctx)
 }
 
+// checkMustFields check if the must fields of the form are filled.
+// 新增時檢查所有必填(且允許新增、未隱藏)的欄位，編輯時只檢查有送出且可編輯的必填欄位
+func checkMustFields(fields types.FormFields, values form2.Values, files map[string][]*multipart.FileHeader, isNew bool) form2.FieldErrors {
+	errs := make(form2.FieldErrors)
+	for _, field := range fields {
+		if !field.Must || field.Hide {
+			continue
+		}
+		if isNew && field.NotAllowAdd {
+			continue
+		}
+		if field.FormType.IsFile() {
+			if isNew && len(files[field.Field]) == 0 {
+				errs.Add(field.Field, "is required")
+			}
+			continue
+		}
+		value, submitted := values[field.Field]
+		if multi, ok := values[field.Field+"[]"]; ok {
+			value, submitted = multi, true
+		}
+		if !isNew && (!submitted || !field.Editable) {
+			continue
+		}
+		empty := true
+		for _, v := range value {
+			if v != "" {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			errs.Add(field.Field, "is required")
+		}
+	}
+	return errs
+}
+
+// apiDataError response the error of inserting or updating the data.
+// 如果錯誤為form.FieldErrors則回傳各欄位的錯誤訊息
+func apiDataError(ctx *context.Context, err error) {
+	var errs form2.FieldErrors
+	if errors.As(err, &errs) {
+		response.ValidationError(ctx, "validation failed", errs)
+		return
+	}
+	response.Error(ctx, err.Error())
+}
+
 func (h *Handler) ApiCreateForm(ctx *context.Context) {
 	params := guard.GetShowNewFormParam(ctx)
 
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"mime/multipart"
+	"testing"
+
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestCheckMustFields(t *testing.T) {
+	fields := types.FormFields{
+		{Field: "name", FormType: form.Text, Must: true, Editable: true},
+		{Field: "tags", FormType: form.Select, Must: true, Editable: true},
+		{Field: "avatar", FormType: form.File, Must: true, Editable: true},
+		{Field: "code", FormType: form.Text, Must: true, NotAllowAdd: true},
+		{Field: "secret", FormType: form.Text, Must: true, Hide: true},
+		{Field: "remark", FormType: form.Text},
+	}
+
+	// all the must fields are required when inserting
+	errs := checkMustFields(fields, form2.Values{}, nil, true)
+	assert.Equal(t, errs, form2.FieldErrors{
+		"name":   "is required",
+		"tags":   "is required",
+		"avatar": "is required",
+	})
+
+	// the empty values and the empty multiple values are missing
+	errs = checkMustFields(fields, form2.Values{
+		"name":   {""},
+		"tags[]": {"", ""},
+	}, nil, true)
+	assert.Equal(t, errs, form2.FieldErrors{
+		"name":   "is required",
+		"tags":   "is required",
+		"avatar": "is required",
+	})
+
+	errs = checkMustFields(fields, form2.Values{
+		"name":   {"jack"},
+		"tags[]": {"", "a"},
+	}, map[string][]*multipart.FileHeader{"avatar": {{Filename: "a.png"}}}, true)
+	assert.Equal(t, errs.HasError(), false)
+
+	// only the submitted and editable fields are checked when updating
+	errs = checkMustFields(fields, form2.Values{}, nil, false)
+	assert.Equal(t, errs.HasError(), false)
+	errs = checkMustFields(fields, form2.Values{"name": {""}, "code": {""}, "tags": {"a"}}, nil, false)
+	assert.Equal(t, errs, form2.FieldErrors{"name": "is required"})
+}
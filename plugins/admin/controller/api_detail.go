@@ -7,6 +7,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
 	"github.com/GoAdminGroup/go-admin/template/types"
@@ -16,6 +17,11 @@ import (
 func (h *Handler) ApiDetail(ctx *context.Context) {
 	prefix := ctx.Query(constant.PrefixKey)
 	id := ctx.Query(constant.DetailPKKey)
+	if id == "" {
+		response.ValidationError(ctx, "validation failed",
+			form2.FieldErrors{constant.DetailPKKey: "is required"})
+		return
+	}
 	panel := h.table(prefix, ctx)
 	user := auth.Auth(ctx)
 
@@ -122,8 +128,20 @@ $('.delete-btn').on('click', function (event) {
 		return
 	}
 
+	// 將欄位值轉換為map[欄位]值
+	data := make(map[string]string)
+	for _, field := range formInfo.FieldList {
+		data[field.Field] = string(field.Value)
+	}
+	for _, group := range formInfo.GroupFieldList {
+		for _, field := range group {
+			data[field.Field] = string(field.Value)
+		}
+	}
+
 	response.OkWithData(ctx, map[string]interface{}{
 		"panel":    formInfo,
+		"data":     data,
 		"previous": infoUrl,
 		"footer":   deleteJs,
 		"prefix":   h.config.PrefixFixSlash(),
@@ -1,10 +1,15 @@
 package controller
 
 import (
+	"strconv"
+	"strings"
+
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 )
 
 func (h *Handler) ApiList(ctx *context.Context) {
@@ -12,6 +17,12 @@ func (h *Handler) ApiList(ctx *context.Context) {
 
 	panel := h.table(prefix, ctx)
 
+	// 檢查分頁及排序參數，錯誤時回傳各欄位的錯誤訊息
+	if errs := checkListParam(ctx, panel); errs.HasError() {
+		response.ValidationError(ctx, "validation failed", errs)
+		return
+	}
+
 	params := parameter.GetParam(ctx.Request.URL, panel.GetInfo().DefaultPageSize, panel.GetInfo().SortField,
 		panel.GetInfo().GetSort())
 
@@ -21,11 +32,43 @@ func (h *Handler) ApiList(ctx *context.Context) {
 		return
 	}
 
+	// 將每一筆資料轉換為map[欄位]值
+	data := make([]map[string]string, len(panelInfo.InfoList))
+	for i, info := range panelInfo.InfoList {
+		data[i] = make(map[string]string, len(info))
+		for field, item := range info {
+			data[i][field] = item.Value
+		}
+	}
+
+	// 篩選條件(去除__開頭的系統參數)
+	filters := make(map[string][]string)
+	for key, value := range params.Fields {
+		if !strings.HasPrefix(key, "__") {
+			filters[key] = value
+		}
+	}
+
+	totalPage := 0
+	if params.PageSizeInt > 0 {
+		totalPage = (panelInfo.Total + params.PageSizeInt - 1) / params.PageSizeInt
+	}
+
 	response.OkWithData(ctx, map[string]interface{}{
 		"panel":  panelInfo,
+		"data":   data,
 		"footer": panelInfo.Paginator.GetContent() + panel.GetInfo().FooterHtml,
 		"header": aDataTable().GetDataTableHeader() + panel.GetInfo().HeaderHtml,
 		"prefix": h.config.PrefixFixSlash(),
+		"paginator": map[string]interface{}{
+			"page":       params.PageInt,
+			"page_size":  params.PageSizeInt,
+			"total":      panelInfo.Total,
+			"total_page": totalPage,
+			"sort":       params.SortField,
+			"sort_type":  params.SortType,
+		},
+		"filters": filters,
 		"urls": map[string]string{
 			"edit":   urls[0],
 			"new":    urls[1],
@@ -37,3 +80,28 @@ func (h *Handler) ApiList(ctx *context.Context) {
 		},
 	})
 }
+
+// checkListParam check the pagination and sort parameters of the api list request.
+// 檢查__page、__pageSize是否為正整數，__sort_type是否為asc或desc，__sort是否為存在的欄位
+func checkListParam(ctx *context.Context, panel table.Table) form.FieldErrors {
+	errs := make(form.FieldErrors)
+
+	for _, key := range []string{parameter.Page, parameter.PageSize} {
+		if value := ctx.Query(key); value != "" {
+			if n, err := strconv.Atoi(value); err != nil || n < 1 {
+				errs.Add(key, "must be a positive integer")
+			}
+		}
+	}
+
+	if sortType := ctx.Query(parameter.SortType); sortType != "" && sortType != "asc" && sortType != "desc" {
+		errs.Add(parameter.SortType, "must be asc or desc")
+	}
+
+	if sort := ctx.Query(parameter.Sort); sort != "" && sort != panel.GetPrimaryKey().Name &&
+		!panel.GetInfo().FieldList.GetFieldByFieldName(sort).Exist() {
+		errs.Add(parameter.Sort, "unknown field")
+	}
+
+	return errs
+}
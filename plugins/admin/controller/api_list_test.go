@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/magiconair/properties/assert"
+)
+
+func TestCheckListParam(t *testing.T) {
+	panel := table.NewDefaultTable(table.DefaultConfig())
+	panel.GetInfo().AddField("Name", "name", db.Varchar)
+
+	check := func(query string) form.FieldErrors {
+		return checkListParam(context.NewContext(httptest.NewRequest("GET", "/admin/api/list/users?"+query, nil)), panel)
+	}
+
+	assert.Equal(t, check("").HasError(), false)
+	assert.Equal(t, check("__page=2&__pageSize=100&__sort=name&__sort_type=asc").HasError(), false)
+	assert.Equal(t, check("__sort=id&__sort_type=desc").HasError(), false)
+
+	// the page and the page size must be positive integers
+	assert.Equal(t, check("__page=0&__pageSize=-1"), form.FieldErrors{
+		"__page":     "must be a positive integer",
+		"__pageSize": "must be a positive integer",
+	})
+	assert.Equal(t, check("__page=a&__pageSize=1.5"), form.FieldErrors{
+		"__page":     "must be a positive integer",
+		"__pageSize": "must be a positive integer",
+	})
+
+	assert.Equal(t, check("__sort=password&__sort_type=up"), form.FieldErrors{
+		"__sort":      "unknown field",
+		"__sort_type": "must be asc or desc",
+	})
+}
@@ -4,10 +4,13 @@ import (
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/file"
+	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 	"github.com/GoAdminGroup/go-admin/template/types/form"
 	"net/url"
 )
@@ -15,6 +18,12 @@ import (
 func (h *Handler) ApiUpdate(ctx *context.Context) {
 	param := guard.GetEditFormParam(ctx)
 
+	// 檢查必填欄位
+	if errs := checkMustFields(param.Panel.GetForm().FieldList, param.Value(), param.MultiForm.File, false); errs.HasError() {
+		response.ValidationError(ctx, "validation failed", errs)
+		return
+	}
+
 	if len(param.MultiForm.File) > 0 {
 		err := file.GetFileEngine(h.config.FileUploadEngine.Name).Upload(param.MultiForm)
 		if err != nil {
@@ -26,14 +35,18 @@ func (h *Handler) ApiUpdate(ctx *context.Context) {
 	for _, field := range param.Panel.GetForm().FieldList {
 		if field.FormType == form.File &&
 			len(param.MultiForm.File[field.Field]) == 0 &&
-			param.MultiForm.Value[field.Field+"__delete_flag"][0] != "1" {
+			form2.Values(param.MultiForm.Value).Get(field.Field+"__delete_flag") != "1" {
 			delete(param.MultiForm.Value, field.Field)
 		}
 	}
 
 	err := param.Panel.UpdateData(param.Value())
+	if err == table.ErrChangePending {
+		response.OkWithMsg(ctx, language.Get(changePendingMsg))
+		return
+	}
 	if err != nil {
-		response.Error(ctx, err.Error())
+		apiDataError(ctx, err)
 		return
 	}
 
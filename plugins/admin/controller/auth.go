@@ -31,8 +31,20 @@ func (h *Handler) Auth(ctx *context.Context) {
 		// ServiceKey = auth
 		// 判斷是否有取得符合參數(auth.ServiceKey)的Service
 		s, exist = h.services.GetOrNot(auth.ServiceKey)
+		username = ctx.FormValue("username")
 	)
 
+	// 只允許單一登入(SSO)時不接受帳號密碼登入
+	if h.config.SSOOnly {
+		response.BadRequest(ctx, "password login is disabled")
+		return
+	}
+
+	// 帳號或ip登入失敗次數過多時，需等待或已被鎖定
+	if !h.checkLoginLockout(ctx, username) {
+		return
+	}
+
 	// 取得Handler.captchaConfig(map[string]string)["driver"]的值
 	if capDriver, ok := h.captchaConfig["driver"]; ok {
 		// Get在plugins\admin\modules\captcha\captcha.go
@@ -42,6 +54,7 @@ func (h *Handler) Auth(ctx *context.Context) {
 		// 驗證token
 		if ok {
 			if !capt.Validate(ctx.FormValue("token")) {
+				auth.LogLoginFailure(ctx, username, auth.LoginFailWrongCaptcha, h.conn)
 				response.BadRequest(ctx, "wrong captcha")
 				return
 			}
@@ -51,7 +64,6 @@ func (h *Handler) Auth(ctx *context.Context) {
 	// 藉由參數key取得url的參數值
 	if !exist {
 		password := ctx.FormValue("password")
-		username := ctx.FormValue("username")
 
 		if password == "" || username == "" {
 			response.BadRequest(ctx, "wrong password or username")
@@ -65,10 +77,36 @@ func (h *Handler) Auth(ctx *context.Context) {
 	}
 
 	if !ok {
+		auth.RecordLoginFailure(ctx, username, auth.LoginFailWrongPassword, h.conn)
 		response.BadRequest(ctx, errMsg)
 		return
 	}
 
+	// 已開啟兩步驟驗證或角色要求兩步驟驗證時，先不登入，跳轉至輸入驗證碼的頁面
+	// 失敗次數在驗證碼正確後才清除，避免以正確的密碼重置次數後繼續猜測驗證碼
+	if auth.TOTPRequired(user, h.conn) || models.UserTOTP().SetConn(h.conn).FindByUserId(user.Id).Enabled {
+		if err := auth.SetTOTPPending(ctx, user, h.conn); err != nil {
+			response.Error(ctx, err.Error())
+			return
+		}
+		totpUrl := h.config.Url("/login/totp")
+		if ref := refFromReferer(ctx); ref != "" {
+			totpUrl += "?ref=" + url.QueryEscape(ref)
+		}
+		response.OkWithData(ctx, map[string]interface{}{
+			"url": totpUrl,
+		})
+		return
+	}
+
+	auth.ResetLoginFailures(username, h.conn)
+
+	// 密碼超過有效天數時先不登入，跳轉至修改密碼的頁面
+	if h.passwordExpired(user) {
+		h.pendingPasswordChange(ctx, user, nil)
+		return
+	}
+
 	// 設置cookie(struct)並儲存在response header Set-Cookie中
 	err := auth.SetCookie(ctx, user, h.conn)
 
@@ -77,26 +115,111 @@ func (h *Handler) Auth(ctx *context.Context) {
 		return
 	}
 
-	// 藉由參數Referer獲得Header
+	// 成功，回傳code:200 and msg:ok and data
+	// 登入頁面有帶入ref時跳轉至ref
+	response.OkWithData(ctx, map[string]interface{}{
+		"url": h.loginRedirectURL(ctx),
+	})
+	return
+
+}
+
+// 帳號或ip被鎖定(或需要等待)時回傳429並記錄至操作紀錄，此次嘗試不會增加失敗次數
+func (h *Handler) checkLoginLockout(ctx *context.Context, username string) bool {
+	wait, reason := auth.CheckLoginLockout(username, auth.ClientIP(ctx), h.conn)
+	if wait <= 0 {
+		return true
+	}
+	auth.LogLoginFailure(ctx, username, reason, h.conn)
+	response.TooManyRequests(ctx, "too many login attempts, please try again later", wait)
+	return false
+}
+
+// 藉由參數Referer獲得Header，回傳其中的ref參數
+func refFromReferer(ctx *context.Context) string {
 	if ref := ctx.Headers("Referer"); ref != "" {
 		if u, err := url.Parse(ref); err == nil {
-			v := u.Query()
-			if r := v.Get("ref"); r != "" {
+			if r := u.Query().Get("ref"); r != "" {
 				rr, _ := url.QueryUnescape(r)
-				response.OkWithData(ctx, map[string]interface{}{
-					"url": rr,
-				})
-				return
+				return rr
 			}
 		}
 	}
+	return ""
+}
+
+// ApiToken check the input password and username and return a bearer token.
+// 身分驗證username、password後回傳token，之後的api請求可以在header中帶入Authorization: Bearer token
+func (h *Handler) ApiToken(ctx *context.Context) {
+
+	var (
+		user   models.UserModel
+		ok     bool
+		errMsg = "fail"
+		// ServiceKey = auth
+		s, exist = h.services.GetOrNot(auth.ServiceKey)
+		username = ctx.FormValue("username")
+	)
+
+	if h.config.SSOOnly {
+		response.BadRequest(ctx, "password login is disabled")
+		return
+	}
+
+	if !h.checkLoginLockout(ctx, username) {
+		return
+	}
+
+	if !exist {
+		password := ctx.FormValue("password")
+
+		if password == "" || username == "" {
+			response.BadRequest(ctx, "wrong password or username")
+			return
+		}
+		user, ok = auth.Check(password, username, h.conn)
+	} else {
+		user, ok, errMsg = auth.GetService(s).P(ctx)
+	}
+
+	if !ok {
+		auth.RecordLoginFailure(ctx, username, auth.LoginFailWrongPassword, h.conn)
+		response.BadRequest(ctx, errMsg)
+		return
+	}
+
+	// 開啟兩步驟驗證的用戶需要帶入totp_code，角色要求但尚未綁定的用戶需先從頁面登入綁定
+	if models.UserTOTP().SetConn(h.conn).FindByUserId(user.Id).Enabled {
+		if !auth.CheckTOTP(user, ctx.FormValue("totp_code"), h.conn) {
+			auth.RecordLoginFailure(ctx, username, auth.LoginFailWrongCode, h.conn)
+			response.BadRequest(ctx, "wrong code")
+			return
+		}
+	} else if auth.TOTPRequired(user, h.conn) {
+		response.BadRequest(ctx, "two factor authentication is required")
+		return
+	}
+
+	auth.ResetLoginFailures(username, h.conn)
+
+	// 密碼過期的用戶需先從頁面登入修改密碼
+	if h.passwordExpired(user) {
+		response.BadRequest(ctx, "password expired, please set a new password")
+		return
+	}
+
+	token, err := auth.IssueToken(user.Id, h.conn)
+
+	if err != nil {
+		response.Error(ctx, err.Error())
+		return
+	}
 
-	// 成功，回傳code:200 and msg:ok and data
 	response.OkWithData(ctx, map[string]interface{}{
-		"url": h.config.GetIndexURL(),
+		"token":      token,
+		"token_type": "Bearer",
+		"expires_in": h.config.AuthTokenLifeTime,
 	})
-	return
-
 }
 
 // Logout delete the cookie.
@@ -126,11 +249,13 @@ func (h *Handler) ShowLogin(ctx *context.Context) {
 	// ExecuteTemplate為html/template套件
 	// 將第三個參數data寫入buf(struct)後輸出HTML
 	if err := tmpl.ExecuteTemplate(buf, name, struct {
-		UrlPrefix string
-		Title     string
-		Logo      template2.HTML
-		CdnUrl    string
-		System    types.SystemInfo
+		UrlPrefix    string
+		Title        string
+		Logo         template2.HTML
+		CdnUrl       string
+		System       types.SystemInfo
+		SSOOnly      bool
+		SSOProviders []ssoButton
 	}{
 		UrlPrefix: h.config.AssertPrefix(),
 		Title:     h.config.LoginTitle,
@@ -138,7 +263,9 @@ func (h *Handler) ShowLogin(ctx *context.Context) {
 		System: types.SystemInfo{
 			Version: system.Version(),
 		},
-		CdnUrl: h.config.AssetUrl,
+		CdnUrl:       h.config.AssetUrl,
+		SSOOnly:      h.config.SSOOnly,
+		SSOProviders: h.ssoButtons(ctx.Query("ref")),
 	}); err == nil {
 		ctx.HTML(http.StatusOK, buf.String())
 	} else {
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"fmt"
+	template2 "html/template"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+	"github.com/GoAdminGroup/go-admin/template/icon"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+const changePendingMsg = "the change is submitted and waiting for approval"
+
+// 資料表需要審核時，新增、編輯頁面顯示的提示
+func changePendingAlert() template2.HTML {
+	return aAlert().SetTitle(icon.Icon(icon.Info, 2) + template2.HTML(language.Get("notice"))).
+		SetTheme("info").
+		SetContent(template2.HTML(language.Get(changePendingMsg))).
+		GetContent()
+}
+
+// ShowChangeRequest show the diff of the change request, the approver can approve or reject it
+// with a comment.
+// 顯示變更申請的內容及差異，可審核的用戶可以填寫意見後核准或駁回
+func (h *Handler) ShowChangeRequest(ctx *context.Context) {
+	user := auth.Auth(ctx)
+	change := models.ChangeRequest().SetConn(h.conn).Find(ctx.Query("id"))
+
+	// 遮蔽資料表設置的敏感欄位
+	if gen, ok := h.generators[change.Prefix]; ok && !change.IsEmpty() {
+		if redactor, ok := gen(ctx).(table.AuditRedactor); ok {
+			change = change.Redact(redactor.GetAuditRedact()...)
+		}
+	}
+
+	if change.IsEmpty() {
+		h.HTML(ctx, user, types.Panel{
+			Content:     aAlert().Warning("change request not found"),
+			Title:       template2.HTML(language.Get("change request")),
+			Description: template2.HTML(language.Get("change request")),
+		})
+		return
+	}
+
+	info := []map[string]types.InfoItem{
+		changeInfoItem("audit table", change.TargetTable),
+		changeInfoItem("record id", change.RecordId),
+		changeInfoItem("operation", language.Get(change.Operation)),
+		changeInfoItem("applicant", h.changeUserName(change.UserId)),
+		changeInfoItem("createdAt", change.CreatedAt),
+		changeInfoItem("status", language.Get(change.Status)),
+	}
+	if !change.IsPending() {
+		info = append(info,
+			changeInfoItem("reviewer", h.changeUserName(change.ReviewerId)),
+			changeInfoItem("reviewed at", change.ReviewedAt),
+			changeInfoItem("comment", change.Comment))
+	}
+
+	// 非data-table的表格以標題作為欄位的key
+	var (
+		fieldHead = language.Get("field")
+		oldHead   = language.Get("old values")
+		newHead   = language.Get("new values")
+		diff      = make([]map[string]types.InfoItem, 0)
+	)
+	for _, item := range change.Diff() {
+		diff = append(diff, map[string]types.InfoItem{
+			fieldHead: {Content: template2.HTML(template2.HTMLEscapeString(item.Field))},
+			oldHead:   {Content: template2.HTML(template2.HTMLEscapeString(changeValueString(item.Old)))},
+			newHead:   {Content: template2.HTML(template2.HTMLEscapeString(changeValueString(item.New)))},
+		})
+	}
+
+	body := stripedTable(info) + aTable().
+		SetStyle("bordered").
+		SetMinWidth("0.01%").
+		SetThead(types.Thead{
+			types.TheadItem{Head: fieldHead, Width: "20%"},
+			types.TheadItem{Head: oldHead, Width: "40%"},
+			types.TheadItem{Head: newHead},
+		}).
+		SetInfoList(diff).GetContent()
+
+	if change.CanReview(user) && h.generators[change.Prefix] != nil {
+		body += template2.HTML(`<div class="form-group" style="margin-top: 15px;">
+<textarea class="form-control" id="change-comment" rows="3" placeholder="` + language.Get("comment") + `"></textarea></div>
+<button class="btn btn-primary" onclick="changeReview('approve')">` + language.Get("approve") + `</button>
+<button class="btn btn-danger" onclick="changeReview('reject')">` + language.Get("reject") + `</button>
+<script>
+function changeReview(action) {
+    $.ajax({
+        dataType: 'json',
+        type: 'POST',
+        url: '` + h.config.Url("/change_requests/") + `' + action,
+        data: {'id': '` + fmt.Sprintf("%d", change.Id) + `', 'comment': $("#change-comment").val()},
+        success: function (data) {
+            $.pjax({url: '` + h.config.Url("/change_requests/review?id="+fmt.Sprintf("%d", change.Id)) + `', container: '#pjax-container'});
+        },
+        error: function (data) {
+            alert(data.responseJSON ? data.responseJSON.msg : '` + language.Get("review fail") + `');
+        }
+    });
+}
+</script>`)
+	}
+
+	h.HTML(ctx, user, types.Panel{
+		Content:     aBox().SetBody(body).GetContent(),
+		Title:       template2.HTML(language.Get("change request")),
+		Description: template2.HTML(language.Get("change request")),
+	})
+}
+
+// ApproveChangeRequest approve the change request and write the change to the table.
+// 核准變更申請並寫入資料表(以審核者的身分寫入變更紀錄)
+func (h *Handler) ApproveChangeRequest(ctx *context.Context) {
+	user := auth.Auth(ctx)
+	change, ok := h.reviewableChange(ctx, user)
+	if !ok {
+		return
+	}
+
+	approver, ok := h.table(change.Prefix, ctx).(table.Approver)
+	if !ok {
+		response.BadRequest(ctx, "the table does not support the approval")
+		return
+	}
+
+	if err := approver.ApplyChange(change, user.Id, ctx.FormValue("comment")); err != nil {
+		if err == models.ErrChangeReviewed {
+			response.BadRequest(ctx, err.Error())
+			return
+		}
+		logger.Error("approve change request error: ", err)
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// RejectChangeRequest reject the change request, the comment is required.
+// 駁回變更申請，必須填寫意見
+func (h *Handler) RejectChangeRequest(ctx *context.Context) {
+	user := auth.Auth(ctx)
+	comment := strings.TrimSpace(ctx.FormValue("comment"))
+	if comment == "" {
+		response.BadRequest(ctx, "comment is required")
+		return
+	}
+
+	change, ok := h.reviewableChange(ctx, user)
+	if !ok {
+		return
+	}
+
+	if err := table.RejectChange(change, user.Id, comment); err != nil {
+		if err == models.ErrChangeReviewed {
+			response.BadRequest(ctx, err.Error())
+			return
+		}
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// PendingChangeRequests return the count of the pending change requests which can be reviewed
+// by the login user, it is used by the notification of the navbar.
+// 回傳登入用戶可以審核的待審核申請數量(導航列通知)
+func (h *Handler) PendingChangeRequests(ctx *context.Context) {
+	response.OkWithData(ctx, map[string]interface{}{
+		"count": models.ChangeRequest().SetConn(h.conn).PendingCount(auth.Auth(ctx)),
+	})
+}
+
+// 取得表單中id的變更申請，並檢查用戶是否可以審核
+func (h *Handler) reviewableChange(ctx *context.Context, user models.UserModel) (models.ChangeRequestModel, bool) {
+	change := models.ChangeRequest().SetConn(h.conn).Find(ctx.FormValue("id"))
+	if change.IsEmpty() {
+		response.BadRequest(ctx, "change request not found")
+		return change, false
+	}
+	if !change.IsPending() {
+		response.BadRequest(ctx, models.ErrChangeReviewed.Error())
+		return change, false
+	}
+	if _, ok := h.generators[change.Prefix]; !ok || !change.CanReview(user) {
+		response.Denied(ctx, "permission denied")
+		return change, false
+	}
+	return change, true
+}
+
+func (h *Handler) changeUserName(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return models.User().SetConn(h.conn).Find(id).Name
+}
+
+func changeInfoItem(key, value string) map[string]types.InfoItem {
+	return map[string]types.InfoItem{
+		"key":   {Content: template2.HTML(language.Get(key))},
+		"value": {Content: template2.HTML(template2.HTMLEscapeString(value))},
+	}
+}
+
+// 變更的值轉換成字串，空值顯示為NULL
+func changeValueString(value interface{}) string {
+	if value == nil {
+		return "NULL"
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -82,7 +82,8 @@ func (h *Handler) SetRoutes(r context.RouterMap) {
 // 先透過參數prefix取得Table(interface)，接著判斷條件後將[]context.Node加入至Handler.operations後回傳
 func (h *Handler) table(prefix string, ctx *context.Context) table.Table {
 	// 透過參數prefix取得h.generators[prefix]的值(func(ctx *context.Context) Table)
-	t := h.generators[prefix](ctx)
+	// 設置目前登入用戶的行級資料權限、欄位權限及變更紀錄的操作者
+	t := table.SetRequest(h.generators[prefix](ctx), ctx, prefix, h.conn)
 
 	// 建立Invoker(Struct)並透過參數ctx取得UserModel，並且取得該user的role、權限與可用menu，最後檢查用戶權限
 	// GetConnection取得匹配的service.Service然後轉換成Connection(interface)類別
@@ -1,10 +1,14 @@
 package controller
 
 import (
+	"net/http"
+
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 )
 
 // Delete delete the row from database.
@@ -24,7 +28,19 @@ func (h *Handler) Delete(ctx *context.Context) {
 
 	// 透過id刪除資料
 	// param.Prefix = manager、roles、permission
-	if err := h.table(param.Prefix, ctx).DeleteData(param.Id); err != nil {
+	err := h.table(param.Prefix, ctx).DeleteData(param.Id)
+	// 需要審核的資料表只建立變更申請，回傳提示訊息
+	if err == table.ErrChangePending {
+		ctx.JSON(http.StatusOK, map[string]interface{}{
+			"code": http.StatusOK,
+			"msg":  language.Get(changePendingMsg),
+			"data": map[string]interface{}{
+				"token": h.authSrv().AddToken(),
+			},
+		})
+		return
+	}
+	if err != nil {
 		logger.Error(err)
 		response.Error(ctx, "delete fail")
 		return
@@ -17,6 +17,7 @@ import (
 	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 	"github.com/GoAdminGroup/go-admin/template/types"
 	"github.com/GoAdminGroup/go-admin/template/types/form"
 )
@@ -195,6 +196,15 @@ func (h *Handler) EditForm(ctx *context.Context) {
 	// Value()取得EditFormParam.MultiForm.Value(map[string][]string)，multipart/form-data所設定的參數
 	// UpdateData先將參數(map[string][]string)資料整理，接著判斷條件後執行資料更新的動作
 	err := param.Panel.UpdateData(param.Value())
+	// 需要審核的資料表只建立變更申請
+	if err == table.ErrChangePending {
+		if ctx.WantJSON() {
+			response.OkWithMsg(ctx, language.Get(changePendingMsg))
+		} else {
+			h.showForm(ctx, changePendingAlert(), param.Prefix, param.Param, true)
+		}
+		return
+	}
 	if err != nil {
 		// 判斷header裡包含accept:json
 		if ctx.WantJSON() {
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+)
+
+// Impersonate log in as another user, only the super admin can impersonate.
+// 超級管理員以其他用戶的身分登入，並記錄至變更紀錄
+func (h *Handler) Impersonate(ctx *context.Context) {
+	var (
+		user   = auth.Auth(ctx)
+		target = models.User().SetConn(h.conn).Find(ctx.FormValue("id"))
+	)
+
+	if err := auth.Impersonate(ctx, h.conn, user, target); err != nil {
+		logger.Error("impersonate error: ", err)
+		response.Error(ctx, "impersonate fail")
+		return
+	}
+
+	h.auditImpersonate(ctx, user.Id, target.Id, models.AuditImpersonate)
+
+	response.OkWithData(ctx, map[string]interface{}{
+		"url": config.GetIndexURL(),
+	})
+}
+
+// ExitImpersonate switch back to the super admin and redirect to the manager list.
+// 結束以其他用戶身分登入，切換回原本的超級管理員後導向管理員列表
+func (h *Handler) ExitImpersonate(ctx *context.Context) {
+	user := auth.Auth(ctx)
+
+	id, err := auth.ExitImpersonate(ctx, h.conn)
+	if err != nil {
+		logger.Error("exit impersonation error: ", err)
+		response.Error(ctx, "exit impersonation fail")
+		return
+	}
+
+	h.auditImpersonate(ctx, id, user.Id, models.AuditExitImpersonate)
+
+	ctx.AddHeader("Location", h.routePathWithPrefix("info", "manager"))
+	ctx.SetStatusCode(http.StatusFound)
+}
+
+// 將以其他用戶身分登入及結束的紀錄寫入變更紀錄
+func (h *Handler) auditImpersonate(ctx *context.Context, impersonatorId, userId int64, operation string) {
+	values := map[string]interface{}{
+		"impersonator_id": impersonatorId,
+		"user_id":         userId,
+	}
+	var old, new map[string]interface{}
+	if operation == models.AuditImpersonate {
+		new = values
+	} else {
+		old = values
+	}
+	if _, err := models.AuditLog().SetConn(h.conn).New(impersonatorId, config.GetAuthUserTable(),
+		strconv.FormatInt(userId, 10), operation, auth.ClientIP(ctx), old, new); err != nil {
+		logger.Error("impersonate audit error: ", err)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
 	"github.com/GoAdminGroup/go-admin/template/types"
 )
 
@@ -146,6 +147,15 @@ func (h *Handler) NewForm(ctx *context.Context) {
 	// InsertData在plugins\admin\modules\table\default.go
 	// 處理param.Value()(為multipart/form-data設定數值)後將資料加入資料表中
 	err := param.Panel.InsertData(param.Value())
+	// 需要審核的資料表只建立變更申請
+	if err == table.ErrChangePending {
+		if ctx.WantJSON() {
+			response.OkWithMsg(ctx, language.Get(changePendingMsg))
+		} else {
+			h.showNewForm(ctx, changePendingAlert(), param.Prefix, param.Param.GetRouteParamStr(), true)
+		}
+		return
+	}
 	if err != nil {
 		if ctx.WantJSON() {
 			response.Error(ctx, err.Error())
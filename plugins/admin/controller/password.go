@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"bytes"
+	template2 "html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/template"
+)
+
+// ShowLoginPassword show the page of changing the expired password.
+// 密碼過期時修改密碼的頁面
+func (h *Handler) ShowLoginPassword(ctx *context.Context) {
+	if _, ok := auth.GetPasswordChangePendingUser(ctx, h.conn); !ok {
+		ctx.AddHeader("Location", h.config.Url(config.GetLoginUrl()))
+		ctx.SetStatusCode(http.StatusFound)
+		return
+	}
+
+	tmpl, err := template2.New("password").Funcs(template.DefaultFuncMap).Parse(loginPasswordTmpl)
+	if err != nil {
+		logger.Error(err)
+		ctx.HTML(http.StatusOK, "parse template error (；′⌒`)")
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, struct {
+		UrlPrefix string
+		Title     string
+		CdnUrl    string
+	}{
+		UrlPrefix: h.config.AssertPrefix(),
+		Title:     h.config.LoginTitle,
+		CdnUrl:    h.config.AssetUrl,
+	}); err == nil {
+		ctx.HTML(http.StatusOK, buf.String())
+	} else {
+		logger.Error(err)
+		ctx.HTML(http.StatusOK, "parse template error (；′⌒`)")
+	}
+}
+
+// LoginPassword change the expired password of the pending user and login.
+// 檢查新密碼是否符合密碼規則，修改密碼後登入
+func (h *Handler) LoginPassword(ctx *context.Context) {
+	user, ok := auth.GetPasswordChangePendingUser(ctx, h.conn)
+	if !ok {
+		ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"code": http.StatusUnauthorized,
+			"msg":  language.Get("login expired, please login again"),
+			"data": map[string]interface{}{
+				"url": h.config.Url(config.GetLoginUrl()),
+			},
+		})
+		return
+	}
+
+	password := ctx.FormValue("password")
+
+	if password != ctx.FormValue("password_again") {
+		response.BadRequest(ctx, "password does not match")
+		return
+	}
+
+	if err := auth.CheckPasswordPolicy(user.Id, password, h.conn); err != nil {
+		response.BadRequest(ctx, err.Error())
+		return
+	}
+
+	hash := auth.EncodePassword([]byte(password))
+	user.SetConn(h.conn).UpdatePwd(hash)
+	auth.SavePasswordHistory(user.Id, hash, h.conn)
+
+	if err := auth.FinishPasswordChangePending(ctx, user, h.conn); err != nil {
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	response.OkWithData(ctx, map[string]interface{}{
+		"url": h.loginRedirectURL(ctx),
+	})
+}
+
+// 自訂的身分驗證(例如LDAP)由外部管理密碼，不檢查密碼是否過期
+func (h *Handler) passwordExpired(user models.UserModel) bool {
+	if _, exist := h.services.GetOrNot(auth.ServiceKey); exist {
+		return false
+	}
+	return auth.PasswordExpired(user, h.conn)
+}
+
+// 設置等待修改密碼的狀態，回傳修改密碼頁面的url(保留登入頁面帶入的ref)
+func (h *Handler) pendingPasswordChange(ctx *context.Context, user models.UserModel, data map[string]interface{}) {
+	if err := auth.SetPasswordChangePending(ctx, user, h.conn); err != nil {
+		response.Error(ctx, err.Error())
+		return
+	}
+	passwordUrl := h.config.Url("/login/password")
+	if ref := refFromReferer(ctx); ref != "" {
+		passwordUrl += "?ref=" + url.QueryEscape(ref)
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["url"] = passwordUrl
+	response.OkWithData(ctx, data)
+}
+
+const loginPasswordTmpl = `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <meta http-equiv="X-UA-Compatible" content="IE=edge">
+    <title>{{.Title}}</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{link .CdnUrl .UrlPrefix "/assets/login/dist/all.min.css"}}">
+</head>
+<body>
+<div class="container">
+    <div class="row" style="margin-top: 80px;">
+        <div class="col-md-4 col-md-offset-4">
+            <form action="##" onsubmit="return false" method="post" id="password-form" class="fh5co-form">
+                <h2>{{lang "new password"}}</h2>
+                <p>{{lang "password expired, please set a new password"}}</p>
+                <div class="form-group">
+                    <input type="password" class="form-control" id="password" autocomplete="off"
+                           placeholder="{{lang "new password"}}">
+                </div>
+                <div class="form-group">
+                    <input type="password" class="form-control" id="password-again" autocomplete="off"
+                           placeholder="{{lang "confirm password"}}">
+                </div>
+                <div class="form-group">
+                    <button class="btn btn-primary" onclick="submitPassword()">{{lang "save"}}</button>
+                </div>
+            </form>
+        </div>
+    </div>
+</div>
+<script src="{{link .CdnUrl .UrlPrefix "/assets/login/dist/all.min.js"}}"></script>
+<script>
+    function submitPassword() {
+        $.ajax({
+            dataType: 'json',
+            type: 'POST',
+            url: '{{.UrlPrefix}}/login/password',
+            data: {
+                'password': $("#password").val(),
+                'password_again': $("#password-again").val()
+            },
+            success: function (data) {
+                location.href = data.data.url
+            },
+            error: function (data) {
+                var res = data.responseJSON || {};
+                alert(res.msg || 'error');
+                if (res.data && res.data.url) {
+                    location.href = res.data.url
+                }
+            }
+        });
+    }
+</script>
+</body>
+</html>`
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+)
+
+// PermissionRoute is a route matched by the tested permission.
+type PermissionRoute struct {
+	Name   string `json:"name"`
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Effect string `json:"effect"`
+}
+
+// TestPermission return the routes which the permission rule grants or denies.
+// 以表單中的http方法及路徑建立權限，回傳所有路由中被該權限允許或拒絕的路由，路由中的:__prefix會展開成所有資料表
+func (h *Handler) TestPermission(ctx *context.Context) {
+	var methods []string
+	if method := ctx.FormValue("http_method"); method != "" {
+		methods = strings.Split(method, ",")
+	} else {
+		methods = []string{""}
+	}
+
+	permission := models.PermissionModel{
+		HttpMethod: methods,
+		HttpPath:   strings.Split(ctx.FormValue("http_path"), "\n"),
+	}
+
+	response.OkWithData(ctx, map[string]interface{}{
+		"routes": h.permissionRoutes(permission),
+	})
+}
+
+func (h *Handler) permissionRoutes(permission models.PermissionModel) []PermissionRoute {
+	var (
+		names    = make([]string, 0, len(h.routes))
+		prefixes = make([]string, 0, len(h.generators))
+		routes   = make([]PermissionRoute, 0)
+	)
+	for name := range h.routes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for prefix := range h.generators {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, name := range names {
+		router := h.routes[name]
+		paths := []string{router.Patten}
+		if strings.Contains(router.Patten, ":__prefix") {
+			paths = make([]string, len(prefixes))
+			for i, prefix := range prefixes {
+				paths[i] = strings.Replace(router.Patten, ":__prefix", prefix, -1)
+			}
+		}
+		for _, path := range paths {
+			for _, method := range router.Methods {
+				method = strings.ToUpper(method)
+				if effect := permission.Effect(path, method, url.Values{}); effect != "" {
+					routes = append(routes, PermissionRoute{Name: name, Method: method, Path: path, Effect: effect})
+				}
+			}
+		}
+	}
+	return routes
+}
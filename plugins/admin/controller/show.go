@@ -115,6 +115,11 @@ func (h *Handler) showTableData(ctx *context.Context, prefix string, params para
 	exportUrl = user.GetCheckPermissionByUrlMethod(exportUrl, h.route(urlNamePrefix+"export").Method()) //post
 	detailUrl = user.GetCheckPermissionByUrlMethod(detailUrl, h.route(urlNamePrefix+"detail").Method()) //get
 
+	// 回收站中的資料不可新增、編輯及刪除，只能還原或永久刪除
+	if table.GetSoftDeleter(panel) != nil && params.IsTrash() {
+		editUrl, newUrl, deleteUrl = "", "", ""
+	}
+
 	return panel, panelInfo, []string{editUrl, newUrl, deleteUrl, exportUrl, detailUrl, infoUrl, updateUrl}, nil
 }
 
@@ -160,6 +165,17 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 		}
 	}
 
+	// 軟刪除的資料表加入回收站的切換按鈕
+	if table.GetSoftDeleter(panel) != nil {
+		if params.IsTrash() {
+			allBtns = append(allBtns, types.GetDefaultButton(language.GetFromHtml("back"), icon.Reply,
+				action.Jump(infoUrl)))
+		} else if deleteUrl != "" {
+			allBtns = append(allBtns, types.GetDefaultButton(language.GetFromHtml("recycle bin"), icon.Trash,
+				action.Jump(infoUrl+"?"+parameter.Trash+"="+parameter.True)))
+		}
+	}
+
 	// 取得HTML及JSON
 	// 上面為空因此這裡也是空值
 	btns, btnsJs := allBtns.Content()
@@ -173,6 +189,26 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 		}
 	}
 
+	// 回收站中每筆資料可以還原或永久刪除
+	if table.GetSoftDeleter(panel) != nil && params.IsTrash() {
+		restoreUrl := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("restore", prefix), h.route("restore").Method())
+		purgeUrl := user.GetCheckPermissionByUrlMethod(h.routePathWithPrefix("purge", prefix), h.route("purge").Method())
+		if restoreUrl != "" {
+			allActionBtns = append(allActionBtns, types.GetActionButton(language.GetFromHtml("restore"),
+				trashAction(restoreUrl, "are you sure to restore"), "grid-row-restore"))
+		}
+		if purgeUrl != "" {
+			allActionBtns = append(allActionBtns, types.GetActionButton(language.GetFromHtml("purge"),
+				trashAction(purgeUrl, "are you sure to purge"), "grid-row-purge"))
+		}
+	}
+
+	// 超級管理員可以在管理員列表以其他用戶身分登入
+	if prefix == "manager" && user.IsSuperAdmin() && !user.IsImpersonated() && !params.IsTrash() {
+		allActionBtns = append(allActionBtns, types.GetActionButton(language.GetFromHtml("log in as"),
+			impersonateAction(h.routePath("impersonate")), "grid-row-impersonate"))
+	}
+
 	// 上面為空，因此這裡不執行
 	if actionBtns == template.HTML("") && len(allActionBtns) > 0 {
 		ext := template.HTML("")
@@ -324,9 +360,7 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 				SetMethod("get").
 				SetLayout(info.FilterFormLayout). // ex:LayoutDefault
 				SetUrl(infoUrl).                  //  + params.GetFixedParamStrWithoutColumnsAndPage()
-				SetHiddenFields(map[string]string{
-					form.NoAnimationKey: "true",
-				}).
+				SetHiddenFields(filterHiddenFields(params)).
 				SetOperationFooter(filterFormFooter(infoUrl)).
 				GetContent())
 	}
@@ -348,6 +382,55 @@ func (h *Handler) showTable(ctx *context.Context, prefix string, params paramete
 	}, params.Animation)
 }
 
+// 篩選表單的隱藏欄位，在回收站中篩選時保留回收站參數
+func filterHiddenFields(params parameter.Parameters) map[string]string {
+	fields := map[string]string{
+		form.NoAnimationKey: "true",
+	}
+	if params.IsTrash() {
+		fields[parameter.Trash] = parameter.True
+	}
+	return fields
+}
+
+// 還原、永久刪除回收站資料的按鈕動作，確認後送出請求並重新載入頁面
+func trashAction(url, confirm string) *action.AjaxAction {
+	return action.Ajax("trash", nil).SetUrl(url).
+		WithAlert(action.AlertData{
+			Title:              language.Get(confirm),
+			Type:               "warning",
+			ShowCancelButton:   true,
+			ConfirmButtonColor: "#DD6B55",
+			ConfirmButtonText:  language.Get("yes"),
+			CloseOnConfirm:     false,
+			CancelButtonText:   language.Get("cancel"),
+		}).
+		SetSuccessJS(`if (data.code === 200) {
+                                    swal(data.msg, '', 'success');
+                                    $.pjax.reload('#pjax-container');
+                                } else {
+                                    swal(data.msg, '', 'error');
+                                }`)
+}
+
+func impersonateAction(url string) *action.AjaxAction {
+	return action.Ajax("impersonate", nil).SetUrl(url).
+		WithAlert(action.AlertData{
+			Title:              language.Get("are you sure to log in as the user"),
+			Type:               "warning",
+			ShowCancelButton:   true,
+			ConfirmButtonColor: "#DD6B55",
+			ConfirmButtonText:  language.Get("yes"),
+			CloseOnConfirm:     false,
+			CancelButtonText:   language.Get("cancel"),
+		}).
+		SetSuccessJS(`if (data.code === 200) {
+                                    location.href = data.data.url;
+                                } else {
+                                    swal(data.msg, '', 'error');
+                                }`)
+}
+
 // Assets return front-end assets according the request path.
 // 處理前端檔案
 func (h *Handler) Assets(ctx *context.Context) {
@@ -419,21 +502,66 @@ func (h *Handler) Export(ctx *context.Context) {
 		"L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z"}
 
 	var (
-		infoData table.PanelInfo
-		fileName string
-		err      error
+		fileName    string
+		err         error
+		wroteHead   = false
+		count       = 2
+		columnIndex = 0
 		// 將參數值設置至base.Info(InfoPanel(struct)).primaryKey中後回傳InfoPanel(struct)
 		tableInfo = panel.GetInfo()
 	)
 
+	// 將資料寫入excel，第一次呼叫時先寫入欄位名稱
+	write := func(infoData table.PanelInfo) error {
+		if !wroteHead {
+			wroteHead = true
+			columnIndex = 0
+			// PanelInfo.Thead為頁面中所有的欄位資訊
+			for _, head := range infoData.Thead {
+				// 如果不隱藏欄位則執行
+				// 將欄位名稱設置至excel
+				if !head.Hide {
+					f.SetCellValue(tableName, orders[columnIndex]+"1", head.Head)
+					columnIndex++
+				}
+			}
+		}
+
+		// PanelInfo.InfoList為取得的資料
+		for _, info := range infoData.InfoList {
+			columnIndex = 0
+			for _, head := range infoData.Thead {
+				// 如果不隱藏欄位則執行
+				// 將數值設置至excel
+				if !head.Hide {
+					if tableInfo.IsExportValue() {
+						f.SetCellValue(tableName, orders[columnIndex]+strconv.Itoa(count), info[head.Field].Value)
+					} else {
+						f.SetCellValue(tableName, orders[columnIndex]+strconv.Itoa(count), info[head.Field].Content)
+					}
+					columnIndex++
+				}
+			}
+			count++
+		}
+		return nil
+	}
+
 	// 判斷是否有選擇匯出特定資料，如選擇當頁或全部(則Id為空)
 	if len(param.Id) == 0 {
 		// GetParam取得頁面size、資料排列方式、選擇欄位...等資訊後設置至Parameters(struct)並回傳
 		params := parameter.GetParam(ctx.Request.URL, tableInfo.DefaultPageSize, tableInfo.SortField,
 			tableInfo.GetSort())
 
-		// 透過參數處理sql語法後取得資料表資料並將值設置至PanelInfo(struct)後回傳，PanelInfo裡的資訊有主題、描述名稱、可以篩選條件的欄位、選擇顯示的欄位....等資訊
-		infoData, err = panel.GetData(params.WithIsAll(param.IsAll))
+		// 匯出全部時分批讀取資料並寫入(excel檔仍保留所有資料，筆數受匯出的最大筆數限制)，沒有實作Exporter的資料表一次取得所有資料
+		if exporter, ok := panel.(table.Exporter); ok {
+			err = exporter.ExportData(params.WithIsAll(param.IsAll), write)
+		} else {
+			var infoData table.PanelInfo
+			if infoData, err = panel.GetData(params.WithIsAll(param.IsAll)); err == nil {
+				err = write(infoData)
+			}
+		}
 
 		// ex: 权限管理-1594877943-page-1-pageSize-10.xlsx
 		fileName = fmt.Sprintf("%s-%d-page-%s-pageSize-%s.xlsx", tableInfo.Title, time.Now().Unix(),
@@ -441,48 +569,26 @@ func (h *Handler) Export(ctx *context.Context) {
 	} else {
 		// 選擇匯出特定資料
 		// 透過參數(選擇取得特定id資料)處理sql語法後取得資料表資料並將值設置至PanelInfo(struct)後回傳，PanelInfo裡的資訊有主題、描述名稱、可以篩選條件的欄位、選擇顯示的欄位....等資訊
+		var infoData table.PanelInfo
 		infoData, err = panel.GetDataWithIds(parameter.GetParam(ctx.Request.URL,
 			// WithPKs將參數(多個string)結合並設置至Parameters.Fields["__pk"]後回傳
 			tableInfo.DefaultPageSize, tableInfo.SortField, tableInfo.GetSort()).WithPKs(param.Id...))
+		if err == nil {
+			err = write(infoData)
+		}
 
 		// ex:权限管理-1594876892-id-40_39_38.xlsx
 		fileName = fmt.Sprintf("%s-%d-id-%s.xlsx", tableInfo.Title, time.Now().Unix(), strings.Join(param.Id, "_"))
 	}
+	if err == table.ErrExportLimit {
+		response.Error(ctx, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(ctx, "export error")
 		return
 	}
 
-	columnIndex := 0
-	// PanelInfo.Thead為頁面中所有的欄位資訊
-	for _, head := range infoData.Thead {
-		// 如果不隱藏欄位則執行
-		// 將欄位名稱設置至excel
-		if !head.Hide {
-			f.SetCellValue(tableName, orders[columnIndex]+"1", head.Head)
-			columnIndex++
-		}
-	}
-
-	count := 2
-	// PanelInfo.InfoList為所有取得的資料
-	for _, info := range infoData.InfoList {
-		columnIndex = 0
-		for _, head := range infoData.Thead {
-			// 如果不隱藏欄位則執行
-			// 將數值設置至excel
-			if !head.Hide {
-				if tableInfo.IsExportValue() {
-					f.SetCellValue(tableName, orders[columnIndex]+strconv.Itoa(count), info[head.Field].Value)
-				} else {
-					f.SetCellValue(tableName, orders[columnIndex]+strconv.Itoa(count), info[head.Field].Content)
-				}
-				columnIndex++
-			}
-		}
-		count++
-	}
-
 	buf, err := f.WriteToBuffer()
 
 	if err != nil || buf == nil {
@@ -0,0 +1,401 @@
+package controller
+
+import (
+	"bytes"
+	template2 "html/template"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/language"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/qrcode"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/types"
+)
+
+// ShowLoginTOTP show the page of the two-factor authentication after the password is checked.
+// 密碼驗證成功後輸入驗證碼的頁面，尚未綁定但角色要求兩步驟驗證的用戶則顯示綁定的QR code
+func (h *Handler) ShowLoginTOTP(ctx *context.Context) {
+	user, ok := auth.GetTOTPPendingUser(ctx, h.conn)
+	if !ok {
+		ctx.AddHeader("Location", h.config.Url(config.GetLoginUrl()))
+		ctx.SetStatusCode(http.StatusFound)
+		return
+	}
+
+	totp := models.UserTOTP().SetConn(h.conn).FindByUserId(user.Id)
+
+	var (
+		enroll template2.HTML
+		err    error
+	)
+	if !totp.Enabled {
+		enroll, err = h.totpEnrollContent(user)
+		if err != nil {
+			logger.Error("totp enroll error", err)
+			ctx.HTML(http.StatusOK, "parse template error (；′⌒`)")
+			return
+		}
+	}
+
+	tmpl, err := template2.New("totp").Funcs(template.DefaultFuncMap).Parse(loginTOTPTmpl)
+	if err != nil {
+		logger.Error(err)
+		ctx.HTML(http.StatusOK, "parse template error (；′⌒`)")
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, struct {
+		UrlPrefix string
+		Title     string
+		CdnUrl    string
+		Enroll    template2.HTML
+	}{
+		UrlPrefix: h.config.AssertPrefix(),
+		Title:     h.config.LoginTitle,
+		CdnUrl:    h.config.AssetUrl,
+		Enroll:    enroll,
+	}); err == nil {
+		ctx.HTML(http.StatusOK, buf.String())
+	} else {
+		logger.Error(err)
+		ctx.HTML(http.StatusOK, "parse template error (；′⌒`)")
+	}
+}
+
+// LoginTOTP check the code of the pending user and login.
+// 檢查驗證碼(或恢復碼)後登入，綁定中的用戶驗證成功後開啟兩步驟驗證並回傳恢復碼
+func (h *Handler) LoginTOTP(ctx *context.Context) {
+	user, ok := auth.GetTOTPPendingUser(ctx, h.conn)
+	if !ok {
+		totpRelogin(ctx, h.config.Url(config.GetLoginUrl()))
+		return
+	}
+
+	// 驗證碼錯誤與密碼錯誤共用失敗次數及鎖定
+	if !h.checkLoginLockout(ctx, user.UserName) {
+		return
+	}
+
+	var (
+		code          = ctx.FormValue("code")
+		totp          = models.UserTOTP().SetConn(h.conn).FindByUserId(user.Id)
+		recoveryCodes []string
+	)
+
+	if totp.Enabled {
+		ok = auth.CheckTOTP(user, code, h.conn)
+	} else {
+		ok = totp.Secret != "" && auth.ValidateTOTP(totp.Secret, code, time.Now())
+		if ok {
+			var hashes []string
+			recoveryCodes, hashes = auth.GenerateRecoveryCodes()
+			if _, err := totp.Enable(hashes); db.CheckError(err, db.UPDATE) {
+				response.Error(ctx, err.Error())
+				return
+			}
+		}
+	}
+
+	if !ok {
+		auth.RecordLoginFailure(ctx, user.UserName, auth.LoginFailWrongCode, h.conn)
+		if !h.addTOTPFailure(ctx) {
+			return
+		}
+		response.BadRequest(ctx, "wrong code")
+		return
+	}
+
+	auth.ResetLoginFailures(user.UserName, h.conn)
+
+	var data map[string]interface{}
+	if len(recoveryCodes) > 0 {
+		data = map[string]interface{}{
+			"recovery_codes": recoveryCodes,
+		}
+	}
+
+	// 密碼過期時改為等待修改密碼
+	if h.passwordExpired(user) {
+		h.pendingPasswordChange(ctx, user, data)
+		return
+	}
+
+	if err := auth.FinishTOTPPending(ctx, user, h.conn); err != nil {
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["url"] = h.loginRedirectURL(ctx)
+	response.OkWithData(ctx, data)
+}
+
+// 記錄驗證碼錯誤，超過次數限制時回傳false並要求重新登入
+func (h *Handler) addTOTPFailure(ctx *context.Context) bool {
+	ok, err := auth.AddTOTPPendingFailure(ctx, h.conn)
+	if err != nil {
+		response.Error(ctx, err.Error())
+		return false
+	}
+	if !ok {
+		totpRelogin(ctx, h.config.Url(config.GetLoginUrl()))
+		return false
+	}
+	return true
+}
+
+// 等待狀態已過期或已清除，回傳401及登入頁面的url
+func totpRelogin(ctx *context.Context, loginUrl string) {
+	ctx.JSON(http.StatusUnauthorized, map[string]interface{}{
+		"code": http.StatusUnauthorized,
+		"msg":  language.Get("too many wrong codes, please login again"),
+		"data": map[string]interface{}{
+			"url": loginUrl,
+		},
+	})
+}
+
+// ShowTOTP show the two-factor authentication setting of the login user.
+// 用戶中心的兩步驟驗證設置頁面
+func (h *Handler) ShowTOTP(ctx *context.Context) {
+	h.showTOTP(ctx, "")
+}
+
+func (h *Handler) showTOTP(ctx *context.Context, alert template2.HTML) {
+	user := auth.Auth(ctx)
+	totp := models.UserTOTP().SetConn(h.conn).FindByUserId(user.Id)
+
+	var (
+		body template2.HTML
+		err  error
+	)
+
+	if totp.Enabled {
+		body = template2.HTML(`<p>` + language.Get("two factor authentication is enabled") + `</p>
+<p>` + language.Get("recovery codes") + `: ` + strconv.Itoa(len(totp.RecoveryCodes)) + `</p>
+<div class="form-group"><input type="text" class="form-control" id="totp-code" autocomplete="off" placeholder="` +
+			language.Get("please enter the code of your authenticator app") + `"></div>
+<button class="btn btn-primary" onclick="totpSubmit('recovery_codes')">` + language.Get("regenerate recovery codes") + `</button>`)
+		if !auth.TOTPRequired(user, h.conn) {
+			body += template2.HTML(` <button class="btn btn-danger" onclick="totpSubmit('disable')">` +
+				language.Get("disable two factor authentication") + `</button>`)
+		}
+	} else {
+		body, err = h.totpEnrollContent(user)
+		if err != nil {
+			alert = aAlert().Warning(err.Error())
+		}
+		body = template2.HTML(`<p>`+language.Get("two factor authentication is disabled")+`</p>`) + body +
+			template2.HTML(`<button class="btn btn-primary" onclick="totpSubmit('enable')">`+
+				language.Get("enable two factor authentication")+`</button>`)
+	}
+
+	body += template2.HTML(`<div id="totp-recovery-codes" style="display: none;margin-top: 15px;">
+<p>` + language.Get("save the recovery codes") + `</p><pre></pre>
+<a class="btn btn-default" href="` + h.config.Url("/totp") + `">` + language.Get("continue") + `</a></div>
+<script>
+function totpSubmit(action) {
+    $.ajax({
+        dataType: 'json',
+        type: 'POST',
+        url: '` + h.config.Url("/totp/") + `' + action,
+        data: {'code': $("#totp-code").val()},
+        success: function (data) {
+            if (data.data && data.data.recovery_codes) {
+                $("#totp-recovery-codes pre").text(data.data.recovery_codes.join("\n"));
+                $("#totp-recovery-codes").show();
+            } else {
+                $.pjax({url: '` + h.config.Url("/totp") + `', container: '#pjax-container'});
+            }
+        },
+        error: function (data) {
+            alert(data.responseJSON ? data.responseJSON.msg : '` + language.Get("wrong code") + `');
+        }
+    });
+}
+</script>`)
+
+	h.HTML(ctx, user, types.Panel{
+		Content:     alert + aBox().SetBody(body).GetContent(),
+		Title:       template2.HTML(language.Get("two factor authentication")),
+		Description: template2.HTML(language.Get("two factor authentication")),
+	})
+}
+
+// EnableTOTP enable the two-factor authentication of the login user.
+// 驗證綁定中的secret後開啟兩步驟驗證並回傳恢復碼
+func (h *Handler) EnableTOTP(ctx *context.Context) {
+	user := auth.Auth(ctx)
+	totp := models.UserTOTP().SetConn(h.conn).FindByUserId(user.Id)
+
+	if totp.Enabled || totp.Secret == "" || !auth.ValidateTOTP(totp.Secret, ctx.FormValue("code"), time.Now()) {
+		response.BadRequest(ctx, "wrong code")
+		return
+	}
+
+	codes, hashes := auth.GenerateRecoveryCodes()
+	if _, err := totp.Enable(hashes); db.CheckError(err, db.UPDATE) {
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	response.OkWithData(ctx, map[string]interface{}{
+		"recovery_codes": codes,
+	})
+}
+
+// DisableTOTP disable the two-factor authentication of the login user.
+// 關閉兩步驟驗證，角色要求兩步驟驗證的用戶不能關閉
+func (h *Handler) DisableTOTP(ctx *context.Context) {
+	user := auth.Auth(ctx)
+
+	if auth.TOTPRequired(user, h.conn) {
+		response.BadRequest(ctx, "two factor authentication is required")
+		return
+	}
+
+	if !auth.CheckTOTP(user, ctx.FormValue("code"), h.conn) {
+		response.BadRequest(ctx, "wrong code")
+		return
+	}
+
+	totp := models.UserTOTP().SetConn(h.conn).FindByUserId(user.Id)
+	if err := totp.Delete(); db.CheckError(err, db.DELETE) {
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	response.Ok(ctx)
+}
+
+// RegenerateTOTPRecoveryCodes replace the recovery codes of the login user.
+// 重新產生恢復碼，舊的恢復碼將失效
+func (h *Handler) RegenerateTOTPRecoveryCodes(ctx *context.Context) {
+	user := auth.Auth(ctx)
+
+	if !auth.CheckTOTP(user, ctx.FormValue("code"), h.conn) {
+		response.BadRequest(ctx, "wrong code")
+		return
+	}
+
+	codes, hashes := auth.GenerateRecoveryCodes()
+	totp := models.UserTOTP().SetConn(h.conn).FindByUserId(user.Id)
+	if _, err := totp.SetRecoveryCodes(hashes); db.CheckError(err, db.UPDATE) {
+		response.Error(ctx, err.Error())
+		return
+	}
+
+	response.OkWithData(ctx, map[string]interface{}{
+		"recovery_codes": codes,
+	})
+}
+
+// 產生新的secret(尚未開啟)並回傳QR code、secret以及驗證碼輸入框的HTML
+// QR code在本地產生(svg)，避免secret傳送至第三方服務
+func (h *Handler) totpEnrollContent(user models.UserModel) (template2.HTML, error) {
+	secret := auth.GenerateTOTPSecret()
+	if _, err := models.UserTOTP().SetConn(h.conn).SetSecret(user.Id, secret); err != nil {
+		return "", err
+	}
+
+	issuer := h.config.Title
+	if issuer == "" {
+		issuer = "GoAdmin"
+	}
+
+	code, err := qrcode.Encode(auth.TOTPURI(issuer, user.UserName, secret))
+	if err != nil {
+		return "", err
+	}
+
+	return template2.HTML(`<p>` + language.Get("scan the qrcode with your authenticator app") + `</p>
+<div>` + code.SVG(4) + `</div>
+<p>` + language.Get("secret") + `: <code>` + template2.HTMLEscapeString(secret) + `</code></p>
+<div class="form-group"><input type="text" class="form-control" id="totp-code" autocomplete="off" placeholder="` +
+		language.Get("please enter the code of your authenticator app") + `"></div>`), nil
+}
+
+// 回傳登入成功後跳轉的url，登入頁面有帶入ref時跳轉至ref
+func (h *Handler) loginRedirectURL(ctx *context.Context) string {
+	if ref := refFromReferer(ctx); ref != "" {
+		return ref
+	}
+	return h.config.GetIndexURL()
+}
+
+const loginTOTPTmpl = `<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="utf-8">
+    <meta http-equiv="X-UA-Compatible" content="IE=edge">
+    <title>{{.Title}}</title>
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <link rel="stylesheet" href="{{link .CdnUrl .UrlPrefix "/assets/login/dist/all.min.css"}}">
+</head>
+<body>
+<div class="container">
+    <div class="row" style="margin-top: 80px;">
+        <div class="col-md-4 col-md-offset-4">
+            <form action="##" onsubmit="return false" method="post" id="totp-form" class="fh5co-form">
+                <h2>{{lang "two factor authentication"}}</h2>
+                {{if .Enroll}}
+                    <p>{{lang "two factor authentication is required"}}</p>
+                    {{.Enroll}}
+                {{else}}
+                    <div class="form-group">
+                        <input type="text" class="form-control" id="totp-code" autocomplete="off"
+                               placeholder="{{lang "please enter the code of your authenticator app"}}">
+                    </div>
+                {{end}}
+                <div class="form-group">
+                    <button class="btn btn-primary" onclick="submitCode()">{{lang "verify"}}</button>
+                </div>
+                <div id="totp-recovery-codes" style="display: none;">
+                    <p>{{lang "save the recovery codes"}}</p>
+                    <pre></pre>
+                    <a class="btn btn-primary" href="#">{{lang "continue"}}</a>
+                </div>
+            </form>
+        </div>
+    </div>
+</div>
+<script src="{{link .CdnUrl .UrlPrefix "/assets/login/dist/all.min.js"}}"></script>
+<script>
+    function submitCode() {
+        $.ajax({
+            dataType: 'json',
+            type: 'POST',
+            url: '{{.UrlPrefix}}/login/totp',
+            data: {'code': $("#totp-code").val()},
+            success: function (data) {
+                if (data.data.recovery_codes) {
+                    $("#totp-recovery-codes pre").text(data.data.recovery_codes.join("\n"));
+                    $("#totp-recovery-codes a").attr("href", data.data.url);
+                    $("#totp-recovery-codes").show();
+                } else {
+                    location.href = data.data.url
+                }
+            },
+            error: function (data) {
+                var res = data.responseJSON || {};
+                alert(res.msg || '{{lang "wrong code"}}');
+                if (res.data && res.data.url) {
+                    location.href = res.data.url
+                }
+            }
+        });
+    }
+</script>
+</body>
+</html>`
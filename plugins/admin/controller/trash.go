@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/guard"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/response"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/table"
+)
+
+// Restore restore the rows from the recycle bin.
+// 透過id將回收站中的資料還原後回傳code、data(token)、msg
+func (h *Handler) Restore(ctx *context.Context) {
+	param := guard.GetDeleteParam(ctx)
+
+	trash := table.GetSoftDeleter(h.table(param.Prefix, ctx))
+	if trash == nil {
+		response.BadRequest(ctx, "restore fail")
+		return
+	}
+
+	if err := trash.RestoreData(param.Id); err != nil {
+		logger.Error(err)
+		response.Error(ctx, "restore fail")
+		return
+	}
+	response.OkWithData(ctx, map[string]interface{}{
+		"token": h.authSrv().AddToken(),
+	})
+}
+
+// Purge permanently delete the rows in the recycle bin.
+// 透過id永久刪除回收站中的資料後回傳code、data(token)、msg
+func (h *Handler) Purge(ctx *context.Context) {
+	param := guard.GetDeleteParam(ctx)
+
+	trash := table.GetSoftDeleter(h.table(param.Prefix, ctx))
+	if trash == nil {
+		response.BadRequest(ctx, "purge fail")
+		return
+	}
+
+	if err := trash.PurgeData(param.Id); err != nil {
+		logger.Error(err)
+		response.Error(ctx, "purge fail")
+		return
+	}
+	response.OkWithData(ctx, map[string]interface{}{
+		"token": h.authSrv().AddToken(),
+	})
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// APIKeyTouchInterval is the minimum interval of updating the last used time of an api key.
+// 更新api key最後使用時間的最短間隔
+var APIKeyTouchInterval = time.Minute
+
+// APIKeyModel is the api key of a service account, the key is only stored hashed and
+// the permissions are the slugs of the PermissionModel.
+// 服務帳號的api key，只儲存key的hash，權限為綁定的PermissionModel slug
+type APIKeyModel struct {
+	Base
+
+	Id          int64
+	Name        string
+	UserId      int64
+	Prefix      string `db:"key_prefix"`
+	Hash        string `db:"key_hash"`
+	Permissions []string
+	ExpiresAt   string
+	LastUsedAt  string
+	LastUsedIp  string
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+// APIKey return a default api key model.
+func APIKey() APIKeyModel {
+	return APIKeyModel{Base: Base{TableName: "goadmin_api_keys"}}
+}
+
+func (t APIKeyModel) SetConn(con db.Connection) APIKeyModel {
+	t.Conn = con
+	return t
+}
+
+func (t APIKeyModel) WithTx(tx *sql.Tx) APIKeyModel {
+	t.Tx = tx
+	return t
+}
+
+// Find return the api key model of the given id.
+func (t APIKeyModel) Find(id interface{}) APIKeyModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// FindByPrefix return the api key model of the given key prefix.
+// 透過key的前綴尋找符合的資料，前綴為唯一值
+func (t APIKeyModel) FindByPrefix(prefix string) APIKeyModel {
+	item, _ := t.Table(t.TableName).Where("key_prefix", "=", prefix).First()
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the model is empty or not.
+func (t APIKeyModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// IsExpired check the api key is expired or not, the key never expires if the expiry is empty.
+// 判斷api key是否過期，未設置過期時間表示永不過期
+func (t APIKeyModel) IsExpired() bool {
+	if t.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.ParseInLocation("2006-01-02 15:04:05", parseTimeValue(t.ExpiresAt), time.Local)
+	if err != nil {
+		return true
+	}
+	return !time.Now().Before(expiresAt)
+}
+
+// New create a new api key with the prefix and hash of the key.
+// 新增api key，只儲存key的前綴及hash
+func (t APIKeyModel) New(name string, userId int64, prefix, hash string, permissions []string, expiresAt string) (APIKeyModel, error) {
+	id, err := t.WithTx(t.Tx).Table(t.TableName).Insert(dialect.H{
+		"name":        name,
+		"user_id":     userId,
+		"key_prefix":  prefix,
+		"key_hash":    hash,
+		"permissions": strings.Join(permissions, ","),
+		"expires_at":  nullTime(expiresAt),
+	})
+
+	t.Id = id
+	t.Name = name
+	t.UserId = userId
+	t.Prefix = prefix
+	t.Hash = hash
+	t.Permissions = permissions
+	t.ExpiresAt = expiresAt
+
+	return t, err
+}
+
+// Update update the name, service account, permissions and expiry of the api key.
+// 更新api key的名稱、服務帳號、權限及過期時間，key本身不會變更
+func (t APIKeyModel) Update(name string, userId int64, permissions []string, expiresAt string) (int64, error) {
+	return t.WithTx(t.Tx).Table(t.TableName).
+		Where("id", "=", t.Id).
+		Update(dialect.H{
+			"name":        name,
+			"user_id":     userId,
+			"permissions": strings.Join(permissions, ","),
+			"expires_at":  nullTime(expiresAt),
+			"updated_at":  time.Now().Format("2006-01-02 15:04:05"),
+		})
+}
+
+// Rotate replace the key of the api key, the old key is invalid immediately.
+// 更換api key，舊的key立即失效
+func (t APIKeyModel) Rotate(prefix, hash string) (APIKeyModel, error) {
+	_, err := t.WithTx(t.Tx).Table(t.TableName).
+		Where("id", "=", t.Id).
+		Update(dialect.H{
+			"key_prefix": prefix,
+			"key_hash":   hash,
+			"updated_at": time.Now().Format("2006-01-02 15:04:05"),
+		})
+	t.Prefix = prefix
+	t.Hash = hash
+	return t, err
+}
+
+// Touch record the last used time and ip of the api key, at most once per APIKeyTouchInterval.
+// 記錄api key最後使用的時間及IP，在APIKeyTouchInterval內只會更新一次
+func (t APIKeyModel) Touch(ip string) {
+	if t.LastUsedAt != "" && t.LastUsedIp == ip {
+		if last, err := time.ParseInLocation("2006-01-02 15:04:05", parseTimeValue(t.LastUsedAt), time.Local); err == nil &&
+			time.Since(last) < APIKeyTouchInterval {
+			return
+		}
+	}
+	_, _ = t.Table(t.TableName).
+		Where("id", "=", t.Id).
+		Update(dialect.H{
+			"last_used_at": time.Now().Format("2006-01-02 15:04:05"),
+			"last_used_ip": ip,
+		})
+}
+
+// GetPermissions return the permission models of the bound slugs.
+// 取得api key綁定的權限
+func (t APIKeyModel) GetPermissions() []PermissionModel {
+	if len(t.Permissions) == 0 {
+		return []PermissionModel{}
+	}
+	slugs := make([]interface{}, len(t.Permissions))
+	for i, slug := range t.Permissions {
+		slugs[i] = slug
+	}
+	items, _ := t.Table("goadmin_permissions").WhereIn("slug", slugs).All()
+	permissions := make([]PermissionModel, len(items))
+	for i, item := range items {
+		permissions[i] = Permission().MapToModel(item)
+	}
+	return permissions
+}
+
+// MapToModel get the api key model from given map.
+func (t APIKeyModel) MapToModel(m map[string]interface{}) APIKeyModel {
+	_ = db.ScanStruct(m, &t)
+	if permissions, _ := m["permissions"].(string); permissions != "" {
+		t.Permissions = strings.Split(permissions, ",")
+	}
+	return t
+}
+
+// 資料庫回傳的時間可能帶有時區或T，只取前19個字元
+func parseTimeValue(s string) string {
+	s = strings.Replace(s, "T", " ", 1)
+	if len(s) > 19 {
+		s = s[:19]
+	}
+	return s
+}
+
+// 空字串存成null
+func nullTime(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
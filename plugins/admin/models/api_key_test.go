@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyModel_IsExpired(t *testing.T) {
+	assert.Equal(t, APIKeyModel{}.IsExpired(), false)
+	assert.Equal(t, APIKeyModel{ExpiresAt: time.Now().Add(-time.Minute).Format("2006-01-02 15:04:05")}.IsExpired(), true)
+	assert.Equal(t, APIKeyModel{ExpiresAt: time.Now().Add(time.Hour).Format("2006-01-02T15:04:05Z07:00")}.IsExpired(), false)
+	assert.Equal(t, APIKeyModel{ExpiresAt: "wrong"}.IsExpired(), true)
+}
+
+func TestUserModel_WithAPIKey(t *testing.T) {
+	var (
+		dashboard = PermissionModel{Id: 1, Slug: "dashboard", HttpMethod: []string{""}, HttpPath: []string{"/"}}
+		users     = PermissionModel{Id: 2, Slug: "users", HttpMethod: []string{""}, HttpPath: []string{"/info/users"}}
+		all       = PermissionModel{Id: 3, Slug: "*", HttpMethod: []string{""}, HttpPath: []string{"*"}}
+		noSecret  = PermissionModel{Id: 4, Slug: "no-secret", HttpMethod: []string{""},
+			HttpPath: []string{"/info/posts", "!/info/secret"}}
+	)
+
+	// the key can not have more permissions than the account
+	user := UserModel{Permissions: []PermissionModel{dashboard}}.WithAPIKey(1, []PermissionModel{dashboard, users, all})
+	assert.Equal(t, user.Permissions, []PermissionModel{dashboard})
+	assert.Equal(t, user.IsSuperAdmin(), false)
+
+	// the super admin account has every permission
+	user = UserModel{Permissions: []PermissionModel{all}}.WithAPIKey(1, []PermissionModel{dashboard, users})
+	assert.Equal(t, user.Permissions, []PermissionModel{dashboard, users})
+
+	// the deny paths of the account are kept
+	user = UserModel{Permissions: []PermissionModel{dashboard, noSecret}}.WithAPIKey(1, []PermissionModel{dashboard})
+	assert.Equal(t, len(user.Permissions), 2)
+	assert.Equal(t, user.Permissions[1].HttpPath, []string{"!/info/secret"})
+}
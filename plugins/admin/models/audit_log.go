@@ -0,0 +1,227 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// The operations of the audit log.
+const (
+	AuditInsert  = "insert"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+
+	AuditImpersonate     = "login_as"
+	AuditExitImpersonate = "logout_as"
+)
+
+// AuditMaskFields are the fields whose values are masked in the audit log.
+// 變更紀錄中不記錄實際值的欄位，只記錄是否有變更
+var AuditMaskFields = []string{"password", "password_again", "remember_token"}
+
+const auditMask = "******"
+
+// AuditLogModel is the structured change record of the table writes.
+// 資料表寫入的變更紀錄，包含變更前後的資料及欄位差異
+type AuditLogModel struct {
+	Base
+
+	Id          int64
+	UserId      int64
+	TargetTable string
+	RecordId    string
+	Operation   string
+	OldValues   string
+	NewValues   string
+	Diff        string
+	Ip          string
+
+	CreatedAt string
+	UpdatedAt string
+
+	redact []string
+}
+
+// AuditChange is the old and new value of a field.
+type AuditChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// AuditLog return a default audit log model.
+func AuditLog() AuditLogModel {
+	return AuditLogModel{Base: Base{TableName: "goadmin_audit_log"}}
+}
+
+func (t AuditLogModel) SetConn(con db.Connection) AuditLogModel {
+	t.Conn = con
+	return t
+}
+
+func (t AuditLogModel) WithTx(tx *sql.Tx) AuditLogModel {
+	t.Tx = tx
+	return t
+}
+
+// Redact set the fields which are masked besides the AuditMaskFields.
+// 設置除了AuditMaskFields外也不記錄實際值的欄位
+func (t AuditLogModel) Redact(fields ...string) AuditLogModel {
+	t.redact = fields
+	return t
+}
+
+// New add an audit log, the old or new row is nil when inserting or deleting.
+// 新增一筆變更紀錄，新增資料時old為nil，刪除資料時new為nil
+func (t AuditLogModel) New(userId int64, table, recordId, operation, ip string, old, new map[string]interface{}) (AuditLogModel, error) {
+	old, new = AuditValues(old), AuditValues(new)
+
+	// 先比較差異再遮蔽敏感欄位，遮蔽的欄位只記錄有變更
+	diff := AuditDiff(old, new)
+	for i := range diff {
+		if auditMasked(diff[i].Field, t.redact) {
+			diff[i].Old, diff[i].New = auditMaskValue(diff[i].Old), auditMaskValue(diff[i].New)
+		}
+	}
+	auditMaskRow(old, t.redact)
+	auditMaskRow(new, t.redact)
+
+	oldJSON, _ := json.Marshal(old)
+	newJSON, _ := json.Marshal(new)
+	diffJSON, _ := json.Marshal(diff)
+
+	id, err := t.Table(t.TableName).WithTx(t.Tx).Insert(dialect.H{
+		"user_id":      userId,
+		"target_table": table,
+		"record_id":    recordId,
+		"operation":    operation,
+		"old_values":   string(oldJSON),
+		"new_values":   string(newJSON),
+		"diff":         string(diffJSON),
+		"ip":           ip,
+	})
+
+	t.Id = id
+	t.UserId = userId
+	t.TargetTable = table
+	t.RecordId = recordId
+	t.Operation = operation
+	t.OldValues = string(oldJSON)
+	t.NewValues = string(newJSON)
+	t.Diff = string(diffJSON)
+	t.Ip = ip
+
+	return t, err
+}
+
+// FindByRecord return the audit logs of the record, the latest first.
+// 回傳某筆資料的所有變更紀錄，依時間由新到舊排序
+func (t AuditLogModel) FindByRecord(table, recordId string) []AuditLogModel {
+	items, _ := t.Table(t.TableName).
+		Where("target_table", "=", table).
+		Where("record_id", "=", recordId).
+		OrderBy("id", "desc").
+		All()
+	list := make([]AuditLogModel, len(items))
+	for i, item := range items {
+		list[i] = t.MapToModel(item)
+	}
+	return list
+}
+
+// MapToModel get the audit log model from given map.
+func (t AuditLogModel) MapToModel(m map[string]interface{}) AuditLogModel {
+	_ = db.ScanStruct(m, &t)
+	return t
+}
+
+// AuditValues convert the row to the json friendly values.
+// 將資料轉換成可以json編碼的值
+func AuditValues(row map[string]interface{}) map[string]interface{} {
+	if row == nil {
+		return nil
+	}
+	values := make(map[string]interface{}, len(row))
+	for key, value := range row {
+		switch v := value.(type) {
+		case []byte:
+			value = string(v)
+		case time.Time:
+			value = v.Format("2006-01-02 15:04:05")
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// AuditDiff return the changed fields between the old and new row ordered by the field name.
+// 比較變更前後的資料，回傳有變更的欄位(依欄位名稱排序)
+func AuditDiff(old, new map[string]interface{}) []AuditChange {
+	var (
+		fields  = make([]string, 0)
+		changes = make([]AuditChange, 0)
+	)
+	for key := range old {
+		fields = append(fields, key)
+	}
+	for key := range new {
+		if _, ok := old[key]; !ok {
+			fields = append(fields, key)
+		}
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		oldValue, newValue := old[field], new[field]
+		if old != nil && new != nil && auditString(oldValue) == auditString(newValue) {
+			continue
+		}
+		changes = append(changes, AuditChange{Field: field, Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+func auditString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+func auditMaskValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return auditMask
+}
+
+func auditMasked(field string, redact []string) bool {
+	for _, f := range AuditMaskFields {
+		if f == field {
+			return true
+		}
+	}
+	for _, f := range redact {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// 遮蔽資料中的敏感欄位
+func auditMaskRow(row map[string]interface{}, redact []string) {
+	for key := range row {
+		if auditMasked(key, redact) {
+			row[key] = auditMaskValue(row[key])
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditDiff(t *testing.T) {
+	old := AuditValues(map[string]interface{}{
+		"id":         int64(1),
+		"name":       []byte("jack"),
+		"salary":     int64(1000),
+		"updated_at": time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC),
+	})
+	assert.Equal(t, old["name"], "jack")
+	assert.Equal(t, old["updated_at"], "2026-10-18 10:00:00")
+
+	updated := AuditValues(map[string]interface{}{
+		"id":         int64(1),
+		"name":       "jack",
+		"salary":     "1200",
+		"updated_at": time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC),
+	})
+	assert.Equal(t, AuditDiff(old, updated), []AuditChange{{Field: "salary", Old: int64(1000), New: "1200"}})
+	assert.Equal(t, len(AuditDiff(old, old)), 0)
+
+	// insert and delete record all the fields
+	assert.Equal(t, AuditDiff(nil, map[string]interface{}{"name": "jack", "avatar": nil}), []AuditChange{
+		{Field: "avatar", Old: nil, New: nil},
+		{Field: "name", Old: nil, New: "jack"},
+	})
+	assert.Equal(t, AuditDiff(map[string]interface{}{"name": "jack"}, nil), []AuditChange{
+		{Field: "name", Old: "jack", New: nil},
+	})
+}
@@ -7,7 +7,7 @@ import (
 
 // Base is base model structure.
 type Base struct {
-	TableName string
+	TableName string `db:"-"`
 
 	Conn db.Connection
 	Tx   *sql.Tx
@@ -19,10 +19,10 @@ func (b Base) SetConn(con db.Connection) Base {
 	return b
 }
 
-// 藉由給定的table回傳sql(struct)
+// 藉由給定的table回傳sql(struct)，管理後台的資料(用戶、角色、權限、登入狀態等)在主資料庫讀取，避免讀取副本尚未同步
 func (b Base) Table(table string) *db.SQL {
 	// Table在modules/db/statement.go中
 	// Table藉由給定的table回傳sql(struct)
 	// WithDriver藉由給定的conn回傳sql(struct)
-	return db.Table(table).WithDriver(b.Conn)
+	return db.Table(table).WithDriver(b.Conn).UsePrimary()
 }
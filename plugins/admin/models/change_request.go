@@ -0,0 +1,268 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// The status of the change request.
+const (
+	ChangePending  = "pending"
+	ChangeApproved = "approved"
+	ChangeRejected = "rejected"
+)
+
+// ErrChangeReviewed is returned when the change request is not pending anymore.
+var ErrChangeReviewed = errors.New("the change request has been reviewed")
+
+// ChangeRequestModel is the pending write of a table which needs approval, the new values
+// are the submitted form values and the old values are the row before the change.
+// 需要審核的資料表寫入，NewValues為提交的表單值，OldValues為申請時的資料，Permission為審核者需要的權限(slug)
+type ChangeRequestModel struct {
+	Base
+
+	Id          int64
+	Prefix      string
+	TargetTable string
+	RecordId    string
+	Operation   string
+	Permission  string
+	OldValues   string
+	NewValues   string
+	UserId      int64
+	Status      string
+	ReviewerId  int64
+	Comment     string
+	ReviewedAt  string
+
+	CreatedAt string
+	UpdatedAt string
+
+	redact []string
+}
+
+// ChangeRequest return a default change request model.
+func ChangeRequest() ChangeRequestModel {
+	return ChangeRequestModel{Base: Base{TableName: "goadmin_change_requests"}}
+}
+
+func (t ChangeRequestModel) SetConn(con db.Connection) ChangeRequestModel {
+	t.Conn = con
+	return t
+}
+
+func (t ChangeRequestModel) WithTx(tx *sql.Tx) ChangeRequestModel {
+	t.Tx = tx
+	return t
+}
+
+// Redact set the fields which are masked besides the AuditMaskFields.
+// 設置除了AuditMaskFields外也不顯示實際值的欄位
+func (t ChangeRequestModel) Redact(fields ...string) ChangeRequestModel {
+	t.redact = fields
+	return t
+}
+
+// Find return the change request model of the given id.
+func (t ChangeRequestModel) Find(id interface{}) ChangeRequestModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the model is empty or not.
+func (t ChangeRequestModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// IsPending check the change request is waiting for approval or not.
+func (t ChangeRequestModel) IsPending() bool {
+	return t.Status == ChangePending
+}
+
+// New add a pending change request.
+// 新增一筆待審核的變更申請
+func (t ChangeRequestModel) New(userId int64, prefix, table, recordId, operation, permission string,
+	old map[string]interface{}, new map[string][]string) (ChangeRequestModel, error) {
+
+	oldValues, newValues := "", ""
+	if old != nil {
+		// 申請時的資料只用於顯示差異，不記錄敏感欄位的實際值
+		old = AuditValues(old)
+		auditMaskRow(old, t.redact)
+		b, _ := json.Marshal(old)
+		oldValues = string(b)
+	}
+	if new != nil {
+		b, _ := json.Marshal(new)
+		newValues = string(b)
+	}
+
+	id, err := t.Table(t.TableName).WithTx(t.Tx).Insert(dialect.H{
+		"prefix":       prefix,
+		"target_table": table,
+		"record_id":    recordId,
+		"operation":    operation,
+		"permission":   permission,
+		"old_values":   oldValues,
+		"new_values":   newValues,
+		"user_id":      userId,
+		"status":       ChangePending,
+	})
+
+	t.Id = id
+	t.Prefix = prefix
+	t.TargetTable = table
+	t.RecordId = recordId
+	t.Operation = operation
+	t.Permission = permission
+	t.OldValues = oldValues
+	t.NewValues = newValues
+	t.UserId = userId
+	t.Status = ChangePending
+
+	return t, err
+}
+
+// Review set the status of the pending change request, ErrChangeReviewed is returned if the
+// change request has been reviewed by others.
+// 審核變更申請，只有待審核的申請可以變更狀態，已被審核時回傳ErrChangeReviewed
+func (t ChangeRequestModel) Review(status string, reviewerId int64, comment string) (ChangeRequestModel, error) {
+	now := time.Now().Format("2006-01-02 15:04:05")
+	_, err := t.Table(t.TableName).WithTx(t.Tx).
+		Where("id", "=", t.Id).
+		Where("status", "=", ChangePending).
+		Update(dialect.H{
+			"status":      status,
+			"reviewer_id": reviewerId,
+			"comment":     comment,
+			"reviewed_at": now,
+			"updated_at":  now,
+		})
+	if db.CheckError(err, db.UPDATE) {
+		return t, err
+	}
+	if err != nil {
+		return t, ErrChangeReviewed
+	}
+	t.Status = status
+	t.ReviewerId = reviewerId
+	t.Comment = comment
+	t.ReviewedAt = now
+	return t, nil
+}
+
+// Reopen set the approved change request back to pending when failed to apply it.
+// 套用變更失敗時將申請改回待審核
+func (t ChangeRequestModel) Reopen() error {
+	_, err := t.Table(t.TableName).WithTx(t.Tx).
+		Where("id", "=", t.Id).
+		Update(dialect.H{
+			"status":      ChangePending,
+			"reviewer_id": 0,
+			"comment":     "",
+			"reviewed_at": nil,
+		})
+	return err
+}
+
+// GetNewValues return the submitted form values of the change request.
+// 取得申請時提交的表單值
+func (t ChangeRequestModel) GetNewValues() map[string][]string {
+	values := make(map[string][]string)
+	if t.NewValues != "" {
+		_ = json.Unmarshal([]byte(t.NewValues), &values)
+	}
+	return values
+}
+
+// GetOldValues return the row before the change.
+func (t ChangeRequestModel) GetOldValues() map[string]interface{} {
+	values := make(map[string]interface{})
+	if t.OldValues != "" {
+		decoder := json.NewDecoder(strings.NewReader(t.OldValues))
+		decoder.UseNumber()
+		_ = decoder.Decode(&values)
+	}
+	return values
+}
+
+// Diff return the changed fields of the change request, the sensitive fields are masked.
+// 比較申請時的資料及提交的值，更新時只比較有提交的欄位，敏感欄位不顯示實際值
+func (t ChangeRequestModel) Diff() []AuditChange {
+	var old, new map[string]interface{}
+	if t.Operation != AuditInsert {
+		old = t.GetOldValues()
+	}
+	if t.Operation != AuditDelete {
+		new = changeFormValues(t.GetNewValues())
+	}
+	if t.Operation == AuditUpdate {
+		for key := range old {
+			if _, ok := new[key]; !ok {
+				delete(old, key)
+			}
+		}
+	}
+	diff := AuditDiff(old, new)
+	for i := range diff {
+		if auditMasked(diff[i].Field, t.redact) {
+			diff[i].Old, diff[i].New = auditMaskValue(diff[i].Old), auditMaskValue(diff[i].New)
+		}
+	}
+	return diff
+}
+
+// 將提交的表單值轉換成比較用的值，忽略內部參數(__開頭)
+func changeFormValues(values map[string][]string) map[string]interface{} {
+	res := make(map[string]interface{})
+	for key, value := range values {
+		if strings.HasPrefix(key, "__") || len(value) == 0 {
+			continue
+		}
+		res[strings.TrimSuffix(key, "[]")] = strings.Join(value, ",")
+	}
+	return res
+}
+
+// CanReview check the user can review the change request or not, the user who submits the
+// change request can not review it.
+// 檢查用戶是否可以審核，需要有審核權限(或為超級管理員)且不是申請者本人
+func (t ChangeRequestModel) CanReview(user UserModel) bool {
+	return t.IsPending() && t.UserId != user.Id &&
+		(user.IsSuperAdmin() || user.CheckPermission(t.Permission))
+}
+
+// PendingCount return the count of the pending change requests which can be reviewed by the user.
+// 回傳用戶可以審核的待審核申請數量
+func (t ChangeRequestModel) PendingCount(user UserModel) int64 {
+	query := t.Table(t.TableName).
+		Where("status", "=", ChangePending).
+		Where("user_id", "!=", user.Id)
+	if !user.IsSuperAdmin() {
+		slugs := make([]interface{}, 0, len(user.Permissions))
+		for _, per := range user.Permissions {
+			slugs = append(slugs, per.Slug)
+		}
+		if len(slugs) == 0 {
+			return 0
+		}
+		query = query.WhereIn("permission", slugs)
+	}
+	count, err := query.Count()
+	if err != nil {
+		return 0
+	}
+	return count
+}
+
+// MapToModel get the change request model from given map.
+func (t ChangeRequestModel) MapToModel(m map[string]interface{}) ChangeRequestModel {
+	_ = db.ScanStruct(m, &t)
+	return t
+}
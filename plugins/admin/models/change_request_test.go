@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeRequestDiff(t *testing.T) {
+	change := ChangeRequestModel{
+		Operation: AuditUpdate,
+		OldValues: `{"id":1,"name":"jack","password":"old","salary":1000,"remark":"a"}`,
+		NewValues: `{"id":["1"],"name":["jack"],"password":["new"],"salary":["1200"],"tags[]":["a","b"],"__go_admin_t_":["x"]}`,
+	}
+
+	// only the submitted fields are compared, the internal parameters are ignored
+	assert.Equal(t, change.Diff(), []AuditChange{
+		{Field: "password", Old: auditMask, New: auditMask},
+		{Field: "salary", Old: json.Number("1000"), New: "1200"},
+		{Field: "tags", Old: nil, New: "a,b"},
+	})
+
+	// the fields redacted by the table are masked too
+	assert.Equal(t, change.Redact("salary").Diff()[1], AuditChange{Field: "salary", Old: auditMask, New: auditMask})
+
+	change.Operation = AuditInsert
+	change.OldValues = ""
+	change.NewValues = `{"name":["jack"]}`
+	assert.Equal(t, change.Diff(), []AuditChange{{Field: "name", Old: nil, New: "jack"}})
+
+	change.Operation = AuditDelete
+	change.OldValues = `{"name":"jack"}`
+	change.NewValues = ""
+	assert.Equal(t, change.Diff(), []AuditChange{{Field: "name", Old: "jack", New: nil}})
+}
+
+func TestChangeRequestCanReview(t *testing.T) {
+	approver := UserModel{Id: 2, Permissions: []PermissionModel{{Slug: "finance.approve"}}}
+	other := UserModel{Id: 3, Permissions: []PermissionModel{{Slug: "finance.view"}}}
+	super := UserModel{Id: 1, Permissions: []PermissionModel{{HttpPath: []string{"*"}, HttpMethod: []string{""}}}}
+
+	change := ChangeRequestModel{UserId: 1, Status: ChangePending, Permission: "finance.approve"}
+
+	assert.True(t, change.CanReview(approver))
+	assert.False(t, change.CanReview(other))
+	// the applicant can not review the change request of his own
+	assert.False(t, change.CanReview(super))
+
+	change.UserId = 2
+	assert.False(t, change.CanReview(approver))
+	assert.True(t, change.CanReview(super))
+
+	change.Status = ChangeApproved
+	assert.False(t, change.CanReview(super))
+}
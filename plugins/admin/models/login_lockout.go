@@ -0,0 +1,165 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+const (
+	// LockoutTargetUsername is the counter of the login username.
+	LockoutTargetUsername = "username"
+	// LockoutTargetIP is the counter of the client ip.
+	LockoutTargetIP = "ip"
+
+	// 指數退避的上限(秒)
+	maxLoginBackoff = 60
+	// 鎖定時間加倍的次數上限
+	maxLockoutDoubling = 10
+)
+
+// LoginLockoutModel is the failure counter of the login username or the client ip.
+// 登入失敗的計數，TargetType為username或ip
+type LoginLockoutModel struct {
+	Base
+
+	Id            int64
+	TargetType    string
+	Target        string
+	Failures      int64
+	LockTimes     int64
+	LastFailureAt int64
+	NextAttemptAt int64
+	LockedUntil   int64
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+// LoginLockout return a default login lockout model.
+func LoginLockout() LoginLockoutModel {
+	return LoginLockoutModel{Base: Base{TableName: "goadmin_login_lockout"}}
+}
+
+func (t LoginLockoutModel) SetConn(con db.Connection) LoginLockoutModel {
+	t.Conn = con
+	return t
+}
+
+func (t LoginLockoutModel) WithTx(tx *sql.Tx) LoginLockoutModel {
+	t.Tx = tx
+	return t
+}
+
+// Find return the counter of the given id.
+func (t LoginLockoutModel) Find(id interface{}) LoginLockoutModel {
+	item, _ := t.Table(t.TableName).Find(id)
+	return t.MapToModel(item)
+}
+
+// FindByTarget return the counter of the given target.
+// 透過參數targetType、target尋找符合的資料，不存在時回傳設置好target的空model
+func (t LoginLockoutModel) FindByTarget(targetType, target string) LoginLockoutModel {
+	item, _ := t.Table(t.TableName).
+		Where("target_type", "=", targetType).
+		Where("target", "=", target).
+		First()
+	m := t.MapToModel(item)
+	m.TargetType = targetType
+	m.Target = target
+	return m
+}
+
+// IsEmpty check the model is empty or not.
+func (t LoginLockoutModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// Wait return the duration before the next attempt is allowed, and whether the target is locked.
+// 回傳距離下次允許登入的時間，以及是否為鎖定狀態(反之為退避等待)
+func (t LoginLockoutModel) Wait(now time.Time) (time.Duration, bool) {
+	if t.LockedUntil > now.Unix() {
+		return time.Duration(t.LockedUntil-now.Unix()) * time.Second, true
+	}
+	if t.NextAttemptAt > now.Unix() {
+		return time.Duration(t.NextAttemptAt-now.Unix()) * time.Second, false
+	}
+	return 0, false
+}
+
+// AddFailure increase the failures, the target is locked when the failures reach maxFailures.
+// 增加失敗次數，超過window(秒)未失敗則重新計算；每次失敗後的等待時間以指數增加，
+// 達到maxFailures時鎖定lockoutTime(秒)，每次重複鎖定時間加倍
+func (t LoginLockoutModel) AddFailure(maxFailures, lockoutTime, window int, now time.Time) (LoginLockoutModel, error) {
+	t = t.addFailure(maxFailures, lockoutTime, window, now)
+
+	values := dialect.H{
+		"failures":        t.Failures,
+		"lock_times":      t.LockTimes,
+		"last_failure_at": t.LastFailureAt,
+		"next_attempt_at": t.NextAttemptAt,
+		"locked_until":    t.LockedUntil,
+	}
+
+	var err error
+	if t.IsEmpty() {
+		values["target_type"] = t.TargetType
+		values["target"] = t.Target
+		t.Id, err = t.WithTx(t.Tx).Table(t.TableName).Insert(values)
+	} else {
+		values["updated_at"] = now.Format("2006-01-02 15:04:05")
+		_, err = t.WithTx(t.Tx).Table(t.TableName).
+			Where("id", "=", t.Id).
+			Update(values)
+	}
+
+	return t, err
+}
+
+// 計算失敗後的計數、等待及鎖定時間
+func (t LoginLockoutModel) addFailure(maxFailures, lockoutTime, window int, now time.Time) LoginLockoutModel {
+	if t.LastFailureAt+int64(window) < now.Unix() {
+		t.Failures = 0
+	}
+
+	t.Failures++
+	t.LastFailureAt = now.Unix()
+
+	if maxFailures > 0 && t.Failures >= int64(maxFailures) {
+		doubling := t.LockTimes
+		if doubling > maxLockoutDoubling {
+			doubling = maxLockoutDoubling
+		}
+		t.LockTimes++
+		t.LockedUntil = now.Unix() + int64(lockoutTime)<<uint(doubling)
+		t.NextAttemptAt = 0
+		t.Failures = 0
+	} else {
+		backoff := int64(1) << uint(t.Failures-1)
+		if t.Failures > 7 || backoff > maxLoginBackoff {
+			backoff = maxLoginBackoff
+		}
+		t.NextAttemptAt = now.Unix() + backoff
+	}
+
+	return t
+}
+
+// Unlock reset the failures and remove the lockout.
+// 解除鎖定並重置失敗次數
+func (t LoginLockoutModel) Unlock() error {
+	if t.IsEmpty() {
+		return nil
+	}
+	return t.WithTx(t.Tx).Table(t.TableName).
+		Where("id", "=", t.Id).
+		Delete()
+}
+
+// MapToModel get the login lockout model from given map.
+func (t LoginLockoutModel) MapToModel(m map[string]interface{}) LoginLockoutModel {
+	_ = db.ScanStruct(m, &t)
+	return t
+}
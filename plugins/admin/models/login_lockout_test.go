@@ -0,0 +1,58 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLockoutAddFailure(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	m := LoginLockout()
+
+	// exponential backoff before the lockout
+	m = m.addFailure(3, 60, 3600, now)
+	assert.Equal(t, m.Failures, int64(1))
+	wait, locked := m.Wait(now)
+	assert.Equal(t, wait, time.Second)
+	assert.Equal(t, locked, false)
+
+	m = m.addFailure(3, 60, 3600, now)
+	wait, locked = m.Wait(now)
+	assert.Equal(t, wait, 2*time.Second)
+	assert.Equal(t, locked, false)
+
+	// locked after three failures
+	m = m.addFailure(3, 60, 3600, now)
+	wait, locked = m.Wait(now)
+	assert.Equal(t, wait, time.Minute)
+	assert.Equal(t, locked, true)
+	assert.Equal(t, m.Failures, int64(0))
+
+	wait, _ = m.Wait(now.Add(time.Minute))
+	assert.Equal(t, wait, time.Duration(0))
+
+	// the lockout time is doubled
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		m = m.addFailure(3, 60, 3600, now)
+	}
+	wait, locked = m.Wait(now)
+	assert.Equal(t, wait, 2*time.Minute)
+	assert.Equal(t, locked, true)
+
+	// the failures are reset out of the window
+	m = m.addFailure(3, 60, 3600, now.Add(3*time.Minute))
+	m = m.addFailure(3, 60, 3600, now.Add(2*time.Hour))
+	assert.Equal(t, m.Failures, int64(1))
+
+	// backoff only
+	m = LoginLockout()
+	for i := 0; i < 20; i++ {
+		m = m.addFailure(0, 60, 3600, now)
+	}
+	wait, locked = m.Wait(now)
+	assert.Equal(t, wait, time.Duration(maxLoginBackoff)*time.Second)
+	assert.Equal(t, locked, false)
+}
@@ -203,13 +203,6 @@ func (t MenuModel) DeleteRoles() error {
 
 // MapToModel get the menu model from given map.
 func (t MenuModel) MapToModel(m map[string]interface{}) MenuModel {
-	t.Id = m["id"].(int64)
-	t.Title, _ = m["title"].(string)
-	t.ParentId = m["parent_id"].(int64)
-	t.Icon, _ = m["icon"].(string)
-	t.Uri, _ = m["uri"].(string)
-	t.Header, _ = m["header"].(string)
-	t.CreatedAt, _ = m["created_at"].(string)
-	t.UpdatedAt, _ = m["updated_at"].(string)
+	_ = db.ScanStruct(m, &t)
 	return t
 }
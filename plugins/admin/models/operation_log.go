@@ -11,14 +11,15 @@ type OperationLogModel struct {
 	//Base(struct)在plugins\admin\models\base.go
 	Base
 
-	Id        int64
-	UserId    int64
-	Path      string
-	Method    string
-	Ip        string
-	Input     string
-	CreatedAt string
-	UpdatedAt string
+	Id             int64
+	UserId         int64
+	ImpersonatorId int64 // 以其他用戶身分登入時，原本登入的超級管理員
+	Path           string
+	Method         string
+	Ip             string
+	Input          string
+	CreatedAt      string
+	UpdatedAt      string
 }
 
 // OperationLog return a default operation log model.
@@ -46,6 +47,13 @@ func (t OperationLogModel) SetConn(con db.Connection) OperationLogModel {
 	return t
 }
 
+// SetImpersonator set the id of the super admin who impersonates the user.
+// 設置以用戶身分登入的超級管理員，操作紀錄會同時記錄兩個身分
+func (t OperationLogModel) SetImpersonator(id int64) OperationLogModel {
+	t.ImpersonatorId = id
+	return t
+}
+
 // New create a new operation log model.
 // 新增一筆使用者行為資料至資料表，回傳OperationLogModel(struct)
 func (t OperationLogModel) New(userId int64, path, method, ip, input string) OperationLogModel {
@@ -53,13 +61,17 @@ func (t OperationLogModel) New(userId int64, path, method, ip, input string) Ope
 	// 插入使用者行為資料
 	// OperationLogModel.Base.TableName
 	// Table藉由給定的參數(t.TableName)回傳sql(struct)
-	id, _ := t.Table(t.TableName).Insert(dialect.H{
+	values := dialect.H{
 		"user_id": userId,
 		"path":    path,
 		"method":  method,
 		"ip":      ip,
 		"input":   input,
-	})
+	}
+	if t.ImpersonatorId != 0 {
+		values["impersonator_id"] = t.ImpersonatorId
+	}
+	id, _ := t.Table(t.TableName).Insert(values)
 
 	t.Id = id
 	t.UserId = userId
@@ -74,13 +86,6 @@ func (t OperationLogModel) New(userId int64, path, method, ip, input string) Ope
 // MapToModel get the operation log model from given map.
 // 透過參數(m map[string]interface{})將資訊設置至OperationLogModel(struct)
 func (t OperationLogModel) MapToModel(m map[string]interface{}) OperationLogModel {
-	t.Id = m["id"].(int64)
-	t.UserId = m["user_id"].(int64)
-	t.Path, _ = m["path"].(string)
-	t.Method, _ = m["method"].(string)
-	t.Ip, _ = m["ip"].(string)
-	t.Input, _ = m["input"].(string)
-	t.CreatedAt, _ = m["created_at"].(string)
-	t.UpdatedAt, _ = m["updated_at"].(string)
+	_ = db.ScanStruct(m, &t)
 	return t
 }
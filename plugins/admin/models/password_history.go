@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// PasswordHistoryModel is the password history of the user.
+// 用戶密碼的修改紀錄(hash)，ChangedAt為修改的時間(unix)，用於判斷密碼重複及過期
+type PasswordHistoryModel struct {
+	Base
+
+	Id        int64
+	UserId    int64
+	Password  string
+	ChangedAt int64
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+// PasswordHistory return a default password history model.
+func PasswordHistory() PasswordHistoryModel {
+	return PasswordHistoryModel{Base: Base{TableName: "goadmin_password_history"}}
+}
+
+func (t PasswordHistoryModel) SetConn(con db.Connection) PasswordHistoryModel {
+	t.Conn = con
+	return t
+}
+
+func (t PasswordHistoryModel) WithTx(tx *sql.Tx) PasswordHistoryModel {
+	t.Tx = tx
+	return t
+}
+
+// New add a password history of the user, only the latest keep records are kept.
+// 新增一筆密碼紀錄，並只保留最近keep筆
+func (t PasswordHistoryModel) New(userId int64, password string, keep int) (PasswordHistoryModel, error) {
+	now := time.Now()
+	id, err := t.WithTx(t.Tx).Table(t.TableName).Insert(dialect.H{
+		"user_id":    userId,
+		"password":   password,
+		"changed_at": now.Unix(),
+	})
+	if err != nil {
+		return t, err
+	}
+
+	t.Id = id
+	t.UserId = userId
+	t.Password = password
+	t.ChangedAt = now.Unix()
+
+	if keep < 1 {
+		keep = 1
+	}
+	old := t.FindByUserId(userId)
+	if len(old) > keep {
+		ids := make([]interface{}, 0, len(old)-keep)
+		for _, item := range old[keep:] {
+			ids = append(ids, item.Id)
+		}
+		err = t.WithTx(t.Tx).Table(t.TableName).WhereIn("id", ids).Delete()
+	}
+
+	return t, err
+}
+
+// FindByUserId return the password histories of the user, the latest first.
+// 回傳用戶的密碼紀錄，依時間由新到舊排序
+func (t PasswordHistoryModel) FindByUserId(userId int64) []PasswordHistoryModel {
+	items, _ := t.Table(t.TableName).
+		Where("user_id", "=", userId).
+		OrderBy("id", "desc").
+		All()
+	list := make([]PasswordHistoryModel, len(items))
+	for i, item := range items {
+		list[i] = t.MapToModel(item)
+	}
+	return list
+}
+
+// MapToModel get the password history model from given map.
+func (t PasswordHistoryModel) MapToModel(m map[string]interface{}) PasswordHistoryModel {
+	_ = db.ScanStruct(m, &t)
+	return t
+}
@@ -1,11 +1,40 @@
 package models
 
 import (
+	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+// The effects of the permission on a request.
+const (
+	PermissionAllow = "allow"
+	PermissionDeny  = "deny"
+)
+
+// The prefixes of the http path of the permission:
+//
+//	!/info/manager/edit     deny the path, overrides the allowed paths of all the permissions
+//	glob:/info/*/edit       glob pattern, * matches a path segment and ** matches any paths
+//	regex:/info/(user|role) regular expression matches the whole path
+const (
+	PermissionDenyPrefix  = "!"
+	PermissionGlobPrefix  = "glob:"
+	PermissionRegexPrefix = "regex:"
+)
+
+// PermissionMethodSets are the named sets of the http methods.
+// 權限可以設置的http方法集合
+var PermissionMethodSets = map[string][]string{
+	"READ":  {"GET", "HEAD", "OPTIONS"},
+	"WRITE": {"POST", "PUT", "PATCH", "DELETE"},
+	"ANY":   {"GET", "HEAD", "OPTIONS", "POST", "PUT", "PATCH", "DELETE"},
+}
+
 // PermissionModel is permission model structure.
 type PermissionModel struct {
 	//plugins/admin/models/base.go中
@@ -76,9 +105,7 @@ func (t PermissionModel) FindByName(name string) PermissionModel {
 // MapToModel get the permission model from given map.
 // 將map設置至permission model
 func (t PermissionModel) MapToModel(m map[string]interface{}) PermissionModel {
-	t.Id = m["id"].(int64)
-	t.Name, _ = m["name"].(string)
-	t.Slug, _ = m["slug"].(string)
+	_ = db.ScanStruct(m, &t)
 
 	methods, _ := m["http_method"].(string)
 	if methods != "" {
@@ -89,7 +116,128 @@ func (t PermissionModel) MapToModel(m map[string]interface{}) PermissionModel {
 
 	path, _ := m["http_path"].(string)
 	t.HttpPath = strings.Split(path, "\n")
-	t.CreatedAt, _ = m["created_at"].(string)
-	t.UpdatedAt, _ = m["updated_at"].(string)
 	return t
 }
+
+// MatchMethod check whether the permission matches the http method, the empty methods match all.
+// 判斷權限是否包含該http方法，未設置方法表示所有方法
+func (t PermissionModel) MatchMethod(method string) bool {
+	if len(t.HttpMethod) == 0 || (len(t.HttpMethod) == 1 && t.HttpMethod[0] == "") {
+		return true
+	}
+	for _, m := range t.HttpMethod {
+		m = strings.TrimSpace(m)
+		if strings.EqualFold(m, method) {
+			return true
+		}
+		if set, ok := PermissionMethodSets[strings.ToUpper(m)]; ok && inMethodArr(set, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Effect return the effect(allow or deny) of the permission on the request, or "" when the
+// permission does not match the request.
+// 回傳權限對請求的作用，允許(allow)、拒絕(deny)，不匹配時回傳空值，拒絕優先於允許
+func (t PermissionModel) Effect(path, method string, params url.Values) string {
+	if !t.MatchMethod(method) {
+		return ""
+	}
+	effect := ""
+	for _, pattern := range t.HttpPath {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		deny := strings.HasPrefix(pattern, PermissionDenyPrefix)
+		if deny {
+			pattern = strings.TrimSpace(pattern[len(PermissionDenyPrefix):])
+		}
+		if !MatchPermissionPath(pattern, path, params) {
+			continue
+		}
+		if deny {
+			return PermissionDeny
+		}
+		effect = PermissionAllow
+	}
+	return effect
+}
+
+// IsDeny check whether the permission has the deny paths.
+func (t PermissionModel) IsDeny() bool {
+	for _, pattern := range t.HttpPath {
+		if strings.HasPrefix(strings.TrimSpace(pattern), PermissionDenyPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPermissionPath check whether the path(with global prefix) and params match the pattern
+// of the permission(without global prefix).
+// 判斷路徑及參數是否符合權限的路徑規則，支援完整路徑、glob及正規表示式
+func MatchPermissionPath(pattern, path string, params url.Values) bool {
+	if pattern == "*" {
+		return true
+	}
+
+	pattern, matchParam := getParam(pattern)
+
+	switch {
+	case strings.HasPrefix(pattern, PermissionGlobPrefix):
+		reg, err := regexp.Compile("^" + regexp.QuoteMeta(config.Url("")) +
+			globToRegexp(strings.TrimPrefix(pattern, PermissionGlobPrefix)) + "$")
+		if err != nil {
+			logger.Error("CheckPermissions error: ", err)
+			return false
+		}
+		return reg.MatchString(path) && checkParam(params, matchParam)
+	case strings.HasPrefix(pattern, PermissionRegexPrefix):
+		reg, err := regexp.Compile("^" + regexp.QuoteMeta(config.Url("")) +
+			"(?:" + strings.TrimPrefix(pattern, PermissionRegexPrefix) + ")$")
+		if err != nil {
+			logger.Error("CheckPermissions error: ", err)
+			return false
+		}
+		return reg.MatchString(path) && checkParam(params, matchParam)
+	}
+
+	// 相容原本的規則：完整路徑或以正規表示式比對
+	matchPath := config.Url(pattern)
+
+	if matchPath == path {
+		return checkParam(params, matchParam)
+	}
+
+	reg, err := regexp.Compile(matchPath)
+
+	if err != nil {
+		logger.Error("CheckPermissions error: ", err)
+		return false
+	}
+
+	return reg.FindString(path) == path && checkParam(params, matchParam)
+}
+
+// 將glob轉換成正規表示式，*匹配一層路徑，**匹配任意路徑，?匹配一個字元
+func globToRegexp(glob string) string {
+	var res strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				res.WriteString(".*")
+				i++
+			} else {
+				res.WriteString("[^/]*")
+			}
+		case '?':
+			res.WriteString("[^/]")
+		default:
+			res.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return res.String()
+}
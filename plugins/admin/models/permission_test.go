@@ -0,0 +1,52 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobToRegexp(t *testing.T) {
+	assert.Equal(t, globToRegexp("/info/*/edit"), "/info/[^/]*/edit")
+	assert.Equal(t, globToRegexp("/info/**"), "/info/.*")
+	assert.Equal(t, globToRegexp("/info/user?"), "/info/user[^/]")
+	assert.Equal(t, globToRegexp("/a.b"), `/a\.b`)
+}
+
+func TestMatchPermissionPath(t *testing.T) {
+	params := url.Values{}
+
+	assert.Equal(t, MatchPermissionPath("*", "/info/user", params), true)
+
+	assert.Equal(t, MatchPermissionPath("glob:/info/*", "/info/user", params), true)
+	assert.Equal(t, MatchPermissionPath("glob:/info/*", "/info/user/edit", params), false)
+	assert.Equal(t, MatchPermissionPath("glob:/info/**", "/info/user/edit", params), true)
+	assert.Equal(t, MatchPermissionPath("glob:/info/*/edit", "/info/role/edit", params), true)
+
+	assert.Equal(t, MatchPermissionPath("regex:/info/(user|role)", "/info/role", params), true)
+	assert.Equal(t, MatchPermissionPath("regex:/info/(user|role)", "/info/roles", params), false)
+
+	assert.Equal(t, MatchPermissionPath("/info/user", "/info/user", params), true)
+	assert.Equal(t, MatchPermissionPath("/info/user", "/info/user/edit", params), false)
+	assert.Equal(t, MatchPermissionPath("/info/user?id=1", "/info/user", url.Values{"id": {"1"}}), true)
+	assert.Equal(t, MatchPermissionPath("/info/user?id=1", "/info/user", url.Values{"id": {"2"}}), false)
+}
+
+func TestPermissionModel_Effect(t *testing.T) {
+	permission := PermissionModel{
+		HttpMethod: []string{"READ"},
+		HttpPath:   []string{"glob:/info/**", "!/info/manager"},
+	}
+
+	assert.Equal(t, permission.Effect("/info/user", "GET", url.Values{}), PermissionAllow)
+	assert.Equal(t, permission.Effect("/info/manager", "GET", url.Values{}), PermissionDeny)
+	assert.Equal(t, permission.Effect("/info/user", "POST", url.Values{}), "")
+	assert.Equal(t, permission.Effect("/menu", "GET", url.Values{}), "")
+	assert.Equal(t, permission.IsDeny(), true)
+
+	assert.Equal(t, PermissionModel{HttpMethod: []string{""}}.MatchMethod("DELETE"), true)
+	assert.Equal(t, PermissionModel{HttpMethod: []string{"WRITE"}}.MatchMethod("DELETE"), true)
+	assert.Equal(t, PermissionModel{HttpMethod: []string{"GET", "ANY"}}.MatchMethod("PATCH"), true)
+	assert.Equal(t, PermissionModel{HttpMethod: []string{"GET"}}.MatchMethod("POST"), false)
+}
@@ -2,12 +2,16 @@ package models
 
 import (
 	"database/sql"
+	"errors"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
 	"strconv"
 	"time"
 )
 
+// ErrRoleCycle is returned when the parent roles make an inheritance cycle.
+var ErrRoleCycle = errors.New("role inheritance cycle")
+
 // RoleModel is role model structure.
 type RoleModel struct {
 	//plugins/admin/models/base.go中
@@ -48,6 +52,20 @@ func (t RoleModel) Find(id interface{}) RoleModel {
 	return t.MapToModel(item)
 }
 
+// FindBySlug return a role model of given slug.
+func (t RoleModel) FindBySlug(slug string) RoleModel {
+	item, _ := t.Table(t.TableName).Where("slug", "=", slug).First()
+	if item == nil {
+		return t
+	}
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the role model is empty or not.
+func (t RoleModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
 // IsSlugExist check the row exist with given slug and id.
 func (t RoleModel) IsSlugExist(slug string, id string) bool {
 	if id == "" {
@@ -118,13 +136,163 @@ func (t RoleModel) AddPermission(permissionId string) (int64, error) {
 	return 0, nil
 }
 
+// ParentIds return the ids of the direct parent roles.
+// 回傳直接的上層角色id
+func (t RoleModel) ParentIds() []int64 {
+	return t.ParentGraph()[t.Id]
+}
+
+// AncestorIds return the ids of all the parent roles transitively.
+// 回傳所有上層角色id(包含上層角色的上層角色)
+func (t RoleModel) AncestorIds() []int64 {
+	return RoleAncestors(t.ParentGraph(), t.Id)
+}
+
+// ParentGraph return the parent role ids of every role.
+// 回傳所有角色的上層角色，key為角色id
+func (t RoleModel) ParentGraph() map[int64][]int64 {
+	graph := make(map[int64][]int64)
+	items, _ := t.Table("goadmin_role_parents").Select("role_id", "parent_id").All()
+	for _, item := range items {
+		roleId, _ := item["role_id"].(int64)
+		parentId, _ := item["parent_id"].(int64)
+		graph[roleId] = append(graph[roleId], parentId)
+	}
+	return graph
+}
+
+// CheckParents check the parent roles would not make an inheritance cycle.
+// 檢查設置的上層角色是否會造成循環繼承
+func (t RoleModel) CheckParents(parentIds []string) error {
+	parents := make([]int64, 0, len(parentIds))
+	for _, id := range parentIds {
+		if id == "" {
+			continue
+		}
+		parentId, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return err
+		}
+		parents = append(parents, parentId)
+	}
+	if RoleHasCycle(t.ParentGraph(), t.Id, parents) {
+		return ErrRoleCycle
+	}
+	return nil
+}
+
+// DeleteParents delete all the parent roles of role.
+func (t RoleModel) DeleteParents() error {
+	return t.WithTx(t.Tx).Table("goadmin_role_parents").
+		Where("role_id", "=", t.Id).
+		Delete()
+}
+
+// AddParent add the parent role to the role.
+func (t RoleModel) AddParent(parentId string) (int64, error) {
+	if parentId == "" {
+		return 0, nil
+	}
+	check, _ := t.Table("goadmin_role_parents").
+		Where("role_id", "=", t.Id).
+		Where("parent_id", "=", parentId).
+		First()
+	if check != nil {
+		return 0, nil
+	}
+	return t.WithTx(t.Tx).Table("goadmin_role_parents").
+		Insert(dialect.H{
+			"role_id":   t.Id,
+			"parent_id": parentId,
+		})
+}
+
+// EffectivePermissions return the permissions of the role and the inherited permissions.
+// 回傳角色本身及繼承自上層角色的所有權限
+func (t RoleModel) EffectivePermissions() (own []PermissionModel, inherited []PermissionModel) {
+	var (
+		ancestors = t.AncestorIds()
+		ids       = []interface{}{t.Id}
+		seen      = make(map[int64]bool)
+	)
+	for _, id := range ancestors {
+		ids = append(ids, id)
+	}
+
+	items, _ := t.Table("goadmin_role_permissions").
+		LeftJoin("goadmin_permissions", "goadmin_permissions.id", "=", "goadmin_role_permissions.permission_id").
+		WhereIn("role_id", ids).
+		Select("goadmin_role_permissions.role_id", "goadmin_permissions.http_method", "goadmin_permissions.http_path",
+			"goadmin_permissions.id", "goadmin_permissions.name", "goadmin_permissions.slug",
+			"goadmin_permissions.created_at", "goadmin_permissions.updated_at").
+		All()
+
+	// 角色本身的權限優先，重複的權限只回傳一次
+	for _, item := range items {
+		if roleId, _ := item["role_id"].(int64); roleId == t.Id && item["id"] != nil {
+			permission := Permission().MapToModel(item)
+			if !seen[permission.Id] {
+				seen[permission.Id] = true
+				own = append(own, permission)
+			}
+		}
+	}
+	for _, item := range items {
+		if item["id"] == nil {
+			continue
+		}
+		permission := Permission().MapToModel(item)
+		if !seen[permission.Id] {
+			seen[permission.Id] = true
+			inherited = append(inherited, permission)
+		}
+	}
+	return
+}
+
+// RoleAncestors return the ancestor role ids of the given roles in the parent graph,
+// the given roles are not included unless they are parents of each other.
+// 透過上層角色的關係回傳所有上層角色id
+func RoleAncestors(graph map[int64][]int64, ids ...int64) []int64 {
+	var (
+		ancestors = make([]int64, 0)
+		seen      = make(map[int64]bool)
+		queue     = make([]int64, 0, len(ids))
+	)
+	queue = append(queue, ids...)
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, parent := range graph[id] {
+			if !seen[parent] {
+				seen[parent] = true
+				ancestors = append(ancestors, parent)
+				queue = append(queue, parent)
+			}
+		}
+	}
+	return ancestors
+}
+
+// RoleHasCycle check whether setting the parents of the role makes an inheritance cycle.
+// 檢查將parents設為角色的上層角色後是否會循環繼承
+func RoleHasCycle(graph map[int64][]int64, id int64, parents []int64) bool {
+	for _, parent := range parents {
+		if parent == id {
+			return true
+		}
+	}
+	for _, ancestor := range RoleAncestors(graph, parents...) {
+		if ancestor == id {
+			return true
+		}
+	}
+	return false
+}
+
 // MapToModel get the role model from given map.
 // 將map設置至role model
 func (t RoleModel) MapToModel(m map[string]interface{}) RoleModel {
-	t.Id = m["id"].(int64)
-	t.Name, _ = m["name"].(string)
-	t.Slug, _ = m["slug"].(string)
-	t.CreatedAt, _ = m["created_at"].(string)
-	t.UpdatedAt, _ = m["updated_at"].(string)
+	_ = db.ScanStruct(m, &t)
 	return t
 }
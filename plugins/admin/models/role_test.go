@@ -0,0 +1,25 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoleAncestors(t *testing.T) {
+	// 4 -> 3 -> 1, 4 -> 2 -> 1
+	graph := map[int64][]int64{
+		2: {1},
+		3: {1},
+		4: {3, 2},
+	}
+
+	assert.Equal(t, RoleAncestors(graph, 4), []int64{3, 2, 1})
+	assert.Equal(t, RoleAncestors(graph, 2, 3), []int64{1})
+	assert.Equal(t, RoleAncestors(graph, 1), []int64{})
+
+	assert.Equal(t, RoleHasCycle(graph, 1, []int64{4}), true)
+	assert.Equal(t, RoleHasCycle(graph, 1, []int64{1}), true)
+	assert.Equal(t, RoleHasCycle(graph, 3, []int64{2}), false)
+	assert.Equal(t, RoleHasCycle(graph, 5, []int64{4}), false)
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+)
+
+// RowScopeModel is the row scope rule of the table generator.
+// 資料表(generator的prefix)的行級資料權限條件，綁定角色(RoleId)或權限(PermissionId)
+// Val可以是固定值(多個值以逗號分隔)或變數，例如{user.id}
+type RowScopeModel struct {
+	Base
+
+	Id           int64
+	RoleId       int64
+	PermissionId int64
+	Prefix       string
+	Field        string
+	Operator     string
+	Val          string
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+// RowScope return a default row scope model.
+func RowScope() RowScopeModel {
+	return RowScopeModel{Base: Base{TableName: "goadmin_row_scopes"}}
+}
+
+func (t RowScopeModel) SetConn(con db.Connection) RowScopeModel {
+	t.Conn = con
+	return t
+}
+
+func (t RowScopeModel) WithTx(tx *sql.Tx) RowScopeModel {
+	t.Tx = tx
+	return t
+}
+
+// FindByPrefix return the rules of the table generator.
+// 回傳該資料表的所有條件
+func (t RowScopeModel) FindByPrefix(prefix string) []RowScopeModel {
+	items, _ := t.Table(t.TableName).
+		Where("prefix", "=", prefix).
+		OrderBy("id", "asc").
+		All()
+	list := make([]RowScopeModel, len(items))
+	for i, item := range items {
+		list[i] = t.MapToModel(item)
+	}
+	return list
+}
+
+// MapToModel get the row scope model from given map.
+func (t RowScopeModel) MapToModel(m map[string]interface{}) RowScopeModel {
+	_ = db.ScanStruct(m, &t)
+	return t
+}
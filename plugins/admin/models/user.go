@@ -5,7 +5,6 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/config"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
-	"github.com/GoAdminGroup/go-admin/modules/logger"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"net/url"
 	"regexp"
@@ -21,7 +20,7 @@ type UserModel struct {
 
 	Id            int64             `json:"id"`
 	Name          string            `json:"name"`
-	UserName      string            `json:"user_name"`
+	UserName      string            `json:"user_name" db:"username"`
 	Password      string            `json:"password"`
 	Avatar        string            `json:"avatar"`
 	RememberToken string            `json:"remember_token"`
@@ -33,6 +32,14 @@ type UserModel struct {
 	Level         string            `json:"level"`
 	LevelName     string            `json:"level_name"`
 
+	// 以其他用戶身分登入時，原本登入的超級管理員
+	ImpersonatorId   int64  `json:"impersonator_id"`
+	ImpersonatorName string `json:"impersonator_name"`
+
+	// 以api key驗證時，api key綁定的權限(權限為其與帳號權限的交集)
+	APIKeyId          int64             `json:"api_key_id"`
+	APIKeyPermissions []PermissionModel `json:"-"`
+
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at"`
 }
@@ -109,6 +116,12 @@ func (t UserModel) IsSuperAdmin() bool {
 	return false
 }
 
+// IsImpersonated check whether the user is impersonated by a super admin.
+// 判斷是否為超級管理員以此用戶身分登入
+func (t UserModel) IsImpersonated() bool {
+	return t.ImpersonatorId != 0
+}
+
 // 藉由參數檢查權限，如果有權限回傳第一個參數(path)，反之回傳""
 func (t UserModel) GetCheckPermissionByUrlMethod(path, method string) string {
 	// 檢查權限(藉由url、method)
@@ -129,11 +142,6 @@ func (t UserModel) HideUserCenterEntrance() bool {
 // 檢查權限(藉由url、method)
 func (t UserModel) CheckPermissionByUrlMethod(path, method string, formParams url.Values) bool {
 
-	// 檢查是否為超級管理員
-	if t.IsSuperAdmin() {
-		return true
-	}
-
 	// 登出檢查
 	logoutCheck, _ := regexp.Compile(config.Url("/logout") + "(.*?)")
 
@@ -141,8 +149,20 @@ func (t UserModel) CheckPermissionByUrlMethod(path, method string, formParams ur
 		return true
 	}
 
+	// 兩步驟驗證設置只能設置自己的帳號，所有用戶皆可訪問
+	totpCheck, _ := regexp.Compile("^" + config.Url("/totp") + "(/|\\?|$)")
+
+	if totpCheck.MatchString(path) {
+		return true
+	}
+
+	// 以其他用戶身分登入時，可以隨時結束並回到原本的帳號
+	if t.IsImpersonated() && strings.Split(path, "?")[0] == config.Url("/impersonate/exit") {
+		return true
+	}
+
 	if path == "" {
-		return false
+		return t.IsSuperAdmin()
 	}
 
 	if path != "/" && path[len(path)-1] == '/' {
@@ -160,42 +180,18 @@ func (t UserModel) CheckPermissionByUrlMethod(path, method string, formParams ur
 		}
 	}
 
+	// 拒絕規則優先於所有允許規則(包含超級管理員)
+	allowed := t.IsSuperAdmin()
 	for _, v := range t.Permissions {
-
-		if v.HttpMethod[0] == "" || inMethodArr(v.HttpMethod, method) {
-
-			if v.HttpPath[0] == "*" {
-				return true
-			}
-
-			for i := 0; i < len(v.HttpPath); i++ {
-
-				matchPath := config.Url(strings.TrimSpace(v.HttpPath[i]))
-				matchPath, matchParam := getParam(matchPath)
-
-				if matchPath == path {
-					if checkParam(params, matchParam) {
-						return true
-					}
-				}
-
-				reg, err := regexp.Compile(matchPath)
-
-				if err != nil {
-					logger.Error("CheckPermissions error: ", err)
-					continue
-				}
-
-				if reg.FindString(path) == path {
-					if checkParam(params, matchParam) {
-						return true
-					}
-				}
-			}
+		switch v.Effect(path, method, params) {
+		case PermissionDeny:
+			return false
+		case PermissionAllow:
+			allowed = true
 		}
 	}
 
-	return false
+	return allowed
 }
 
 // 取得參數
@@ -301,6 +297,126 @@ func (t UserModel) GetAllRoleId() []interface{} {
 	return ids
 }
 
+// GetAllRoleIdWithAncestors return the role ids of the user and all their parent roles.
+// 取得該用戶的role_id及所有繼承的上層角色id
+func (t UserModel) GetAllRoleIdWithAncestors() []interface{} {
+	var (
+		ids     = t.GetAllRoleId()
+		roleIds = make([]int64, len(t.Roles))
+		exist   = make(map[int64]bool)
+	)
+	if len(ids) == 0 {
+		return ids
+	}
+	for key, role := range t.Roles {
+		roleIds[key] = role.Id
+		exist[role.Id] = true
+	}
+	graph := Role().SetConn(t.Conn).ParentGraph()
+	for _, id := range RoleAncestors(graph, roleIds...) {
+		if !exist[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// RoleSlugsWithAncestors return the slugs of every role of the user and its parent roles,
+// the key is the role id.
+// 回傳用戶每個角色及其所有上層角色的slug，key為角色id
+func (t UserModel) RoleSlugsWithAncestors() map[int64][]string {
+	var (
+		res   = make(map[int64][]string, len(t.Roles))
+		slugs = make(map[int64]string)
+	)
+	if len(t.Roles) == 0 {
+		return res
+	}
+	items, _ := t.Table("goadmin_roles").Select("id", "slug").All()
+	for _, item := range items {
+		id, _ := item["id"].(int64)
+		slugs[id], _ = item["slug"].(string)
+	}
+	graph := Role().SetConn(t.Conn).ParentGraph()
+	for _, role := range t.Roles {
+		res[role.Id] = []string{role.Slug}
+		for _, id := range RoleAncestors(graph, role.Id) {
+			res[role.Id] = append(res[role.Id], slugs[id])
+		}
+	}
+	return res
+}
+
+// WithoutRoles remove the given roles of the user and query the permissions and menus again.
+// 移除用戶的角色後重新取得權限及可用menu
+func (t UserModel) WithoutRoles(ids ...int64) UserModel {
+	roles := make([]RoleModel, 0, len(t.Roles))
+	for _, role := range t.Roles {
+		removed := false
+		for _, id := range ids {
+			if role.Id == id {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			roles = append(roles, role)
+		}
+	}
+	t.Roles = roles
+	t.Level, t.LevelName = "", ""
+	if len(t.Roles) > 0 {
+		t.Level = t.Roles[0].Slug
+		t.LevelName = t.Roles[0].Name
+	}
+	t.Permissions = nil
+	t.MenuIds = nil
+	return t.WithPermissions().WithMenus()
+}
+
+// WithAPIKey limit the permissions of the user to the permissions bound to the api key which the
+// user also has, the limit is kept when the permissions are queried again.
+// 以api key驗證時，將權限限制為api key綁定的權限與帳號權限的交集(重新查詢權限後仍然有效)
+func (t UserModel) WithAPIKey(id int64, permissions []PermissionModel) UserModel {
+	t.APIKeyId = id
+	t.APIKeyPermissions = permissions
+	t.Permissions = limitAPIKeyPermissions(t.Permissions, permissions)
+	return t
+}
+
+// limitAPIKeyPermissions return the permissions of the key which the account also has(the super
+// admin has every permission), the deny paths of the account are kept as well.
+// 回傳帳號也擁有的api key權限，並保留帳號的拒絕規則
+func limitAPIKeyPermissions(permissions, keyPermissions []PermissionModel) []PermissionModel {
+	var (
+		res        = make([]PermissionModel, 0, len(keyPermissions))
+		superAdmin = UserModel{Permissions: permissions}.IsSuperAdmin()
+		owned      = make(map[int64]bool, len(permissions))
+	)
+	for _, permission := range permissions {
+		owned[permission.Id] = true
+	}
+	for _, permission := range keyPermissions {
+		if superAdmin || owned[permission.Id] {
+			res = append(res, permission)
+		}
+	}
+	for _, permission := range permissions {
+		if !permission.IsDeny() {
+			continue
+		}
+		deny := permission
+		deny.HttpPath = make([]string, 0)
+		for _, pattern := range permission.HttpPath {
+			if strings.HasPrefix(strings.TrimSpace(pattern), PermissionDenyPrefix) {
+				deny.HttpPath = append(deny.HttpPath, pattern)
+			}
+		}
+		res = append(res, deny)
+	}
+	return res
+}
+
 // WithPermissions query the permission info of the user.
 // 查詢user的permission
 func (t UserModel) WithPermissions() UserModel {
@@ -308,7 +424,8 @@ func (t UserModel) WithPermissions() UserModel {
 	var permissions = make([]map[string]interface{}, 0)
 
 	//可能會有多個role id(可以設定多個role)
-	roleIds := t.GetAllRoleId()
+	//包含繼承的上層角色，上層角色的權限會傳遞給下層角色
+	roleIds := t.GetAllRoleIdWithAncestors()
 
 	//----------------------------------------------------------------------------------------------
 	// permission會依照user_id以及role_id取得不同的權限，因此需要做下列兩次判斷
@@ -362,6 +479,10 @@ func (t UserModel) WithPermissions() UserModel {
 		t.Permissions = append(t.Permissions, Permission().MapToModel(permissions[i]))
 	}
 
+	if t.APIKeyId != 0 {
+		t.Permissions = limitAPIKeyPermissions(t.Permissions, t.APIKeyPermissions)
+	}
+
 	return t
 }
 
@@ -386,8 +507,8 @@ func (t UserModel) WithMenus() UserModel {
 		// 取得該user的role_id
 		// LeftJoin、WhereIn、Select、All都在modules/db/statement.go中
 		// 總共取得menu_id, parent_id兩個欄位
-		// 取得menuIdsModel藉由role_id
-		rolesId := t.GetAllRoleId()
+		// 取得menuIdsModel藉由role_id(包含繼承的上層角色)
+		rolesId := t.GetAllRoleIdWithAncestors()
 		if len(rolesId) > 0 {
 			menuIdsModel, _ = t.Table("goadmin_role_menu").
 				LeftJoin("goadmin_menu", "goadmin_menu.id", "=", "goadmin_role_menu.menu_id").
@@ -555,13 +676,6 @@ func (t UserModel) AddPermission(permissionId string) (int64, error) {
 // MapToModel get the user model from given map.
 // 設置user model從map中
 func (t UserModel) MapToModel(m map[string]interface{}) UserModel {
-	t.Id, _ = m["id"].(int64)
-	t.Name, _ = m["name"].(string)
-	t.UserName, _ = m["username"].(string)
-	t.Password, _ = m["password"].(string)
-	t.Avatar, _ = m["avatar"].(string)
-	t.RememberToken, _ = m["remember_token"].(string)
-	t.CreatedAt, _ = m["created_at"].(string)
-	t.UpdatedAt, _ = m["updated_at"].(string)
+	_ = db.ScanStruct(m, &t)
 	return t
 }
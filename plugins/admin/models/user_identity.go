@@ -0,0 +1,82 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// UserIdentityModel is the link between the user and the identity of the external service.
+// 用戶與外部驗證(LDAP、OIDC)身分的綁定，Provider為驗證來源(例如ldap、oidc:google)，Subject為該來源中不變的唯一識別(LDAP DN、OIDC sub)
+type UserIdentityModel struct {
+	Base
+
+	Id       int64
+	UserId   int64
+	Provider string
+	Subject  string
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+// UserIdentity return a default user identity model.
+func UserIdentity() UserIdentityModel {
+	return UserIdentityModel{Base: Base{TableName: "goadmin_user_identities"}}
+}
+
+func (t UserIdentityModel) SetConn(con db.Connection) UserIdentityModel {
+	t.Conn = con
+	return t
+}
+
+func (t UserIdentityModel) WithTx(tx *sql.Tx) UserIdentityModel {
+	t.Tx = tx
+	return t
+}
+
+// FindBySubject return the identity of the given provider and subject.
+// 透過參數provider、subject尋找符合的資料
+func (t UserIdentityModel) FindBySubject(provider, subject string) UserIdentityModel {
+	item, _ := t.Table(t.TableName).
+		Where("provider", "=", provider).
+		Where("subject", "=", subject).
+		First()
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the model is empty or not.
+func (t UserIdentityModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// Link bind the identity to the user.
+// 將外部身分綁定至用戶
+func (t UserIdentityModel) Link(userId int64, provider, subject string) (UserIdentityModel, error) {
+	id, err := t.WithTx(t.Tx).Table(t.TableName).Insert(dialect.H{
+		"user_id":  userId,
+		"provider": provider,
+		"subject":  subject,
+	})
+	t.Id = id
+	t.UserId = userId
+	t.Provider = provider
+	t.Subject = subject
+	return t, err
+}
+
+// Delete remove the identity.
+// 刪除綁定
+func (t UserIdentityModel) Delete() error {
+	return t.WithTx(t.Tx).Table(t.TableName).
+		Where("id", "=", t.Id).
+		Delete()
+}
+
+// MapToModel get the identity model from given map.
+// 將map設置至UserIdentityModel
+func (t UserIdentityModel) MapToModel(m map[string]interface{}) UserIdentityModel {
+	_ = db.ScanStruct(m, &t)
+	return t
+}
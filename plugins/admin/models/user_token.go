@@ -0,0 +1,81 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// UserTokenVersionModel is the version of the bearer tokens of the user.
+// 用戶bearer token的版本，token中記錄簽發時的版本，版本增加後舊的token全部失效
+type UserTokenVersionModel struct {
+	Base
+
+	Id      int64
+	UserId  int64
+	Version int64
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+// UserTokenVersion return a default user token version model.
+func UserTokenVersion() UserTokenVersionModel {
+	return UserTokenVersionModel{Base: Base{TableName: "goadmin_user_token_versions"}}
+}
+
+func (t UserTokenVersionModel) SetConn(con db.Connection) UserTokenVersionModel {
+	t.Conn = con
+	return t
+}
+
+func (t UserTokenVersionModel) WithTx(tx *sql.Tx) UserTokenVersionModel {
+	t.Tx = tx
+	return t
+}
+
+// FindByUserId return the token version of the given user, the version is 0 if not exist.
+// 透過參數userId尋找符合的資料，不存在時回傳設置好userId的空model(版本為0)
+func (t UserTokenVersionModel) FindByUserId(userId int64) UserTokenVersionModel {
+	item, _ := t.Table(t.TableName).
+		Where("user_id", "=", userId).
+		First()
+	m := t.MapToModel(item)
+	m.UserId = userId
+	return m
+}
+
+// IsEmpty check the model is empty or not.
+func (t UserTokenVersionModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// Bump increase the version, which revokes all the issued tokens of the user.
+// 版本加一，撤銷用戶所有已簽發的token
+func (t UserTokenVersionModel) Bump() (UserTokenVersionModel, error) {
+	var err error
+	t.Version++
+	if t.IsEmpty() {
+		t.Id, err = t.WithTx(t.Tx).Table(t.TableName).Insert(dialect.H{
+			"user_id": t.UserId,
+			"version": t.Version,
+		})
+	} else {
+		_, err = t.WithTx(t.Tx).Table(t.TableName).
+			Where("id", "=", t.Id).
+			Update(dialect.H{
+				"version":    t.Version,
+				"updated_at": time.Now().Format("2006-01-02 15:04:05"),
+			})
+	}
+	return t, err
+}
+
+// MapToModel get the token version model from given map.
+// 將map設置至UserTokenVersionModel
+func (t UserTokenVersionModel) MapToModel(m map[string]interface{}) UserTokenVersionModel {
+	_ = db.ScanStruct(m, &t)
+	return t
+}
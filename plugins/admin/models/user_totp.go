@@ -0,0 +1,143 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+)
+
+// UserTOTPModel is the two-factor authentication model of the user.
+// 用戶的兩步驟驗證設置，Enabled為false時表示正在綁定中(尚未驗證)
+type UserTOTPModel struct {
+	Base
+
+	Id            int64
+	UserId        int64
+	Secret        string
+	Enabled       bool
+	RecoveryCodes []string
+
+	CreatedAt string
+	UpdatedAt string
+}
+
+// UserTOTP return a default user totp model.
+func UserTOTP() UserTOTPModel {
+	return UserTOTPModel{Base: Base{TableName: "goadmin_user_totp"}}
+}
+
+func (t UserTOTPModel) SetConn(con db.Connection) UserTOTPModel {
+	t.Conn = con
+	return t
+}
+
+func (t UserTOTPModel) WithTx(tx *sql.Tx) UserTOTPModel {
+	t.Tx = tx
+	return t
+}
+
+// FindByUserId return the totp model of the given user.
+// 透過參數userId尋找符合的資料
+func (t UserTOTPModel) FindByUserId(userId int64) UserTOTPModel {
+	item, _ := t.Table(t.TableName).Where("user_id", "=", userId).First()
+	return t.MapToModel(item)
+}
+
+// IsEmpty check the model is empty or not.
+func (t UserTOTPModel) IsEmpty() bool {
+	return t.Id == int64(0)
+}
+
+// SetSecret save the secret of the user which is not enabled yet.
+// 設置綁定中的secret(enabled = 0)，已存在則覆蓋
+func (t UserTOTPModel) SetSecret(userId int64, secret string) (UserTOTPModel, error) {
+	old := t.FindByUserId(userId)
+
+	var err error
+	if old.IsEmpty() {
+		t.Id, err = t.WithTx(t.Tx).Table(t.TableName).Insert(dialect.H{
+			"user_id": userId,
+			"secret":  secret,
+			"enabled": 0,
+		})
+	} else {
+		t.Id = old.Id
+		_, err = t.WithTx(t.Tx).Table(t.TableName).
+			Where("id", "=", old.Id).
+			Update(dialect.H{
+				"secret":         secret,
+				"enabled":        0,
+				"recovery_codes": "",
+				"updated_at":     time.Now().Format("2006-01-02 15:04:05"),
+			})
+	}
+
+	t.UserId = userId
+	t.Secret = secret
+	t.Enabled = false
+	t.RecoveryCodes = nil
+
+	return t, err
+}
+
+// Enable enable the two-factor authentication with the hashes of recovery codes.
+// 開啟兩步驟驗證並儲存恢復碼(hash)
+func (t UserTOTPModel) Enable(recoveryCodes []string) (UserTOTPModel, error) {
+	_, err := t.WithTx(t.Tx).Table(t.TableName).
+		Where("id", "=", t.Id).
+		Update(dialect.H{
+			"enabled":        1,
+			"recovery_codes": strings.Join(recoveryCodes, ","),
+			"updated_at":     time.Now().Format("2006-01-02 15:04:05"),
+		})
+	t.Enabled = true
+	t.RecoveryCodes = recoveryCodes
+	return t, err
+}
+
+// SetRecoveryCodes replace the hashes of the recovery codes.
+// 更新恢復碼(hash)
+func (t UserTOTPModel) SetRecoveryCodes(recoveryCodes []string) (UserTOTPModel, error) {
+	_, err := t.WithTx(t.Tx).Table(t.TableName).
+		Where("id", "=", t.Id).
+		Update(dialect.H{
+			"recovery_codes": strings.Join(recoveryCodes, ","),
+			"updated_at":     time.Now().Format("2006-01-02 15:04:05"),
+		})
+	t.RecoveryCodes = recoveryCodes
+	return t, err
+}
+
+// UseRecoveryCode check and remove the given recovery code hash.
+// 檢查恢復碼(hash)是否存在，存在則移除(只能使用一次)並回傳true
+func (t UserTOTPModel) UseRecoveryCode(hash string) bool {
+	for i, code := range t.RecoveryCodes {
+		if code == hash {
+			codes := append(append([]string{}, t.RecoveryCodes[:i]...), t.RecoveryCodes[i+1:]...)
+			_, err := t.SetRecoveryCodes(codes)
+			return !db.CheckError(err, db.UPDATE)
+		}
+	}
+	return false
+}
+
+// Delete disable the two-factor authentication of the user.
+// 刪除用戶的兩步驟驗證設置
+func (t UserTOTPModel) Delete() error {
+	return t.WithTx(t.Tx).Table(t.TableName).
+		Where("user_id", "=", t.UserId).
+		Delete()
+}
+
+// MapToModel get the totp model from given map.
+// 將map設置至UserTOTPModel
+func (t UserTOTPModel) MapToModel(m map[string]interface{}) UserTOTPModel {
+	_ = db.ScanStruct(m, &t)
+	if codes, _ := m["recovery_codes"].(string); codes != "" {
+		t.RecoveryCodes = strings.Split(codes, ",")
+	}
+	return t
+}
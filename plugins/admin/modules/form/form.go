@@ -2,6 +2,8 @@ package form
 
 import (
 	"errors"
+	"sort"
+	"strings"
 )
 
 const (
@@ -123,3 +125,36 @@ func (f Values) RemoveSysRemark() Values {
 	f.Delete(NoAnimationKey)
 	return f
 }
+
+// FieldErrors is a validation error which maps the invalid field to its message.
+// The form Validator can return it so that the json api responds with the
+// invalid fields instead of a single message.
+// 表單驗證錯誤，key為欄位名稱，value為錯誤訊息
+type FieldErrors map[string]string
+
+// Add adds the message of given field.
+// 將參數field、msg加入FieldErrors
+func (f FieldErrors) Add(field, msg string) FieldErrors {
+	f[field] = msg
+	return f
+}
+
+// HasError check if there is any invalid field.
+func (f FieldErrors) HasError() bool {
+	return len(f) > 0
+}
+
+// Error implements the error interface.
+// 依欄位名稱排序後將錯誤訊息結合成string
+func (f FieldErrors) Error() string {
+	fields := make([]string, 0, len(f))
+	for field := range f {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	msgs := make([]string, len(fields))
+	for i, field := range fields {
+		msgs[i] = field + ": " + f[field]
+	}
+	return strings.Join(msgs, "; ")
+}
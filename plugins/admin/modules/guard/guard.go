@@ -1,6 +1,11 @@
 package guard
 
 import (
+	"encoding/json"
+	"mime/multipart"
+	"strconv"
+	"strings"
+
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/errors"
@@ -34,7 +39,9 @@ func (g *Guard) table(ctx *context.Context) (table.Table, string) {
 	// constant.PrefixKey = __prefix
 	// 取得url中__prefix的值
 	prefix := ctx.Query(constant.PrefixKey)
-	return g.tableList[prefix](ctx), prefix
+	// 設置目前登入用戶的行級資料權限、欄位權限及變更紀錄的操作者
+	t := table.SetRequest(g.tableList[prefix](ctx), ctx, prefix, g.conn)
+	return t, prefix
 }
 
 // 查詢url裡的參數(__prefix)，如果Guard.tableList存在該prefix(key)則執行迴圈
@@ -45,7 +52,7 @@ func (g *Guard) CheckPrefix(ctx *context.Context) {
 	prefix := ctx.Query(constant.PrefixKey)
 
 	if _, ok := g.tableList[prefix]; !ok {
-		if ctx.Headers(constant.PjaxHeader) == "" && ctx.Method() != "GET" {
+		if (ctx.Headers(constant.PjaxHeader) == "" && ctx.Method() != "GET") || ctx.WantJSON() {
 			response.BadRequest(ctx, errors.Msg)
 		} else {
 			response.Alert(ctx, errors.Msg, errors.Msg, "table model not found", g.conn, g.navBtns,
@@ -59,6 +66,68 @@ func (g *Guard) CheckPrefix(ctx *context.Context) {
 	ctx.Next()
 }
 
+// 取得請求的表單(multipart.Form)，multipart/form-data以外的請求會將
+// application/x-www-form-urlencoded或application/json的內容轉換成multipart.Form
+// json中的陣列會以"欄位名稱[]"為key，與多選欄位提交的格式一致
+func getMultipartForm(ctx *context.Context) (*multipart.Form, error) {
+	if ctx.Request.MultipartForm == nil {
+		_ = ctx.Request.ParseMultipartForm(32 << 20)
+	}
+
+	if ctx.Request.MultipartForm != nil {
+		return ctx.Request.MultipartForm, nil
+	}
+
+	multiForm := &multipart.Form{
+		Value: make(map[string][]string),
+		File:  make(map[string][]*multipart.FileHeader),
+	}
+
+	if !strings.Contains(ctx.Headers("Content-Type"), "json") {
+		for key, value := range ctx.Request.PostForm {
+			multiForm.Value[key] = value
+		}
+		return multiForm, nil
+	}
+
+	var body map[string]interface{}
+	if err := ctx.BindJSON(&body); err != nil {
+		return nil, err
+	}
+
+	for key, value := range body {
+		if arr, ok := value.([]interface{}); ok {
+			if !strings.HasSuffix(key, "[]") {
+				key += "[]"
+			}
+			values := make([]string, len(arr))
+			for i, v := range arr {
+				values[i] = jsonValueToString(v)
+			}
+			multiForm.Value[key] = values
+		} else {
+			multiForm.Value[key] = []string{jsonValueToString(value)}
+		}
+	}
+
+	return multiForm, nil
+}
+
+// 將json解碼後的值轉換成表單的字串
+func jsonValueToString(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		b, _ := json.Marshal(value)
+		return string(b)
+	}
+}
+
 const (
 	editFormParamKey   = "edit_form_param"
 	deleteParamKey     = "delete_param"
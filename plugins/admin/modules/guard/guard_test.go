@@ -0,0 +1,57 @@
+package guard
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMultipartForm(t *testing.T) {
+	req := httptest.NewRequest("POST", "/admin/api/create/users",
+		strings.NewReader(`{"name":"jack","age":18,"score":1.5,"active":true,"avatar":null,`+
+			`"tags":["a",2],"roles[]":["1"],"meta":{"k":"v"}}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	multiForm, err := getMultipartForm(context.NewContext(req))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, multiForm.Value, map[string][]string{
+		"name":    {"jack"},
+		"age":     {"18"},
+		"score":   {"1.5"},
+		"active":  {"true"},
+		"avatar":  {""},
+		"tags[]":  {"a", "2"},
+		"roles[]": {"1"},
+		"meta":    {`{"k":"v"}`},
+	})
+	assert.Equal(t, len(multiForm.File), 0)
+
+	// the malformed json body
+	for _, body := range []string{``, `{"name":`, `["jack"]`, `"jack"`} {
+		req = httptest.NewRequest("POST", "/admin/api/create/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		_, err = getMultipartForm(context.NewContext(req))
+		assert.NotEqual(t, err, nil)
+	}
+
+	req = httptest.NewRequest("POST", "/admin/api/create/users", strings.NewReader("name=jack&tags[]=a&tags[]=b"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	multiForm, err = getMultipartForm(context.NewContext(req))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, multiForm.Value, map[string][]string{"name": {"jack"}, "tags[]": {"a", "b"}})
+
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	assert.Equal(t, writer.WriteField("name", "jack"), nil)
+	assert.Equal(t, writer.Close(), nil)
+	req = httptest.NewRequest("POST", "/admin/create/users", &buf)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	multiForm, err = getMultipartForm(context.NewContext(req))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, multiForm.Value["name"], []string{"jack"})
+}
@@ -89,11 +89,6 @@ func (e NewFormParam) Value() form.Values {
 // NewForm(新增表單)新增用戶、角色、權限等表單資訊，首先取得multipart/form-data設定的參數值並驗證token是否正確
 // 接著取得頁面size、資料排列方式、選擇欄位...等資訊後設置至Parameters(struct)，最後設定Context.UserValue並執行新增表單的動作
 func (g *Guard) NewForm(ctx *context.Context) {
-	// form.PreviousKey  = __go_admin_previous_
-	// 藉由參數取得multipart/form-data中的__go_admin_previous_值
-	// ex:/admin/info/manager?__page=1&__pageSize=10&__sort=id&__sort_type=desc
-	previous := ctx.FormValue(form.PreviousKey)
-
 	// 取得url中__prefix的值
 	// prefix = manager、roles、permission
 	panel, prefix := g.table(ctx)
@@ -101,16 +96,33 @@ func (g *Guard) NewForm(ctx *context.Context) {
 	// 取得匹配的service.Service然後轉換成Connection(interface)類別
 	conn := db.GetConnection(g.services)
 
+	// 取得請求的表單，api的請求可以是application/x-www-form-urlencoded或application/json
+	multiForm, err := getMultipartForm(ctx)
+	if err != nil {
+		alert(ctx, panel, err.Error(), conn, g.navBtns)
+		ctx.Abort()
+		return
+	}
+
+	// 取得在multipart/form-data所設定的參數(map[string][]string)
+	values := form.Values(multiForm.Value)
+
+	// form.PreviousKey  = __go_admin_previous_
+	// 藉由參數取得multipart/form-data中的__go_admin_previous_值
+	// ex:/admin/info/manager?__page=1&__pageSize=10&__sort=id&__sort_type=desc
+	previous := values.Get(form.PreviousKey)
+
 	// form.TokenKey  = __go_admin_t_
 	// 藉由參數取得multipart/form-data中的__go_admin_t_值
-	token := ctx.FormValue(form.TokenKey)
+	token := values.Get(form.TokenKey)
 
 	// GetTokenService將參數g.services.Get(auth.TokenServiceKey)轉換成TokenService(struct)類別後回傳
 	// GetTokenService透過參數(token_csrf_helper)取得匹配的Service(interface)
 	// CheckToken檢查TokenService.tokens([]string)裡是否有符合參數toCheckToken的值
 	// 如果符合，將在TokenService.tokens([]string)裡將符合的toCheckToken從[]string拿出
 	// 檢查token是否正確
-	if !auth.GetTokenService(g.services.Get(auth.TokenServiceKey)).CheckToken(token) {
+	// 透過bearer token驗證的請求(非瀏覽器)不檢查csrf token
+	if !auth.IsBearerAuth(ctx) && !auth.GetTokenService(g.services.Get(auth.TokenServiceKey)).CheckToken(token) {
 		alert(ctx, panel, errors.CreateFailWrongToken, conn, g.navBtns)
 		ctx.Abort()
 		return
@@ -131,21 +143,18 @@ func (g *Guard) NewForm(ctx *context.Context) {
 		previous = config.Url("/info/" + prefix + param.GetRouteParamStr())
 	}
 
-	// 取得在multipart/form-data所設定的參數(map[string][]string)
-	values := ctx.Request.MultipartForm.Value
-
 	// newFormParamKey = new_form_param
 	// SetUserValue藉由參數key、value設定Context.UserValue
 	ctx.SetUserValue(newFormParamKey, &NewFormParam{
 		Panel:        panel,
 		Id:           "",
-		Prefix:       prefix,                                                // manage or roles or permissions
-		Param:        param,                                                 // 頁面size、資料排列方式、選擇欄位...等資訊
-		IsIframe:     form.Values(values).Get(constant.IframeKey) == "true", // ex:false
-		IframeID:     form.Values(values).Get(constant.IframeIDKey),         // ex:空
-		Path:         strings.Split(previous, "?")[0],                       // ex:/admin/info/manager(roles or permissions)
-		MultiForm:    ctx.Request.MultipartForm,                             // 在multipart/form-data所設定的參數
-		PreviousPath: previous,                                              // ex: /admin/info/manager?__page=1&__pageSize=10&__sort=id&__sort_type=desc
+		Prefix:       prefix,                                   // manage or roles or permissions
+		Param:        param,                                    // 頁面size、資料排列方式、選擇欄位...等資訊
+		IsIframe:     values.Get(constant.IframeKey) == "true", // ex:false
+		IframeID:     values.Get(constant.IframeIDKey),         // ex:空
+		Path:         strings.Split(previous, "?")[0],          // ex:/admin/info/manager(roles or permissions)
+		MultiForm:    multiForm,                                // 在multipart/form-data所設定的參數
+		PreviousPath: previous,                                 // ex: /admin/info/manager?__page=1&__pageSize=10&__sort=id&__sort_type=desc
 		FromList:     fromList,
 	})
 	ctx.Next()
@@ -37,6 +37,7 @@ const (
 
 	IsAll      = "__is_all"
 	PrimaryKey = "__pk"
+	Trash      = "__goadmin_trash"
 
 	True  = "true"
 	False = "false"
@@ -200,6 +201,12 @@ func (param Parameters) IsAll() bool {
 	return param.GetFieldValue(IsAll) == True
 }
 
+// IsTrash check whether to query the soft deleted rows(recycle bin).
+// 透過參數__goadmin_trash判斷是否查詢回收站(已軟刪除)的資料
+func (param Parameters) IsTrash() bool {
+	return param.GetFieldValue(Trash) == True
+}
+
 func (param Parameters) WithURLPath(path string) Parameters {
 	param.URLPath = path
 	return param
@@ -10,7 +10,10 @@ import (
 	"github.com/GoAdminGroup/go-admin/modules/menu"
 	"github.com/GoAdminGroup/go-admin/template"
 	"github.com/GoAdminGroup/go-admin/template/types"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 // 成功，回傳code:200 and msg:ok
@@ -47,6 +50,19 @@ func BadRequest(ctx *context.Context, msg string) {
 	})
 }
 
+// 驗證錯誤，回傳code:400、msg以及各欄位的錯誤訊息errors
+func ValidationError(ctx *context.Context, msg string, errs map[string]string) {
+	fieldErrs := make(map[string]string, len(errs))
+	for field, fieldMsg := range errs {
+		fieldErrs[field] = language.Get(fieldMsg)
+	}
+	ctx.JSON(http.StatusBadRequest, map[string]interface{}{
+		"code":   http.StatusBadRequest,
+		"msg":    language.Get(msg),
+		"errors": fieldErrs,
+	})
+}
+
 // 透過參數ctx回傳目前登入的用戶(Context.UserValue["user"])並轉換成UserModel，接著將給定的數據(types.Page(struct))寫入buf(struct)並回傳，最後輸出HTML
 // 將參數desc、title、msg寫入Panel
 func Alert(ctx *context.Context, desc, title, msg string, conn db.Connection, btns *types.Buttons,
@@ -95,6 +111,19 @@ func Error(ctx *context.Context, msg string) {
 	})
 }
 
+// 請求過於頻繁，回傳code:429、msg以及需要等待的秒數retry_after，並設置header Retry-After
+func TooManyRequests(ctx *context.Context, msg string, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	ctx.AddHeader("Retry-After", strconv.Itoa(seconds))
+	ctx.JSON(http.StatusTooManyRequests, map[string]interface{}{
+		"code": http.StatusTooManyRequests,
+		"msg":  language.Get(msg),
+		"data": map[string]interface{}{
+			"retry_after": seconds,
+		},
+	})
+}
+
 // 錯誤，回傳code:403 and msg
 func Denied(ctx *context.Context, msg string) {
 	ctx.JSON(http.StatusInternalServerError, map[string]interface{}{
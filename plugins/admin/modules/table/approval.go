@@ -0,0 +1,157 @@
+package table
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+)
+
+// ErrChangePending is returned by the writes of the table which needs approval, the change
+// request is created and waiting for approval.
+var ErrChangePending = errors.New("the change is waiting for approval")
+
+// 設置審核的資料表寫入時改為建立變更申請，套用已核准的申請時直接寫入
+func (tb *DefaultTable) needApproval() bool {
+	return tb.Approval != "" && !tb.approving
+}
+
+// 建立待審核的變更申請，刪除多筆資料時每筆各建立一個申請，成功時回傳ErrChangePending
+func (tb *DefaultTable) submitChange(table, operation string, ids []string, values form.Values) error {
+	if tb.operator.Prefix == "" {
+		return errors.New("approval: unknown table prefix")
+	}
+
+	var before map[string]map[string]interface{}
+	if operation == models.AuditInsert {
+		ids = []string{""}
+	} else {
+		before = tb.auditSnapshot(table, ids)
+	}
+
+	if values != nil {
+		values.Delete(form.TokenKey)
+		values.Delete(form.PreviousKey)
+	}
+
+	for _, id := range ids {
+		_, err := models.ChangeRequest().SetConn(adminConnection()).Redact(tb.AuditRedact...).
+			New(tb.operator.UserId, tb.operator.Prefix, table, id, operation, tb.Approval, before[id], values)
+		if db.CheckError(err, db.INSERT) {
+			return err
+		}
+	}
+
+	return ErrChangePending
+}
+
+// ApplyChange write the pending change request to the table and mark it approved. If the table
+// and the change requests are stored in the same database, both are done in one transaction;
+// otherwise the change request is approved first and set back to pending if failed to write.
+// 核准變更申請並寫入資料表，與變更申請在同一個資料庫時在同一個交易中完成，
+// 否則先核准申請，寫入失敗時再改回待審核(自定義的InsertFn、UpdateFn、DeleteFn不在交易中)
+func (tb *DefaultTable) ApplyChange(change models.ChangeRequestModel, reviewerId int64, comment string) error {
+	if !change.IsPending() {
+		return models.ErrChangeReviewed
+	}
+
+	tb.approving = true
+	defer func() { tb.approving = false }()
+
+	change = change.SetConn(adminConnection())
+
+	if !tb.getDataFromDB() {
+		if _, err := change.Review(models.ChangeApproved, reviewerId, comment); err != nil {
+			return err
+		}
+		err := tb.applyChange(change)
+		tb.reopenChange(change, err)
+		return err
+	}
+
+	sameDB := tb.sameAsAdminConnection()
+
+	if !sameDB {
+		if _, err := change.Review(models.ChangeApproved, reviewerId, comment); err != nil {
+			return err
+		}
+	}
+
+	_, err := tb.sql().WithTransaction(func(tx *sql.Tx) (error, map[string]interface{}) {
+		tb.tx = tx
+		defer func() { tb.tx = nil }()
+
+		if sameDB {
+			if _, err := change.WithTx(tx).Review(models.ChangeApproved, reviewerId, comment); err != nil {
+				return err, nil
+			}
+		}
+
+		return tb.applyChange(change), nil
+	})
+
+	if !sameDB {
+		tb.reopenChange(change, err)
+	}
+
+	return err
+}
+
+// RejectChange reject the pending change request with the comment.
+// 駁回變更申請
+func RejectChange(change models.ChangeRequestModel, reviewerId int64, comment string) error {
+	_, err := change.SetConn(adminConnection()).Review(models.ChangeRejected, reviewerId, comment)
+	return err
+}
+
+func (tb *DefaultTable) applyChange(change models.ChangeRequestModel) error {
+	switch change.Operation {
+	case models.AuditInsert:
+		return tb.InsertData(change.GetNewValues())
+	case models.AuditUpdate:
+		return tb.UpdateData(change.GetNewValues())
+	case models.AuditDelete:
+		return tb.DeleteData(change.RecordId)
+	}
+	return errors.New("approval: wrong operation")
+}
+
+// 寫入失敗時將已核准的申請改回待審核
+func (tb *DefaultTable) reopenChange(change models.ChangeRequestModel, err error) {
+	if err == nil {
+		return
+	}
+	if reopenErr := change.Reopen(); db.CheckError(reopenErr, db.UPDATE) {
+		logger.Error("reopen change request error", reopenErr)
+	}
+}
+
+// 資料表是否與系統資料表(變更紀錄、變更申請)在同一個資料庫
+func (tb *DefaultTable) sameAsAdminConnection() bool {
+	return tb.connection == DefaultConnectionName &&
+		tb.connectionDriver == config.GetDatabases().GetDefault().Driver
+}
+
+// 套用變更申請時，與系統資料表在同一個資料庫的交易
+func (tb *DefaultTable) adminTx() *sql.Tx {
+	if tb.tx != nil && tb.sameAsAdminConnection() {
+		return tb.tx
+	}
+	return nil
+}
+
+func adminConnection() db.Connection {
+	return db.GetConnectionFromService(services.Get(config.GetDatabases().GetDefault().Driver))
+}
+
+func copyValues(values form.Values) form.Values {
+	res := make(form.Values, len(values))
+	for key, value := range values {
+		res[key] = append([]string(nil), value...)
+	}
+	return res
+}
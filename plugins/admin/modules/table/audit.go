@@ -0,0 +1,122 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+)
+
+// Operator is the user who writes the table, recorded in the audit log. The prefix of the
+// table generator is recorded in the change request.
+type Operator struct {
+	UserId int64
+	Ip     string
+	Prefix string
+}
+
+// AuditRedactor is implemented by the tables which have the sensitive fields besides the
+// models.AuditMaskFields.
+// 有額外敏感欄位的資料表(DefaultTable)，變更申請顯示差異時遮蔽這些欄位
+type AuditRedactor interface {
+	GetAuditRedact() []string
+}
+
+// CurrentOperator return the operator of the request.
+// 回傳目前登入的用戶、請求的ip及資料表的prefix
+func CurrentOperator(ctx *context.Context) Operator {
+	op := Operator{Ip: auth.ClientIP(ctx), Prefix: ctx.Query(constant.PrefixKey)}
+	if user, ok := ctx.User().(models.UserModel); ok {
+		op.UserId = user.Id
+	}
+	return op
+}
+
+// SetOperator set the operator of the table writes.
+func (tb *DefaultTable) SetOperator(op Operator) {
+	tb.operator = op
+}
+
+// 取得寫入前的資料快照，key為主鍵值
+func (tb *DefaultTable) auditSnapshot(table string, ids []string) map[string]map[string]interface{} {
+	snapshot := make(map[string]map[string]interface{})
+	if !tb.getDataFromDB() || table == "" {
+		return snapshot
+	}
+
+	vals := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		if id != "" {
+			vals = append(vals, id)
+		}
+	}
+	if len(vals) == 0 {
+		return snapshot
+	}
+
+	// 寫入前後的資料都在主資料庫讀取，避免讀取副本尚未同步
+	rows, err := tb.sql().UsePrimary().Table(table).WhereIn(tb.PrimaryKey.Name, vals).All()
+	if err != nil {
+		logger.Error("audit snapshot error", err)
+		return snapshot
+	}
+	for _, row := range rows {
+		snapshot[fmt.Sprintf("%v", row[tb.PrimaryKey.Name])] = row
+	}
+	return snapshot
+}
+
+// 記錄寫入前後的資料，新增時before為空，刪除時after為空，沒有任何變更的更新不記錄
+func (tb *DefaultTable) audit(table, operation string, before, after map[string]map[string]interface{}) {
+	if !tb.getDataFromDB() || table == "" {
+		return
+	}
+
+	ids := make([]string, 0)
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+
+	conn := db.GetConnectionFromService(services.Get(config.GetDatabases().GetDefault().Driver))
+
+	for _, id := range ids {
+		if operation == models.AuditUpdate && len(models.AuditDiff(models.AuditValues(before[id]),
+			models.AuditValues(after[id]))) == 0 {
+			continue
+		}
+		_, err := models.AuditLog().SetConn(conn).WithTx(tb.adminTx()).Redact(tb.AuditRedact...).
+			New(tb.operator.UserId, table, id, operation, tb.operator.Ip, before[id], after[id])
+		if err != nil {
+			logger.Error("audit log error", err)
+		}
+	}
+}
+
+// 將提交的表單值轉換成變更紀錄的資料，忽略內部參數(__開頭)
+func auditFormValues(dataList form.Values) map[string]interface{} {
+	values := make(map[string]interface{})
+	for key, value := range dataList {
+		if strings.HasPrefix(key, "__") || len(value) == 0 {
+			continue
+		}
+		key = strings.TrimSuffix(key, "[]")
+		if len(value) == 1 {
+			values[key] = value[0]
+		} else {
+			values[key] = strings.Join(value, ",")
+		}
+	}
+	return values
+}
@@ -0,0 +1,71 @@
+package table
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditRedact(t *testing.T) {
+	data, err := ioutil.ReadFile("../../../../tests/data/admin.db")
+	assert.Equal(t, err, nil)
+	dir, err := ioutil.TempDir("", "goadmin")
+	assert.Equal(t, err, nil)
+	defer func() { _ = os.RemoveAll(dir) }()
+	file := filepath.Join(dir, "admin.db")
+	assert.Equal(t, ioutil.WriteFile(file, data, os.ModePerm), nil)
+
+	cfg := config.Set(config.Config{
+		Databases: config.DatabaseList{"default": {Driver: db.DriverSqlite, File: file}},
+		UrlPrefix: "admin",
+	})
+	conn := db.GetConnectionByDriver(db.DriverSqlite).InitDB(cfg.Databases)
+	defer conn.Close()
+	services = service.List{db.DriverSqlite: conn}
+
+	ctx := context.NewContext(httptest.NewRequest("GET", "/admin/info/manager", nil))
+	manager := NewSystemTable(conn, cfg).GetManagerTable(ctx).(*DefaultTable)
+	manager.SetOperator(Operator{UserId: 1, Ip: "127.0.0.1"})
+
+	before := manager.auditSnapshot("goadmin_users", []string{"1"})
+	oldHash := before["1"]["password"].(string)
+	_, err = db.WithDriver(conn).Table("goadmin_users").Where("id", "=", 1).
+		Update(map[string]interface{}{"password": "$2a$10$newhash", "remember_token": "newtoken", "name": "root"})
+	assert.Equal(t, err, nil)
+	manager.audit("goadmin_users", models.AuditUpdate, before, manager.auditSnapshot("goadmin_users", []string{"1"}))
+
+	logs := models.AuditLog().SetConn(conn).FindByRecord("goadmin_users", "1")
+	assert.Equal(t, len(logs), 1)
+	record := logs[0].OldValues + logs[0].NewValues + logs[0].Diff
+	// the password hash and the remember token are not recorded, only the fact that they are changed
+	assert.False(t, strings.Contains(record, oldHash))
+	assert.False(t, strings.Contains(record, "$2a$10$newhash"))
+	assert.False(t, strings.Contains(record, "newtoken"))
+	assert.Equal(t, logs[0].Diff, `[{"field":"name","old":"admin","new":"root"},`+
+		`{"field":"password","old":"******","new":"******"},{"field":"remember_token","old":"******","new":"******"}]`)
+
+	// the fields redacted by the generator
+	users := NewDefaultTable(DefaultConfigWithDriver(db.DriverSqlite).SetAuditRedact("name")).(*DefaultTable)
+	users.GetInfo().SetTable("goadmin_users")
+	before = users.auditSnapshot("goadmin_users", []string{"2"})
+	_, err = db.WithDriver(conn).Table("goadmin_users").Where("id", "=", 2).
+		Update(map[string]interface{}{"name": "secret"})
+	assert.Equal(t, err, nil)
+	users.audit("goadmin_users", models.AuditUpdate, before, users.auditSnapshot("goadmin_users", []string{"2"}))
+
+	logs = models.AuditLog().SetConn(conn).FindByRecord("goadmin_users", "2")
+	assert.Equal(t, len(logs), 1)
+	assert.Equal(t, logs[0].Diff, `[{"field":"name","old":"******","new":"******"}]`)
+	assert.False(t, strings.Contains(logs[0].NewValues, "secret"))
+	assert.Equal(t, users.Copy().(*DefaultTable).AuditRedact, []string{"name"})
+}
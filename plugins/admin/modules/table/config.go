@@ -1,6 +1,8 @@
 package table
 
 import (
+	"time"
+
 	"github.com/GoAdminGroup/go-admin/modules/db"
 )
 
@@ -18,6 +20,11 @@ type Config struct {
 	OnlyNewForm    bool
 	OnlyUpdateForm bool
 	OnlyDetail     bool
+	SoftDelete     string
+	TrashRetention time.Duration
+	Approval       string
+	AuditRedact    []string
+	ExportLimit    int
 }
 
 func DefaultConfig() Config {
@@ -90,6 +97,50 @@ func (config Config) SetExportable(exportable bool) Config {
 	return config
 }
 
+// SetSoftDelete enable the soft delete of the table, the deleted rows are marked with the
+// deletion time in the field(default: deleted_at) and moved to the recycle bin.
+// 啟用軟刪除，刪除時在欄位(預設為deleted_at)記錄刪除時間，資料移至回收站並可還原或永久刪除
+func (config Config) SetSoftDelete(field ...string) Config {
+	config.SoftDelete = DefaultSoftDeleteField
+	if len(field) > 0 && field[0] != "" {
+		config.SoftDelete = field[0]
+	}
+	return config
+}
+
+// SetTrashRetention set the retention period of the recycle bin, the rows deleted before
+// the period are purged by the scheduled task.
+// 設置回收站的保留期限，超過期限的資料會被排程永久刪除，0表示不自動刪除
+func (config Config) SetTrashRetention(retention time.Duration) Config {
+	config.TrashRetention = retention
+	return config
+}
+
+// SetApproval make the inserts, updates and deletes of the table be submitted as change requests,
+// which are written after approved by the user with the permission(slug).
+// 啟用審核，新增、編輯及刪除時改為建立待審核的變更申請，擁有該權限(slug)的用戶核准後才寫入資料表
+// 審核者的權限也需要允許/change_requests/*的路徑
+func (config Config) SetApproval(permission string) Config {
+	config.Approval = permission
+	return config
+}
+
+// SetAuditRedact set the sensitive fields of the table besides the models.AuditMaskFields(password,
+// remember_token...), the audit log and the change request only record that the fields are changed.
+// 設置資料表的敏感欄位(預設已包含password、remember_token等)，變更紀錄及變更申請只記錄欄位有變更，不記錄實際值
+func (config Config) SetAuditRedact(fields ...string) Config {
+	config.AuditRedact = fields
+	return config
+}
+
+// SetExportLimit set the maximum rows of the export, the export fails with ErrExportLimit if
+// the rows exceed it. Zero means DefaultExportLimit and the negative number means no limit.
+// 設置匯出的最大筆數，超過時匯出失敗(回傳ErrExportLimit)；0為DefaultExportLimit，負數表示不限制
+func (config Config) SetExportLimit(limit int) Config {
+	config.ExportLimit = limit
+	return config
+}
+
 func (config Config) SetConnection(connection string) Config {
 	config.Connection = connection
 	return config
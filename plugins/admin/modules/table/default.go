@@ -1,6 +1,8 @@
 package table
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,6 +18,7 @@ import (
 	errs "github.com/GoAdminGroup/go-admin/modules/errors"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
@@ -30,6 +33,12 @@ type DefaultTable struct {
 	connection       string
 	sourceURL        string
 	getDataFun       GetDataFun
+	rowScope         RowScope
+	forbiddenFields  []string
+	operator         Operator
+	approving        bool
+	tx               *sql.Tx
+	ctx              context.Context
 }
 
 type GetDataFun func(params parameter.Parameters) ([]map[string]interface{}, int)
@@ -58,6 +67,11 @@ func NewDefaultTable(cfgs ...Config) Table {
 			OnlyUpdateForm: cfg.OnlyUpdateForm,
 			OnlyDetail:     cfg.OnlyDetail,
 			OnlyInfo:       cfg.OnlyInfo,
+			SoftDelete:     cfg.SoftDelete,
+			TrashRetention: cfg.TrashRetention,
+			Approval:       cfg.Approval,
+			AuditRedact:    cfg.AuditRedact,
+			ExportLimit:    cfg.ExportLimit,
 		},
 		connectionDriver: cfg.Driver,
 		connection:       cfg.Connection,
@@ -80,19 +94,61 @@ func (tb *DefaultTable) Copy() Table {
 				SetDescription(tb.Detail.Description).
 				SetTitle(tb.Detail.Title).
 				SetGetDataFn(tb.Detail.GetDataFn),
-			CanAdd:     tb.CanAdd,
-			Editable:   tb.Editable,
-			Deletable:  tb.Deletable,
-			Exportable: tb.Exportable,
-			PrimaryKey: tb.PrimaryKey,
+			CanAdd:         tb.CanAdd,
+			Editable:       tb.Editable,
+			Deletable:      tb.Deletable,
+			Exportable:     tb.Exportable,
+			PrimaryKey:     tb.PrimaryKey,
+			SoftDelete:     tb.SoftDelete,
+			TrashRetention: tb.TrashRetention,
+			Approval:       tb.Approval,
+			AuditRedact:    tb.AuditRedact,
+			ExportLimit:    tb.ExportLimit,
 		},
 		connectionDriver: tb.connectionDriver,
 		connection:       tb.connection,
 		sourceURL:        tb.sourceURL,
 		getDataFun:       tb.getDataFun,
+		rowScope:         tb.rowScope,
+		forbiddenFields:  tb.forbiddenFields,
+		operator:         tb.operator,
+		ctx:              tb.ctx,
 	}
 }
 
+// SetRowScope set the row level data permission of the table.
+// 設置行級資料權限，查詢、編輯、刪除及匯出時會加上對應的條件
+func (tb *DefaultTable) SetRowScope(scope RowScope) {
+	tb.rowScope = scope
+}
+
+// SetContext set the context of the table queries, the queries are canceled when the context
+// is done, such as the request is canceled by the client.
+// 設置查詢使用的context(一般為請求的context)，請求中斷或逾時時停止查詢
+func (tb *DefaultTable) SetContext(ctx context.Context) {
+	tb.ctx = ctx
+}
+
+// 取得查詢使用的context，沒有設置時使用context.Background()
+func (tb *DefaultTable) context() context.Context {
+	if tb.ctx != nil {
+		return tb.ctx
+	}
+	return context.Background()
+}
+
+// SetFieldPermission remove the info and detail fields which the user has no permission of,
+// the form fields are read-only.
+// 依欄位設置的權限移除列表、詳情及匯出的欄位，表單欄位設為唯讀，並記錄不可篩選、排序及提交的欄位
+func (tb *DefaultTable) SetFieldPermission(fn FieldPermissionFn) {
+	var forbidden []string
+	tb.Info.FieldList, forbidden = filterInfoFields(tb.Info.FieldList, fn)
+	tb.forbiddenFields = append(tb.forbiddenFields, forbidden...)
+	tb.Detail.FieldList, forbidden = filterInfoFields(tb.Detail.FieldList, fn)
+	tb.forbiddenFields = append(tb.forbiddenFields, forbidden...)
+	tb.forbiddenFields = append(tb.forbiddenFields, readOnlyFormFields(tb.Form.FieldList, fn)...)
+}
+
 // GetData query the data set.
 // 透過參數處理sql語法後取得資料表資料並將值設置至PanelInfo(struct)後回傳，PanelInfo裡的資訊有主題、描述名稱、可以篩選條件的欄位、選擇顯示的欄位....等資訊
 func (tb *DefaultTable) GetData(params parameter.Parameters) (PanelInfo, error) {
@@ -153,6 +209,7 @@ func (tb *DefaultTable) GetData(params parameter.Parameters) (PanelInfo, error)
 			Param:        params,
 			PageSizeList: tb.Info.GetPageSizeList(),
 		}).SetExtraInfo(template.HTML(extraInfo)),
+		Total:          size,
 		Title:          tb.Info.Title,
 		FilterFormData: filterForm,
 		Description:    tb.Info.Description,
@@ -243,6 +300,7 @@ func (tb *DefaultTable) GetDataWithIds(params parameter.Parameters) (PanelInfo,
 		}).
 			SetExtraInfo(template.HTML(fmt.Sprintf("<b>" + language.Get("query time") + ": </b>" +
 				fmt.Sprintf("%.3fms", endTime.Sub(beginTime).Seconds()*1000)))),
+		Total:          size,
 		Title:          tb.Info.Title,
 		FilterFormData: filterForm,
 		Description:    tb.Info.Description,
@@ -360,13 +418,17 @@ func (tb *DefaultTable) getTempModelData(res map[string]interface{}, params para
 	return tempModelData
 }
 
-func (tb *DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (PanelInfo, error) {
+// eachDataFromDatabase query all the data of the params and call fn with every size rows, the rows
+// are read one by one and only size rows are kept in memory. fn is called at least once.
+// 查詢所有資料(匯出全部)，逐筆讀取並每size筆呼叫一次fn，記憶體中最多只保留size筆資料；沒有資料時也會呼叫一次fn
+func (tb *DefaultTable) eachDataFromDatabase(params parameter.Parameters, size int, fn func(info PanelInfo) error) error {
 	var (
 		connection     = tb.db()
 		queryStatement = "select %s from %s %s %s %s order by " + modules.Delimiter(connection.GetDelimiter(), "%s") + " %s"
 	)
 
 	columns, _ := tb.getColumns(tb.Info.Table)
+	columns = tb.allowedColumns(columns)
 
 	thead, fields, joins := tb.Info.FieldList.GetThead(types.TableInfo{
 		Table:      tb.Info.Table,
@@ -392,6 +454,8 @@ func (tb *DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (Pan
 		tb.Info.FieldList.GetFieldFilterProcessValue)
 	wheres, whereArgs = tb.Info.Wheres.Statement(wheres, connection.GetDelimiter(), whereArgs, existKeys, columns)
 	wheres, whereArgs = tb.Info.WhereRaws.Statement(wheres, whereArgs)
+	wheres, whereArgs = tb.rowScopeStatement(tb.Info.Table, wheres, whereArgs)
+	wheres = tb.softDeleteStatement(tb.Info.Table, wheres, params.IsTrash())
 
 	if wheres != "" {
 		wheres = " where " + wheres
@@ -405,24 +469,114 @@ func (tb *DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (Pan
 
 	logger.LogSQL(queryCmd, []interface{}{})
 
-	res, err := connection.QueryWithConnection(tb.connection, queryCmd, whereArgs...)
+	rows, err := connection.QueryRowsWithConnection(tb.context(), tb.connection, queryCmd, whereArgs...)
 
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var (
+		infoList = make(types.InfoList, 0, size)
+		called   = false
+		call     = func() error {
+			called = true
+			err := fn(PanelInfo{
+				InfoList:    infoList,
+				Thead:       thead,
+				Total:       len(infoList),
+				Title:       tb.Info.Title,
+				Description: tb.Info.Description,
+			})
+			infoList = make(types.InfoList, 0, size)
+			return err
+		}
+	)
+
+	for rows.Next() {
+		infoList = append(infoList, tb.getTempModelData(rows.Row(), params, columns))
+		if len(infoList) >= size {
+			if err = call(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if len(infoList) > 0 || !called {
+		return call()
+	}
+
+	return nil
+}
+
+// getAllDataFromDatabase query all the data of the params.
+// 查詢所有資料(匯出全部)
+func (tb *DefaultTable) getAllDataFromDatabase(params parameter.Parameters) (PanelInfo, error) {
+	var all PanelInfo
+	err := tb.eachDataFromDatabase(params, exportChunkSize, func(info PanelInfo) error {
+		infoList := append(all.InfoList, info.InfoList...)
+		all = info
+		all.InfoList = infoList
+		all.Total = len(infoList)
+		return nil
+	})
 	if err != nil {
 		return PanelInfo{}, err
 	}
+	return all, nil
+}
 
-	infoList := make([]map[string]types.InfoItem, 0)
+// 匯出全部資料時每批讀取的筆數
+const exportChunkSize = 500
 
-	for i := 0; i < len(res); i++ {
-		infoList = append(infoList, tb.getTempModelData(res[i], params, columns))
+// ErrExportLimit is returned when the rows to export exceed the export limit of the table.
+var ErrExportLimit = errors.New("too many rows to export, please narrow down the filter")
+
+// 回傳匯出的最大筆數，0表示不限制
+func (tb *DefaultTable) exportLimit() int {
+	if tb.ExportLimit == 0 {
+		return DefaultExportLimit
+	}
+	if tb.ExportLimit < 0 {
+		return 0
 	}
+	return tb.ExportLimit
+}
 
-	return PanelInfo{
-		InfoList:    infoList,
-		Thead:       thead,
-		Title:       tb.Info.Title,
-		Description: tb.Info.Description,
-	}, nil
+// ExportData query the data of the params and call fn with every chunk of the data. When
+// exporting all the data of the database, the rows are read from the database by chunks;
+// otherwise fn is called once with the result of GetData. The writer of fn may still keep
+// all the rows in memory(the xlsx file does), so ErrExportLimit is returned once the rows
+// exceed the export limit.
+// 匯出資料時使用，匯出資料庫全部資料時逐筆讀取並分批呼叫fn，其他情況以GetData的結果呼叫一次fn；
+// fn寫入的檔案(excel)仍可能保留所有資料，超過匯出的最大筆數時停止讀取並回傳ErrExportLimit
+func (tb *DefaultTable) ExportData(params parameter.Parameters, fn func(info PanelInfo) error) error {
+	var (
+		limit = tb.exportLimit()
+		count = 0
+		call  = func(info PanelInfo) error {
+			count += len(info.InfoList)
+			if limit > 0 && count > limit {
+				return ErrExportLimit
+			}
+			return fn(info)
+		}
+	)
+	if params.IsAll() && tb.getDataFromDB() && tb.Info.QueryFilterFn == nil {
+		return tb.eachDataFromDatabase(params, exportChunkSize, call)
+	}
+	info, err := tb.GetData(params)
+	if err != nil {
+		return err
+	}
+	return call(info)
 }
 
 // TODO: refactor
@@ -452,10 +606,10 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		if connection.Name() == db.DriverMssql {
 			countExtra = "as [size]"
 		}
-		// %s means: fields, table, join table, pk values, group by, order by field,  order by type
-		queryStatement = "select %s from " + placeholder + " %s where " + pk + " in (%s) %s ORDER BY %s." + placeholder + " %s"
-		// %s means: table, join table, pk values
-		countStatement = "select count(*) " + countExtra + " from " + placeholder + " %s where " + pk + " in (%s)"
+		// %s means: fields, table, join table, wheres, group by, order by field,  order by type
+		queryStatement = "select %s from " + placeholder + " %s where %s %s ORDER BY %s." + placeholder + " %s"
+		// %s means: table, join table, wheres
+		countStatement = "select count(*) " + countExtra + " from " + placeholder + " %s where %s"
 	} else {
 		// -------用戶編輯介面會執行---------
 		if connection.Name() == db.DriverMssql {
@@ -475,6 +629,8 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 	// getColumns(取得資料表欄位)將欄位名稱加入columns([]string)
 	// 如果有值是primary_key並且自動遞增則bool = true，最後回傳欄位名稱及bool
 	columns, _ := tb.getColumns(tb.Info.Table) // ex: tb.Info.Table = goadmin_users
+	// 移除沒有權限的欄位，避免透過篩選、排序推測欄位的值
+	columns = tb.allowedColumns(columns)
 
 	// 透過參數並且將欄位、join語法...等資訊處理後，回傳[]TheadItem、欄位名稱、joinFields(ex:group_concat(goadmin_roles.`name`...)、join語法(left join....)、合併的資料表、可篩選過濾的欄位
 	thead, fields, joinFields, joins, joinTables, filterForm := tb.getTheadAndFilterForm(params, columns)
@@ -521,7 +677,9 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 				args = append(args, value)
 			}
 		}
-		wheres = wheres[:len(wheres)-1]
+		wheres = pk + " in (" + wheres[:len(wheres)-1] + ")"
+		wheres, args = tb.rowScopeStatement(tb.Info.Table, wheres, args)
+		wheres = tb.softDeleteStatement(tb.Info.Table, wheres, params.IsTrash())
 	} else {
 		// -----用戶介面會執行------
 		// parameter
@@ -538,6 +696,12 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		//// ----用戶頁面DefaultTable.Info.WhereRaws為空，回傳的值不變-------
 		wheres, whereArgs = tb.Info.WhereRaws.Statement(wheres, whereArgs)
 
+		// 行級資料權限
+		wheres, whereArgs = tb.rowScopeStatement(tb.Info.Table, wheres, whereArgs)
+
+		// 軟刪除，預設只查詢未刪除的資料，回收站只查詢已刪除的資料
+		wheres = tb.softDeleteStatement(tb.Info.Table, wheres, params.IsTrash())
+
 		if wheres != "" {
 			wheres = " where " + wheres
 		}
@@ -572,7 +736,7 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 	// 印出sql資訊
 	logger.LogSQL(queryCmd, args)
 
-	res, err := connection.QueryWithConnection(tb.connection, queryCmd, args...)
+	res, err := connection.QueryWithConnectionContext(tb.context(), tb.connection, queryCmd, args...)
 	if err != nil {
 		return PanelInfo{}, err
 	}
@@ -595,7 +759,7 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		// countCmd的指令為查詢符合結果的資料數量
 		countCmd := fmt.Sprintf(countStatement, tb.Info.Table, joins, wheres)
 		// ex: total: [map[count(*):4]](4筆)
-		total, err := connection.QueryWithConnection(tb.connection, countCmd, whereArgs...)
+		total, err := connection.QueryWithConnectionContext(tb.context(), tb.connection, countCmd, whereArgs...)
 
 		if err != nil {
 			return PanelInfo{}, err
@@ -610,6 +774,8 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		} else {
 			size = int(total[0]["count(*)"].(int64)) // ex:4(4筆符合)
 		}
+	} else {
+		size = len(infoList)
 	}
 
 	endTime := time.Now()
@@ -620,6 +786,7 @@ func (tb *DefaultTable) getDataFromDatabase(params parameter.Parameters) (PanelI
 		Paginator: tb.GetPaginator(size, params,
 			template.HTML(fmt.Sprintf("<b>"+language.Get("query time")+": </b>"+
 				fmt.Sprintf("%.3fms", endTime.Sub(beginTime).Seconds()*1000)))),
+		Total:          size,
 		Title:          tb.Info.Title,       // 左上角主題
 		FilterFormData: filterForm,          // 可以篩選條件的欄位
 		Description:    tb.Info.Description, //主題旁的描述
@@ -687,6 +854,17 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 			queryStatement = "select %s from %s %s where " + pk + " = ? %s "
 		}
 
+		// 行級資料權限，不在範圍內的資料視為不存在
+		if scope, scopeArgs := tb.rowScope.Statement(tableName, delimiter); scope != "" {
+			queryStatement = strings.Replace(queryStatement, " = ? ", " = ? and "+scope+" ", 1)
+			args = append(args, scopeArgs...)
+		}
+
+		// 軟刪除的資料只能在回收站中查看
+		if cond := tb.softDeleteCondition(tableName, param.IsTrash()); cond != "" {
+			queryStatement = strings.Replace(queryStatement, " = ? ", " = ? and "+cond+" ", 1)
+		}
+
 		// tb.Form.FieldList為表單所有欄位資訊
 		for _, field := range tb.Form.FieldList {
 
@@ -754,9 +932,9 @@ func (tb *DefaultTable) GetDataWithId(param parameter.Parameters) (FormInfo, err
 		logger.LogSQL(queryCmd, args)
 
 		// 取得單筆資料(利用id)
-		// QueryWithConnection(connection方法)在admin\modules\db\mysql.go
-		// QueryWithConnection有給定連接(tb.connection)名稱，透過參數tb.connection查詢db.DbList[tb.connection]資料並回傳
-		result, err := connection.QueryWithConnection(tb.connection, queryCmd, args...)
+		// QueryWithConnectionContext(connection方法)在admin\modules\db\mysql.go
+		// 有給定連接(tb.connection)名稱，透過參數tb.connection查詢db.DbList[tb.connection]資料並回傳，請求中斷時停止查詢
+		result, err := connection.QueryWithConnectionContext(tb.context(), tb.connection, queryCmd, args...)
 		if err != nil {
 			// tb.Form.Title主題左上角(ex:菜單管理)
 			// tb.Form.Description主題旁邊的描述(ex:菜單管理)
@@ -821,6 +999,10 @@ func (tb *DefaultTable) UpdateData(dataList form.Values) error {
 	// -------用戶編輯介面不會執行---------
 	if tb.Form.PostHook != nil {
 		defer func() {
+			// 等待審核時尚未寫入
+			if err == ErrChangePending {
+				return
+			}
 			// PostTypeKey = __go_admin_post_type
 			dataList.Add(form.PostTypeKey, "0")
 			// PostResultKey = __go_admin_post_result
@@ -840,6 +1022,12 @@ func (tb *DefaultTable) UpdateData(dataList form.Values) error {
 		}()
 	}
 
+	// 欄位權限
+	if err = tb.checkFieldPermission(dataList); err != nil {
+		errMsg = "post error: " + err.Error()
+		return err
+	}
+
 	// 編輯頁面時一般tb.Form.Validator = nil
 	// -------用戶編輯介面不會執行---------
 	if tb.Form.Validator != nil {
@@ -849,12 +1037,46 @@ func (tb *DefaultTable) UpdateData(dataList form.Values) error {
 		}
 	}
 
+	// 需要審核時保留PreProcessFn處理前的值，核准後再重新處理
+	submitted := copyValues(dataList)
+
 	// 編輯頁面時一般tb.Form.PreProcessFn = nil
 	// -------用戶編輯介面不會執行---------
 	if tb.Form.PreProcessFn != nil {
 		dataList = tb.Form.PreProcessFn(dataList)
 	}
 
+	// 行級資料權限，原資料及更新後的資料都必須在範圍內
+	if err = tb.checkRowScope(tb.Form.Table, []string{dataList.Get(tb.PrimaryKey.Name)}); err != nil {
+		errMsg = "post error: " + err.Error()
+		return err
+	}
+	if err = tb.checkRowScopeValues(tb.Form.Table, dataList.Get(tb.PrimaryKey.Name), dataList); err != nil {
+		errMsg = "post error: " + err.Error()
+		return err
+	}
+
+	// 回收站中的資料不可編輯
+	if err = tb.checkSoftDeleted(tb.Form.Table, []string{dataList.Get(tb.PrimaryKey.Name)}, false); err != nil {
+		errMsg = "post error: " + err.Error()
+		return err
+	}
+
+	// 建立待審核的變更申請
+	if tb.needApproval() {
+		err = tb.submitChange(tb.Form.Table, models.AuditUpdate, []string{dataList.Get(tb.PrimaryKey.Name)}, submitted)
+		if err != ErrChangePending {
+			errMsg = "post error: " + err.Error()
+		}
+		return err
+	}
+
+	// 變更紀錄，記錄更新前的資料
+	var (
+		ids    = []string{dataList.Get(tb.PrimaryKey.Name)}
+		before = tb.auditSnapshot(tb.Form.Table, ids)
+	)
+
 	if tb.Form.UpdateFn != nil {
 		// ----------用戶、角色會執行-----------
 		// PostTypeKey = __go_admin_post_type
@@ -864,8 +1086,10 @@ func (tb *DefaultTable) UpdateData(dataList form.Values) error {
 		err = tb.Form.UpdateFn(dataList)
 		if err != nil {
 			errMsg = "post error: " + err.Error()
+			return err
 		}
-		return err
+		tb.audit(tb.Form.Table, models.AuditUpdate, before, tb.auditSnapshot(tb.Form.Table, ids))
+		return nil
 	}
 
 	// ------------權限會執行--------------
@@ -882,6 +1106,8 @@ func (tb *DefaultTable) UpdateData(dataList form.Values) error {
 		return err
 	}
 
+	tb.audit(tb.Form.Table, models.AuditUpdate, before, tb.auditSnapshot(tb.Form.Table, ids))
+
 	return nil
 }
 
@@ -903,6 +1129,10 @@ func (tb *DefaultTable) InsertData(dataList form.Values) error {
 	// -------------只有新增權限會執行----------------
 	if tb.Form.PostHook != nil {
 		defer func() {
+			// 等待審核時尚未寫入
+			if err == ErrChangePending {
+				return
+			}
 			// PostTypeKey = __go_admin_post_type
 			dataList.Add(form.PostTypeKey, "1")
 			dataList.Add(tb.GetPrimaryKey().Name, strconv.Itoa(int(id)))
@@ -924,6 +1154,12 @@ func (tb *DefaultTable) InsertData(dataList form.Values) error {
 		}()
 	}
 
+	// 欄位權限
+	if err = tb.checkFieldPermission(dataList); err != nil {
+		errMsg = "post error: " + err.Error()
+		return err
+	}
+
 	// -------------只有新增權限會執行----------------
 	if tb.Form.Validator != nil {
 		if err := tb.Form.Validator(dataList); err != nil {
@@ -932,9 +1168,30 @@ func (tb *DefaultTable) InsertData(dataList form.Values) error {
 		}
 	}
 
+	// 行級資料權限，新增的資料必須在範圍內
+	if err = tb.checkRowScopeValues(tb.Form.Table, "", dataList); err != nil {
+		errMsg = "post error: " + err.Error()
+		return err
+	}
+
+	// 建立待審核的變更申請，核准後再執行PreProcessFn
+	if tb.needApproval() {
+		err = tb.submitChange(tb.Form.Table, models.AuditInsert, nil, copyValues(dataList))
+		if err != ErrChangePending {
+			errMsg = "post error: " + err.Error()
+		}
+		return err
+	}
+
 	// 新增頁面都為nil
 	if tb.Form.PreProcessFn != nil {
 		dataList = tb.Form.PreProcessFn(dataList)
+
+		// PreProcessFn可能修改了資料，再檢查一次行級資料權限
+		if err = tb.checkRowScopeValues(tb.Form.Table, "", dataList); err != nil {
+			errMsg = "post error: " + err.Error()
+			return err
+		}
 	}
 
 	// 用戶及角色頁面都不為空，會執行新增資料的動作，執行後return結果
@@ -945,8 +1202,13 @@ func (tb *DefaultTable) InsertData(dataList form.Values) error {
 		err = tb.Form.InsertFn(dataList)
 		if err != nil {
 			errMsg = "post error: " + err.Error()
+			return err
 		}
-		return err
+		// 無法取得新增資料的主鍵，以提交的值記錄
+		tb.audit(tb.Form.Table, models.AuditInsert, nil, map[string]map[string]interface{}{
+			dataList.Get(tb.PrimaryKey.Name): auditFormValues(dataList),
+		})
+		return nil
 	}
 
 	// --------------新增權限頁面才會執行下面動作，用戶及角色在上面動作已經return------------
@@ -962,6 +1224,12 @@ func (tb *DefaultTable) InsertData(dataList form.Values) error {
 		return err
 	}
 
+	pk := dataList.Get(tb.PrimaryKey.Name)
+	if id != 0 {
+		pk = strconv.FormatInt(id, 10)
+	}
+	tb.audit(tb.Form.Table, models.AuditInsert, nil, tb.auditSnapshot(tb.Form.Table, []string{pk}))
+
 	return nil
 }
 
@@ -1078,6 +1346,10 @@ func (tb *DefaultTable) DeleteData(id string) error {
 
 	if tb.Info.DeleteHook != nil {
 		defer func() {
+			// 等待審核時尚未刪除
+			if err == ErrChangePending {
+				return
+			}
 			go func() {
 				defer func() {
 					if recoverErr := recover(); recoverErr != nil {
@@ -1094,6 +1366,9 @@ func (tb *DefaultTable) DeleteData(id string) error {
 
 	if tb.Info.DeleteHookWithRes != nil {
 		defer func() {
+			if err == ErrChangePending {
+				return
+			}
 			go func() {
 				defer func() {
 					if recoverErr := recover(); recoverErr != nil {
@@ -1108,14 +1383,30 @@ func (tb *DefaultTable) DeleteData(id string) error {
 		}()
 	}
 
+	// 行級資料權限
+	if err = tb.checkRowScope(tb.Info.Table, idArr); err != nil {
+		return err
+	}
+
+	// 每筆資料各建立一個待審核的變更申請
+	if tb.needApproval() {
+		err = tb.submitChange(tb.Info.Table, models.AuditDelete, idArr, nil)
+		return err
+	}
+
 	if tb.Info.PreDeleteFn != nil {
 		if err = tb.Info.PreDeleteFn(idArr); err != nil {
 			return err
 		}
 	}
 
+	// 變更紀錄，記錄刪除前的資料
+	before := tb.auditSnapshot(tb.Info.Table, idArr)
+
 	if tb.Info.DeleteFn != nil {
-		err = tb.Info.DeleteFn(idArr)
+		if err = tb.Info.DeleteFn(idArr); err == nil {
+			tb.audit(tb.Info.Table, models.AuditDelete, before, nil)
+		}
 		return err
 	}
 
@@ -1124,7 +1415,17 @@ func (tb *DefaultTable) DeleteData(id string) error {
 		return err
 	}
 
-	err = tb.delete(tb.Info.Table, tb.PrimaryKey.Name, idArr)
+	// 軟刪除，將資料移至回收站
+	if tb.SoftDelete != "" && tb.getDataFromDB() {
+		if err = tb.softDelete(tb.Info.Table, idArr); err == nil {
+			tb.audit(tb.Info.Table, models.AuditDelete, before, tb.auditSnapshot(tb.Info.Table, idArr))
+		}
+		return err
+	}
+
+	if err = tb.delete(tb.Info.Table, tb.PrimaryKey.Name, idArr); err == nil {
+		tb.audit(tb.Info.Table, models.AuditDelete, before, nil)
+	}
 	return err
 }
 
@@ -1159,6 +1460,119 @@ func (tb *DefaultTable) delete(table, key string, values []string) error {
 		Delete()
 }
 
+// 回傳移除沒有權限的欄位後的columns
+func (tb *DefaultTable) allowedColumns(columns Columns) Columns {
+	if len(tb.forbiddenFields) == 0 {
+		return columns
+	}
+	allowed := make(Columns, 0, len(columns))
+	for _, column := range columns {
+		if !modules.InArray(tb.forbiddenFields, column) {
+			allowed = append(allowed, column)
+		}
+	}
+	return allowed
+}
+
+// checkFieldPermission reject the values of the fields which the user has no permission of.
+// 提交的資料包含沒有權限的欄位時回傳沒有權限
+func (tb *DefaultTable) checkFieldPermission(dataList form.Values) error {
+	for _, field := range tb.forbiddenFields {
+		_, ok := dataList[field]
+		_, okArr := dataList[field+"[]"]
+		if ok || okArr {
+			return errors.New(errs.NoPermission)
+		}
+	}
+	return nil
+}
+
+// 將行級資料權限的條件以and加入wheres
+func (tb *DefaultTable) rowScopeStatement(table, wheres string, whereArgs []interface{}) (string, []interface{}) {
+	scope, args := tb.rowScope.Statement(table, tb.delimiter())
+	if scope == "" {
+		return wheres, whereArgs
+	}
+	return andWhere(wheres, scope), append(whereArgs, args...)
+}
+
+// 以and將條件加入wheres，原本的條件以括號包住，避免其中的or(WhereOr、WhereRaw)改變優先順序
+func andWhere(wheres, cond string) string {
+	if wheres == "" {
+		return cond + " "
+	}
+	return "(" + wheres + ") and " + cond + " "
+}
+
+// checkRowScope check the rows of ids are all in the row scope.
+// 檢查資料是否都在行級資料權限的範圍內，否則回傳沒有權限
+func (tb *DefaultTable) checkRowScope(table string, ids []string) error {
+	if tb.rowScope == nil || !tb.getDataFromDB() || table == "" {
+		return nil
+	}
+
+	vals := uniqueIds(ids)
+	if len(vals) == 0 {
+		return nil
+	}
+
+	// 寫入前的檢查在主資料庫讀取，避免讀取副本尚未同步
+	scope, args := tb.rowScope.Statement(table, tb.delimiter())
+	res, err := tb.sql().UsePrimary().Table(table).
+		Select(tb.PrimaryKey.Name).
+		WhereIn(tb.PrimaryKey.Name, vals).
+		WhereRaw(scope, args...).
+		All()
+	if err != nil {
+		return err
+	}
+	if len(res) != len(vals) {
+		return errors.New(errs.NoPermission)
+	}
+	return nil
+}
+
+// checkRowScopeValues check the row is in the row scope after the posted values are written,
+// id is empty when inserting.
+// 檢查寫入後的資料是否在行級資料權限的範圍內：提交的值優先，未提交的欄位以原資料的值檢查，
+// 新增資料時(id為空)未提交的欄位視為不符合
+func (tb *DefaultTable) checkRowScopeValues(table, id string, dataList form.Values) error {
+	if tb.rowScope == nil || !tb.getDataFromDB() || table == "" {
+		return nil
+	}
+
+	var (
+		fields = tb.rowScope.Fields()
+		values = make(map[string]string)
+	)
+
+	if id != "" && len(fields) > 0 {
+		row, err := tb.sql().UsePrimary().Table(table).
+			Select(fields...).
+			Where(tb.PrimaryKey.Name, "=", id).
+			First()
+		if err != nil {
+			return err
+		}
+		for _, field := range fields {
+			if v, ok := row[field]; ok && v != nil {
+				values[field] = rowScopeString(v)
+			}
+		}
+	}
+
+	for _, field := range fields {
+		if len(dataList[field]) > 0 {
+			values[field] = dataList.Get(field)
+		}
+	}
+
+	if !tb.rowScope.Match(values) {
+		return errors.New(errs.NoPermission)
+	}
+	return nil
+}
+
 // 透過參數並且將欄位、join語法...等資訊處理後，回傳[]TheadItem、欄位名稱、joinFields(ex:group_concat(goadmin_roles.`name`...)、合併的資料表、可篩選過濾的欄位
 func (tb *DefaultTable) getTheadAndFilterForm(params parameter.Parameters, columns Columns) (types.Thead,
 	string, string, string, []string, []types.FormField) {
@@ -1204,7 +1618,10 @@ func (tb *DefaultTable) sql() *db.SQL {
 	// getDataFromDB(從資料庫取得資料)判斷條件
 	if tb.connectionDriver != "" && tb.getDataFromDB() {
 		// WithDriverAndConnection將參數設置(connName、conn)並回傳sql(struct)
-		return db.WithDriverAndConnection(tb.connection, db.GetConnectionFromService(services.Get(tb.connectionDriver)))
+		// 套用變更申請時在同一個交易中寫入
+		return db.WithDriverAndConnection(tb.connection, db.GetConnectionFromService(services.Get(tb.connectionDriver))).
+			WithTx(tb.tx).
+			WithContext(tb.context())
 	}
 	return nil
 }
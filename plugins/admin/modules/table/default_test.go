@@ -1 +1,74 @@
 package table
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	_ "github.com/GoAdminGroup/go-admin/modules/db/drivers/sqlite"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportData(t *testing.T) {
+	data, err := ioutil.ReadFile("../../../../tests/data/admin.db")
+	assert.Equal(t, err, nil)
+	dir, err := ioutil.TempDir("", "goadmin")
+	assert.Equal(t, err, nil)
+	defer func() { _ = os.RemoveAll(dir) }()
+	file := filepath.Join(dir, "admin.db")
+	assert.Equal(t, ioutil.WriteFile(file, data, os.ModePerm), nil)
+
+	conn := db.GetConnectionByDriver(db.DriverSqlite).InitDB(map[string]config.Database{
+		"default": {Driver: db.DriverSqlite, File: file},
+	})
+	defer conn.Close()
+	services = service.List{db.DriverSqlite: conn}
+
+	tb := NewDefaultTable(DefaultConfigWithDriver(db.DriverSqlite)).(*DefaultTable)
+	tb.GetInfo().AddField("ID", "id", db.Int)
+	tb.GetInfo().AddField("Slug", "slug", db.Varchar)
+	tb.GetInfo().SetTable("goadmin_permissions")
+
+	params := parameter.BaseParam().WithIsAll(true)
+
+	// the rows are read by chunks
+	sizes := make([]int, 0)
+	slugs := make([]interface{}, 0)
+	err = tb.eachDataFromDatabase(params, 1, func(info PanelInfo) error {
+		sizes = append(sizes, len(info.InfoList))
+		for _, item := range info.InfoList {
+			slugs = append(slugs, item["slug"].Value)
+		}
+		return nil
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, sizes, []int{1, 1})
+	assert.Equal(t, len(slugs), 2)
+
+	calls := 0
+	err = tb.ExportData(params, func(info PanelInfo) error {
+		calls++
+		assert.Equal(t, len(info.InfoList), 2)
+		return nil
+	})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, calls, 1)
+
+	// the export fails once the rows exceed the limit
+	tb.ExportLimit = 1
+	err = tb.ExportData(params, func(info PanelInfo) error { return nil })
+	assert.Equal(t, err, ErrExportLimit)
+	tb.ExportLimit = -1
+	assert.Equal(t, tb.ExportData(params, func(info PanelInfo) error { return nil }), nil)
+	assert.Equal(t, NewDefaultTable(DefaultConfig().SetExportLimit(100)).(*DefaultTable).exportLimit(), 100)
+	assert.Equal(t, NewDefaultTable(DefaultConfig()).(*DefaultTable).exportLimit(), DefaultExportLimit)
+
+	all, err := tb.GetData(params)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, all.Total, 2)
+}
@@ -0,0 +1,79 @@
+package table
+
+import (
+	"html/template"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/template/types"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+)
+
+// FieldPermissionFn check whether the user has one of the permissions(slug).
+type FieldPermissionFn func(slugs []string) bool
+
+// 沒有權限的表單欄位以唯讀方式顯示，且不產生input，避免提交該欄位
+const fieldReadOnlyContent = `<div class="box box-solid box-default no-margin">
+    <div class="box-body" style="min-height: 40px;">{{.Value}}</div>
+</div>`
+
+// UserFieldPermission return the FieldPermissionFn of the login user, the super administrator
+// has all the permissions.
+// 回傳目前登入用戶的欄位權限檢查，超級管理員可以查看及編輯所有欄位
+func UserFieldPermission(ctx *context.Context) FieldPermissionFn {
+	user, ok := ctx.User().(models.UserModel)
+	return func(slugs []string) bool {
+		if !ok {
+			return false
+		}
+		if user.IsSuperAdmin() {
+			return true
+		}
+		for _, slug := range slugs {
+			if user.CheckPermission(slug) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// 移除沒有權限的欄位，並回傳被移除的欄位名稱
+func filterInfoFields(list types.FieldList, fn FieldPermissionFn) (types.FieldList, []string) {
+	var (
+		allowed   = make(types.FieldList, 0, len(list))
+		forbidden = make([]string, 0)
+	)
+	for _, field := range list {
+		if len(field.Permissions) > 0 && !fn(field.Permissions) {
+			forbidden = append(forbidden, field.Field)
+			continue
+		}
+		allowed = append(allowed, field)
+	}
+	return allowed, forbidden
+}
+
+// 將沒有權限的表單欄位設為唯讀，新增表單不顯示，並回傳該些欄位名稱
+func readOnlyFormFields(list types.FormFields, fn FieldPermissionFn) []string {
+	var forbidden = make([]string, 0)
+	for i := range list {
+		if len(list[i].Permissions) > 0 && !fn(list[i].Permissions) {
+			forbidden = append(forbidden, list[i].Field)
+			list[i].FormType = form.Custom
+			list[i].CustomContent = fieldReadOnlyContent
+			// 唯讀內容以{{.Value}}(template.HTML)輸出不會被轉義，顯示前先轉義欄位的值
+			display := list[i].FieldDisplay
+			list[i].Display = func(value types.FieldModel) interface{} {
+				return template.HTML(template.HTMLEscapeString(string(display.ToDisplayHTML(value))))
+			}
+			list[i].DisplayProcessChains = nil
+			list[i].CustomJs = ""
+			list[i].CustomCss = ""
+			list[i].Editable = false
+			list[i].NotAllowAdd = true
+			list[i].Must = false
+		}
+	}
+	return forbidden
+}
@@ -0,0 +1,57 @@
+package table
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	errs "github.com/GoAdminGroup/go-admin/modules/errors"
+	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
+	"github.com/GoAdminGroup/go-admin/template/types/form"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFieldPermission(t *testing.T) {
+	tb := NewDefaultTable(DefaultConfig()).(*DefaultTable)
+
+	info := tb.GetInfo()
+	info.AddField("ID", "id", db.Int)
+	info.AddField("Name", "name", db.Varchar)
+	info.AddField("Salary", "salary", db.Int).FieldPermission("hr", "finance")
+
+	formList := tb.GetForm()
+	formList.AddField("Name", "name", db.Varchar, form.Text).FieldMust()
+	formList.AddField("Salary", "salary", db.Int, form.Text).FieldMust().FieldPermission("hr")
+
+	tb.SetFieldPermission(func(slugs []string) bool {
+		for _, slug := range slugs {
+			if slug == "support" {
+				return true
+			}
+		}
+		return false
+	})
+
+	assert.Equal(t, len(tb.Info.FieldList), 2)
+	assert.Equal(t, tb.Info.FieldList[1].Field, "name")
+
+	salary := tb.Form.FieldList.FindByFieldName("salary")
+	assert.Equal(t, salary.FormType, form.Custom)
+	assert.Equal(t, salary.Editable, false)
+	assert.Equal(t, salary.NotAllowAdd, true)
+	assert.Equal(t, salary.Must, false)
+	assert.Equal(t, tb.Form.FieldList.FindByFieldName("name").FormType, form.Text)
+
+	assert.Equal(t, tb.allowedColumns(Columns{"id", "name", "salary"}), Columns{"id", "name"})
+
+	assert.Equal(t, tb.checkFieldPermission(form2.Values{"name": {"jack"}}), nil)
+	assert.Equal(t, tb.checkFieldPermission(form2.Values{"salary": {""}}).Error(), errs.NoPermission)
+
+	copied := tb.Copy().(*DefaultTable)
+	assert.Equal(t, copied.allowedColumns(Columns{"salary"}), Columns{})
+
+	// the value of the read only field is escaped
+	salary.UpdateValue("1", "<script>alert(1)</script>", map[string]interface{}{}, nil).FillCustomContent()
+	assert.Equal(t, strings.Contains(string(salary.CustomContent), "<script>"), false)
+	assert.Equal(t, strings.Contains(string(salary.CustomContent), "&lt;script&gt;alert(1)&lt;/script&gt;"), true)
+}
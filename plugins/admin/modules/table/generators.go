@@ -2,11 +2,13 @@ package table
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/modules/ui"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/tools"
 	tmpl "html/template"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
@@ -14,18 +16,22 @@ import (
 	"time"
 
 	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/auth"
 	"github.com/GoAdminGroup/go-admin/modules/collection"
 	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/constant"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
 	errs "github.com/GoAdminGroup/go-admin/modules/errors"
 	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/modules/page"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	form2 "github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
 	"github.com/GoAdminGroup/go-admin/template"
+	"github.com/GoAdminGroup/go-admin/template/icon"
 	"github.com/GoAdminGroup/go-admin/template/types"
 	"github.com/GoAdminGroup/go-admin/template/types/action"
 	"github.com/GoAdminGroup/go-admin/template/types/form"
@@ -85,6 +91,16 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (managerTable Table)
 	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 	info.AddField(lg("updatedAt"), "updated_at", db.Timestamp)
 
+	// 登入中的session，只有儲存驅動支援列出session時才顯示
+	sessionListable := auth.SessionListable(s.conn)
+	if sessionListable {
+		info.AddButton(tmpl.HTML(lg("sessions")), icon.Desktop, action.Jump(config.Url("/info/session")))
+	}
+	info.AddButton(tmpl.HTML(lg("api keys")), icon.Key, action.Jump(config.Url("/info/api_keys")))
+	if sessionListable {
+		info.AddActionButton(tmpl.HTML(lg("sessions")), action.Jump(config.Url("/info/session?user_id={{.Id}}")))
+	}
+
 	info.SetTable("goadmin_users").
 		SetTitle(lg("Managers")).
 		SetDescription(lg("Managers")).
@@ -112,6 +128,15 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (managerTable Table)
 					return deleteUserPermissionErr, nil
 				}
 
+				deleteUserIdentityErr := s.connection().WithTx(tx).
+					Table("goadmin_user_identities").
+					WhereIn("user_id", ids).
+					Delete()
+
+				if db.CheckError(deleteUserIdentityErr, db.DELETE) {
+					return deleteUserIdentityErr, nil
+				}
+
 				deleteUserErr := s.connection().WithTx(tx).
 					Table("goadmin_users").
 					WhereIn("id", ids).
@@ -124,6 +149,16 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (managerTable Table)
 				return nil, nil
 			})
 
+			// 撤銷被刪除用戶的token及session，避免id被重複使用時舊的token仍然有效
+			if txErr == nil {
+				for _, id := range idArr {
+					userId, _ := strconv.ParseInt(id, 10, 64)
+					if err := auth.RevokeUserSessions(s.conn, userId, ""); err != nil {
+						logger.Error("revoke sessions error: ", err)
+					}
+				}
+			}
+
 			return txErr
 		})
 
@@ -195,9 +230,16 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (managerTable Table)
 				return errors.New("password does not match")
 			}
 
+			// 檢查密碼規則(長度、字元類型、是否與最近的密碼重複)
+			if err := auth.CheckPasswordPolicy(user.Id, password, s.conn); err != nil {
+				return err
+			}
+
 			password = encodePassword([]byte(values.Get("password")))
 		}
 
+		oldRoleIds := user.WithRoles().GetAllRoleId()
+
 		_, txErr := s.connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
 
 			_, updateUserErr := user.WithTx(tx).Update(values.Get("username"), password, values.Get("name"), values.Get("avatar"))
@@ -235,6 +277,17 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (managerTable Table)
 			return nil, nil
 		})
 
+		if txErr == nil && password != "" {
+			auth.SavePasswordHistory(user.Id, password, s.conn)
+		}
+
+		// 密碼或角色變更時撤銷該用戶其他的session
+		if txErr == nil && (password != "" || !sameIds(oldRoleIds, values["role_id[]"])) {
+			if err := auth.RevokeUserSessions(s.conn, user.Id, auth.CurrentSid(ctx)); err != nil {
+				logger.Error("revoke sessions error: ", err)
+			}
+		}
+
 		return txErr
 	})
 	formList.SetInsertFn(func(values form2.Values) error {
@@ -248,10 +301,19 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (managerTable Table)
 			return errors.New("password does not match")
 		}
 
+		if err := auth.CheckPasswordPolicy(0, password, s.conn); err != nil {
+			return err
+		}
+
+		var (
+			userId int64
+			hash   = encodePassword([]byte(password))
+		)
+
 		_, txErr := s.connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
 
 			user, createUserErr := models.User().WithTx(tx).SetConn(s.conn).New(values.Get("username"),
-				encodePassword([]byte(values.Get("password"))),
+				hash,
 				values.Get("name"),
 				values.Get("avatar"))
 
@@ -259,6 +321,8 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (managerTable Table)
 				return createUserErr, nil
 			}
 
+			userId = user.Id
+
 			for i := 0; i < len(values["role_id[]"]); i++ {
 				_, addRoleErr := user.WithTx(tx).AddRole(values["role_id[]"][i])
 				if db.CheckError(addRoleErr, db.INSERT) {
@@ -275,6 +339,11 @@ func (s *SystemTable) GetManagerTable(ctx *context.Context) (managerTable Table)
 
 			return nil, nil
 		})
+
+		if txErr == nil {
+			auth.SavePasswordHistory(userId, hash, s.conn)
+		}
+
 		return txErr
 	})
 
@@ -414,6 +483,15 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable
 					return deleteUserPermissionErr, nil
 				}
 
+				deleteUserIdentityErr := s.connection().WithTx(tx).
+					Table("goadmin_user_identities").
+					WhereIn("user_id", ids).
+					Delete()
+
+				if db.CheckError(deleteUserIdentityErr, db.DELETE) {
+					return deleteUserIdentityErr, nil
+				}
+
 				deleteUserErr := s.connection().WithTx(tx).
 					Table("goadmin_users").
 					WhereIn("id", ids).
@@ -426,6 +504,16 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable
 				return nil, nil
 			})
 
+			// 撤銷被刪除用戶的token及session，避免id被重複使用時舊的token仍然有效
+			if txErr == nil {
+				for _, id := range idArr {
+					userId, _ := strconv.ParseInt(id, 10, 64)
+					if err := auth.RevokeUserSessions(s.conn, userId, ""); err != nil {
+						logger.Error("revoke sessions error: ", err)
+					}
+				}
+			}
+
 			return txErr
 		})
 
@@ -445,6 +533,9 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable
 		})
 
 	formList.SetTable("goadmin_users").SetTitle(lg("Managers")).SetDescription(lg("Managers"))
+	// 用戶中心的兩步驟驗證設置入口
+	formList.SetHeaderHtml(template.HTML(`<a class="btn btn-sm btn-default" href="` + config.Url("/totp") + `">` +
+		lg("two factor authentication") + `</a>`))
 	formList.SetUpdateFn(func(values form2.Values) error {
 
 		if values.IsEmpty("name", "username") {
@@ -465,6 +556,10 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable
 				return errors.New("password does not match")
 			}
 
+			if err := auth.CheckPasswordPolicy(user.Id, password, s.conn); err != nil {
+				return err
+			}
+
 			password = encodePassword([]byte(values.Get("password")))
 		}
 
@@ -474,6 +569,15 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable
 			return updateUserErr
 		}
 
+		if password != "" {
+			auth.SavePasswordHistory(user.Id, password, s.conn)
+
+			// 密碼變更時撤銷其他的session
+			if err := auth.RevokeUserSessions(s.conn, user.Id, auth.CurrentSid(ctx)); err != nil {
+				logger.Error("revoke sessions error: ", err)
+			}
+		}
+
 		return nil
 	})
 	formList.SetInsertFn(func(values form2.Values) error {
@@ -491,8 +595,14 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable
 			return errors.New(errs.NoPermission)
 		}
 
-		_, createUserErr := models.User().SetConn(s.conn).New(values.Get("username"),
-			encodePassword([]byte(values.Get("password"))),
+		if err := auth.CheckPasswordPolicy(0, password, s.conn); err != nil {
+			return err
+		}
+
+		hash := encodePassword([]byte(password))
+
+		user, createUserErr := models.User().SetConn(s.conn).New(values.Get("username"),
+			hash,
 			values.Get("name"),
 			values.Get("avatar"))
 
@@ -500,6 +610,8 @@ func (s *SystemTable) GetNormalManagerTable(ctx *context.Context) (managerTable
 			return createUserErr
 		}
 
+		auth.SavePasswordHistory(user.Id, hash, s.conn)
+
 		return nil
 	})
 
@@ -525,10 +637,15 @@ func (s *SystemTable) GetPermissionTable(ctx *context.Context) (permissionTable
 			pathArr := strings.Split(model.Value, "\n")
 			res := ""
 			for i := 0; i < len(pathArr); i++ {
+				// 拒絕規則以紅色標籤顯示
+				lb := label().SetContent(template.HTML(pathArr[i]))
+				if strings.HasPrefix(strings.TrimSpace(pathArr[i]), models.PermissionDenyPrefix) {
+					lb = lb.SetType("danger")
+				}
 				if i == len(pathArr)-1 {
-					res += string(label().SetContent(template.HTML(pathArr[i])).GetContent())
+					res += string(lb.GetContent())
 				} else {
-					res += string(label().SetContent(template.HTML(pathArr[i])).GetContent()) + "<br><br>"
+					res += string(lb.GetContent()) + "<br><br>"
 				}
 			}
 			return res
@@ -592,6 +709,9 @@ func (s *SystemTable) GetPermissionTable(ctx *context.Context) (permissionTable
 			{Value: "PATCH", Text: "PATCH"},
 			{Value: "OPTIONS", Text: "OPTIONS"},
 			{Value: "HEAD", Text: "HEAD"},
+			{Value: "READ", Text: "READ(GET, HEAD, OPTIONS)"},
+			{Value: "WRITE", Text: "WRITE(POST, PUT, PATCH, DELETE)"},
+			{Value: "ANY", Text: "ANY"},
 		}).
 		FieldDisplay(func(model types.FieldModel) interface{} {
 			return strings.Split(model.Value, ",")
@@ -605,7 +725,41 @@ func (s *SystemTable) GetPermissionTable(ctx *context.Context) (permissionTable
 		FieldPostFilterFn(func(model types.PostFieldModel) interface{} {
 			return strings.TrimSpace(model.Value.Value())
 		}).
-		FieldHelpMsg(template.HTML(lg("a path a line, without global prefix")))
+		FieldHelpMsg(template.HTML(lg("a path a line, without global prefix") + "<br>" + lg("permission path syntax")))
+	// 測試權限規則，列出被允許或拒絕的路由
+	formList.AddField(lg("test permission"), "permission_test", db.Varchar, form.Custom).
+		FieldCustomContent(template.HTML(`<button type="button" class="btn btn-sm btn-default permission-test-btn">` +
+			lg("test permission") + `</button><div class="permission-test-result" style="margin-top:10px;"></div>`)).
+		FieldCustomJs(template.JS(`$(".permission-test-btn").on("click", function () {
+	var method = $("select.http_method").val();
+	$.ajax({
+		method: "post",
+		url: "` + config.Url("/permission/test") + `",
+		data: {
+			http_method: method ? method.join(",") : "",
+			http_path: $("textarea[name='http_path']").val()
+		},
+		success: function (data) {
+			var box = $(".permission-test-result").empty();
+			var routes = (data.data && data.data.routes) || [];
+			if (routes.length === 0) {
+				box.text("` + lg("no route matched") + `");
+				return;
+			}
+			var table = $("<table class='table table-condensed'></table>");
+			$.each(routes, function (i, route) {
+				var effect = $("<span class='label'></span>").text(route.effect === "deny" ? "` + lg("deny") + `" : "` + lg("grant") + `").
+					addClass(route.effect === "deny" ? "label-danger" : "label-success");
+				table.append($("<tr></tr>").
+					append($("<td></td>").append(effect)).
+					append($("<td></td>").text(route.method)).
+					append($("<td></td>").text(route.path)).
+					append($("<td></td>").text(route.name)));
+			});
+			box.append(table);
+		}
+	});
+});`))
 	formList.AddField(lg("updatedAt"), "updated_at", db.Timestamp, form.Default).FieldNotAllowAdd()
 	formList.AddField(lg("createdAt"), "created_at", db.Timestamp, form.Default).FieldNotAllowAdd()
 
@@ -641,6 +795,42 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 	info.AddField("ID", "id", db.Int).FieldSortable()
 	info.AddField(lg("role"), "name", db.Varchar).FieldFilterable()
 	info.AddField(lg("slug"), "slug", db.Varchar).FieldFilterable()
+	info.AddField(lg("parent roles"), "parent_roles", db.Varchar).
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			parentIds := models.RoleWithId(model.ID).SetConn(s.conn).ParentIds()
+			if len(parentIds) == 0 {
+				return "-"
+			}
+			ids := make([]interface{}, len(parentIds))
+			for i, id := range parentIds {
+				ids[i] = id
+			}
+			roles, _ := s.table("goadmin_roles").Select("name").WhereIn("id", ids).All()
+			names := make([]string, 0, len(roles))
+			for _, role := range roles {
+				name, _ := role["name"].(string)
+				names = append(names, name)
+			}
+			return roleLabels(names, "primary")
+		})
+	info.AddField(lg("effective permissions"), "effective_permissions", db.Varchar).
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			// 角色本身的權限以綠色顯示，繼承自上層角色的權限以灰色顯示
+			own, inherited := models.RoleWithId(model.ID).SetConn(s.conn).EffectivePermissions()
+			ownNames := make([]string, len(own))
+			for i, permission := range own {
+				ownNames[i] = permission.Name
+			}
+			inheritedNames := make([]string, len(inherited))
+			for i, permission := range inherited {
+				inheritedNames[i] = permission.Name
+			}
+			labels := roleLabels(ownNames, "success") + roleLabels(inheritedNames, "default")
+			if labels == "" {
+				return "-"
+			}
+			return labels
+		})
 	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 	info.AddField(lg("updatedAt"), "updated_at", db.Timestamp)
 
@@ -680,6 +870,24 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 					return deleteRolePermissionErr, nil
 				}
 
+				deleteRoleParentErr := s.connection().WithTx(tx).
+					Table("goadmin_role_parents").
+					WhereIn("role_id", ids).
+					Delete()
+
+				if db.CheckError(deleteRoleParentErr, db.DELETE) {
+					return deleteRoleParentErr, nil
+				}
+
+				deleteRoleChildErr := s.connection().WithTx(tx).
+					Table("goadmin_role_parents").
+					WhereIn("parent_id", ids).
+					Delete()
+
+				if db.CheckError(deleteRoleChildErr, db.DELETE) {
+					return deleteRoleChildErr, nil
+				}
+
 				deleteRolesErr := s.connection().WithTx(tx).
 					Table("goadmin_roles").
 					WhereIn("id", ids).
@@ -718,6 +926,19 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 			return permissions
 		}).FieldHelpMsg(template.HTML(lg("no corresponding options?")) +
 		link("/admin/info/permission/new", "Create here."))
+	formList.AddField(lg("parent roles"), "parent_id", db.Varchar, form.SelectBox).
+		FieldOptionsFromTable("goadmin_roles", "name", "id").
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			var parents = make([]string, 0)
+
+			if model.ID == "" {
+				return parents
+			}
+			for _, id := range models.RoleWithId(model.ID).SetConn(s.conn).ParentIds() {
+				parents = append(parents, strconv.FormatInt(id, 10))
+			}
+			return parents
+		}).FieldHelpMsg(template.HTML(lg("inherit the permissions of the parent roles")))
 
 	formList.AddField(lg("updatedAt"), "updated_at", db.Timestamp, form.Default).FieldNotAllowAdd()
 	formList.AddField(lg("createdAt"), "created_at", db.Timestamp, form.Default).FieldNotAllowAdd()
@@ -734,6 +955,11 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 
 		role := models.RoleWithId(values.Get("id")).SetConn(s.conn)
 
+		// 上層角色不可造成循環繼承
+		if err := role.CheckParents(values["parent_id[]"]); err != nil {
+			return err
+		}
+
 		_, txErr := s.connection().WithTransaction(func(tx *sql.Tx) (e error, i map[string]interface{}) {
 
 			_, updateRoleErr := role.WithTx(tx).Update(values.Get("name"), values.Get("slug"))
@@ -755,6 +981,19 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 				}
 			}
 
+			delParentErr := role.WithTx(tx).DeleteParents()
+
+			if db.CheckError(delParentErr, db.DELETE) {
+				return delParentErr, nil
+			}
+
+			for i := 0; i < len(values["parent_id[]"]); i++ {
+				_, addParentErr := role.WithTx(tx).AddParent(values["parent_id[]"][i])
+				if db.CheckError(addParentErr, db.INSERT) {
+					return addParentErr, nil
+				}
+			}
+
 			return nil, nil
 		})
 
@@ -781,6 +1020,13 @@ func (s *SystemTable) GetRolesTable(ctx *context.Context) (roleTable Table) {
 				}
 			}
 
+			for i := 0; i < len(values["parent_id[]"]); i++ {
+				_, addParentErr := role.WithTx(tx).AddParent(values["parent_id[]"][i])
+				if db.CheckError(addParentErr, db.INSERT) {
+					return addParentErr, nil
+				}
+			}
+
 			return nil, nil
 		})
 
@@ -822,11 +1068,6 @@ func (s *SystemTable) GetOpTable(ctx *context.Context) (opTable Table) {
 			SetTabTitle("Manager Detail").
 			GetContent()
 	}).FieldFilterable()
-	info.AddField(lg("path"), "path", db.Varchar).FieldFilterable()
-	info.AddField(lg("method"), "method", db.Varchar).FieldFilterable()
-	info.AddField(lg("ip"), "ip", db.Varchar).FieldFilterable()
-	info.AddField(lg("content"), "input", db.Text).FieldWidth(230)
-	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 
 	users, _ := s.table(config.GetAuthUserTable()).Select("id", "name").All()
 	options := make(types.FieldOptions, len(users))
@@ -834,7 +1075,26 @@ func (s *SystemTable) GetOpTable(ctx *context.Context) (opTable Table) {
 		options[k].Value = fmt.Sprintf("%v", user["id"])
 		options[k].Text = fmt.Sprintf("%v", user["name"])
 	}
+
+	// 以其他用戶身分登入時，執行操作的超級管理員
+	info.AddField(lg("impersonator"), "impersonator_id", db.Int).FieldDisplay(func(value types.FieldModel) interface{} {
+		if value.Value == "" || value.Value == "0" {
+			return "-"
+		}
+		for _, option := range options {
+			if option.Value == value.Value {
+				return `<span class="label label-danger">` + tmpl.HTMLEscapeString(option.Text) + `</span>`
+			}
+		}
+		return value.Value
+	}).FieldFilterable()
+	info.AddField(lg("path"), "path", db.Varchar).FieldFilterable()
+	info.AddField(lg("method"), "method", db.Varchar).FieldFilterable()
+	info.AddField(lg("ip"), "ip", db.Varchar).FieldFilterable()
+	info.AddField(lg("content"), "input", db.Text).FieldWidth(230)
+	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
 	info.AddSelectBox(language.Get("user"), options, action.FieldFilter("user_id"))
+	info.AddButton(tmpl.HTML(lg("audit log")), icon.History, action.Jump(config.Url("/info/audit_log")))
 	info.AddSelectBox(language.Get("method"), types.FieldOptions{
 		{Value: "GET", Text: "GET"},
 		{Value: "POST", Text: "POST"},
@@ -866,6 +1126,599 @@ func (s *SystemTable) GetOpTable(ctx *context.Context) (opTable Table) {
 	return
 }
 
+// GetAuditLogTable return the table of the change records of the table writes, filter by the
+// table and the record id to browse the history of a record.
+// 資料變更紀錄的列表，可依資料表及主鍵值篩選查看某筆資料的所有變更
+func (s *SystemTable) GetAuditLogTable(ctx *context.Context) (auditTable Table) {
+	auditTable = NewDefaultTable(Config{
+		Driver:     config.GetDatabases().GetDefault().Driver,
+		CanAdd:     false,
+		Editable:   false,
+		Deletable:  false,
+		Exportable: true,
+		Connection: "default",
+		PrimaryKey: PrimaryKey{
+			Type: db.Int,
+			Name: DefaultPrimaryKeyName,
+		},
+	})
+
+	info := auditTable.GetInfo().AddXssJsFilter().
+		HideDeleteButton().HideEditButton().HideNewButton()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField("userID", "user_id", db.Int).FieldHide()
+	info.AddField(lg("user"), "name", db.Varchar).FieldJoin(types.Join{
+		Table:     config.GetAuthUserTable(),
+		JoinField: "id",
+		Field:     "user_id",
+	})
+	info.AddField(lg("audit table"), "target_table", db.Varchar).FieldFilterable()
+	info.AddField(lg("record id"), "record_id", db.Varchar).FieldFilterable().
+		FieldDisplay(func(value types.FieldModel) interface{} {
+			// 點擊後篩選該筆資料的所有變更紀錄
+			return `<a href="` + tmpl.HTMLEscapeString(config.Url("/info/audit_log?target_table="+
+				url.QueryEscape(fmt.Sprintf("%v", value.Row["target_table"]))+"&record_id="+url.QueryEscape(value.Value))) +
+				`">` + tmpl.HTMLEscapeString(value.Value) + `</a>`
+		})
+	info.AddField(lg("operation"), "operation", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		return lg(value.Value)
+	}).FieldFilterable(types.FilterType{FormType: form.SelectSingle}).FieldFilterOptions(types.FieldOptions{
+		{Value: models.AuditInsert, Text: lg(models.AuditInsert)},
+		{Value: models.AuditUpdate, Text: lg(models.AuditUpdate)},
+		{Value: models.AuditDelete, Text: lg(models.AuditDelete)},
+		{Value: models.AuditRestore, Text: lg(models.AuditRestore)},
+		{Value: models.AuditPurge, Text: lg(models.AuditPurge)},
+		{Value: models.AuditImpersonate, Text: lg(models.AuditImpersonate)},
+		{Value: models.AuditExitImpersonate, Text: lg(models.AuditExitImpersonate)},
+	})
+	info.AddField(lg("changes"), "diff", db.Text).FieldDisplay(func(value types.FieldModel) interface{} {
+		var changes []models.AuditChange
+		if err := json.Unmarshal([]byte(value.Value), &changes); err != nil {
+			return ""
+		}
+		res := ""
+		for _, change := range changes {
+			res += "<b>" + tmpl.HTMLEscapeString(change.Field) + "</b>: " +
+				tmpl.HTMLEscapeString(auditValueString(change.Old)) + " &rarr; " +
+				tmpl.HTMLEscapeString(auditValueString(change.New)) + "<br>"
+		}
+		return res
+	}).FieldWidth(360)
+	info.AddField(lg("ip"), "ip", db.Varchar).FieldFilterable()
+	info.AddField(lg("createdAt"), "created_at", db.Timestamp).FieldSortable()
+
+	info.AddButton(tmpl.HTML(lg("operation log")), icon.List, action.Jump(config.Url("/info/op")))
+
+	info.SetTable("goadmin_audit_log").
+		SetTitle(lg("audit log")).
+		SetDescription(lg("audit log"))
+
+	detail := auditTable.GetDetail()
+
+	detail.AddField("ID", "id", db.Int)
+	detail.AddField(lg("audit table"), "target_table", db.Varchar)
+	detail.AddField(lg("record id"), "record_id", db.Varchar)
+	detail.AddField(lg("operation"), "operation", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		return lg(value.Value)
+	})
+	detail.AddField(lg("old values"), "old_values", db.Text)
+	detail.AddField(lg("new values"), "new_values", db.Text)
+	detail.AddField(lg("ip"), "ip", db.Varchar)
+	detail.AddField(lg("createdAt"), "created_at", db.Timestamp)
+
+	detail.SetTable("goadmin_audit_log").
+		SetTitle(lg("audit log")).
+		SetDescription(lg("audit log"))
+
+	formList := auditTable.GetForm().AddXssJsFilter()
+
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
+
+	formList.SetTable("goadmin_audit_log").
+		SetTitle(lg("audit log")).
+		SetDescription(lg("audit log"))
+
+	return
+}
+
+// 將變更紀錄的值轉換成字串，空值顯示為NULL
+func auditValueString(value interface{}) string {
+	if value == nil {
+		return "NULL"
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// GetChangeRequestTable return the table of the change requests of the tables which need
+// approval, the approver reviews the diff and approves or rejects it on the review page.
+// 需要審核的資料表所建立的變更申請列表，點擊審核後在審核頁面核准或駁回
+func (s *SystemTable) GetChangeRequestTable(ctx *context.Context) (changeTable Table) {
+	changeTable = NewDefaultTable(Config{
+		Driver:     config.GetDatabases().GetDefault().Driver,
+		CanAdd:     false,
+		Editable:   false,
+		Deletable:  false,
+		Exportable: true,
+		Connection: "default",
+		PrimaryKey: PrimaryKey{
+			Type: db.Int,
+			Name: DefaultPrimaryKeyName,
+		},
+	})
+
+	info := changeTable.GetInfo().AddXssJsFilter().
+		HideDeleteButton().HideEditButton().HideNewButton().HideDetailButton()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField("userID", "user_id", db.Int).FieldHide().FieldFilterable()
+	info.AddField(lg("applicant"), "name", db.Varchar).FieldJoin(types.Join{
+		Table:     config.GetAuthUserTable(),
+		JoinField: "id",
+		Field:     "user_id",
+	})
+	info.AddField(lg("audit table"), "target_table", db.Varchar).FieldFilterable()
+	info.AddField(lg("record id"), "record_id", db.Varchar).FieldFilterable()
+	info.AddField(lg("operation"), "operation", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		return lg(value.Value)
+	}).FieldFilterable(types.FilterType{FormType: form.SelectSingle}).FieldFilterOptions(types.FieldOptions{
+		{Value: models.AuditInsert, Text: lg(models.AuditInsert)},
+		{Value: models.AuditUpdate, Text: lg(models.AuditUpdate)},
+		{Value: models.AuditDelete, Text: lg(models.AuditDelete)},
+	})
+	info.AddField(lg("permission"), "permission", db.Varchar).FieldFilterable()
+	info.AddField(lg("status"), "status", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		typ := "warning"
+		switch value.Value {
+		case models.ChangeApproved:
+			typ = "success"
+		case models.ChangeRejected:
+			typ = "danger"
+		}
+		return label().SetType(typ).SetContent(tmpl.HTML(lg(value.Value))).GetContent()
+	}).FieldFilterable(types.FilterType{FormType: form.SelectSingle}).FieldFilterOptions(types.FieldOptions{
+		{Value: models.ChangePending, Text: lg(models.ChangePending)},
+		{Value: models.ChangeApproved, Text: lg(models.ChangeApproved)},
+		{Value: models.ChangeRejected, Text: lg(models.ChangeRejected)},
+	})
+	info.AddField(lg("comment"), "comment", db.Varchar)
+	info.AddField(lg("createdAt"), "created_at", db.Timestamp).FieldSortable()
+	info.AddField(lg("reviewed at"), "reviewed_at", db.Timestamp).FieldSortable()
+
+	info.AddActionButton(tmpl.HTML(lg("review")), action.Jump(config.Url("/change_requests/review?id={{.Id}}")))
+
+	info.SetTable("goadmin_change_requests").
+		SetTitle(lg("change requests")).
+		SetDescription(lg("change requests")).
+		SetSortField("created_at").
+		SetSortDesc()
+
+	formList := changeTable.GetForm().AddXssJsFilter()
+
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
+
+	formList.SetTable("goadmin_change_requests").
+		SetTitle(lg("change requests")).
+		SetDescription(lg("change requests"))
+
+	return
+}
+
+// GetLoginLockoutTable return the table of the login failure counters, the username or the ip
+// is unlocked by deleting the counter.
+// 登入失敗計數(帳號、ip)的列表，刪除或點擊解除鎖定即可解鎖
+func (s *SystemTable) GetLoginLockoutTable(ctx *context.Context) (lockoutTable Table) {
+	lockoutTable = NewDefaultTable(Config{
+		Driver:     config.GetDatabases().GetDefault().Driver,
+		CanAdd:     false,
+		Editable:   false,
+		Deletable:  true,
+		Exportable: true,
+		Connection: "default",
+		PrimaryKey: PrimaryKey{
+			Type: db.Int,
+			Name: DefaultPrimaryKeyName,
+		},
+	})
+
+	info := lockoutTable.GetInfo().AddXssJsFilter().
+		HideFilterArea().HideDetailButton().HideEditButton().HideNewButton()
+
+	displayTime := func(value types.FieldModel) interface{} {
+		unix, _ := strconv.ParseInt(value.Value, 10, 64)
+		if unix <= 0 {
+			return "-"
+		}
+		return time.Unix(unix, 0).Format("2006-01-02 15:04:05")
+	}
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField(lg("type"), "target_type", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		return lg(value.Value)
+	}).FieldFilterable(types.FilterType{FormType: form.SelectSingle}).FieldFilterOptions(types.FieldOptions{
+		{Value: models.LockoutTargetUsername, Text: lg(models.LockoutTargetUsername)},
+		{Value: models.LockoutTargetIP, Text: lg(models.LockoutTargetIP)},
+	})
+	info.AddField(lg("target"), "target", db.Varchar).FieldFilterable()
+	info.AddField(lg("failures"), "failures", db.Int).FieldSortable()
+	info.AddField(lg("lock times"), "lock_times", db.Int).FieldSortable()
+	info.AddField(lg("last failure at"), "last_failure_at", db.Int).FieldDisplay(displayTime).FieldSortable()
+	info.AddField(lg("locked until"), "locked_until", db.Int).FieldDisplay(func(value types.FieldModel) interface{} {
+		unix, _ := strconv.ParseInt(value.Value, 10, 64)
+		if unix <= time.Now().Unix() {
+			return "-"
+		}
+		return `<span class="label label-danger">` + time.Unix(unix, 0).Format("2006-01-02 15:04:05") + `</span>`
+	}).FieldSortable()
+
+	info.AddActionButton(template.HTML(lg("unlock")), action.Ajax("login_lockout_unlock",
+		func(ctx *context.Context) (success bool, msg string, data interface{}) {
+			lockout := models.LoginLockout().SetConn(s.conn).Find(ctx.FormValue("id"))
+			if err := lockout.Unlock(); db.CheckError(err, db.DELETE) {
+				return false, err.Error(), ""
+			}
+			return true, language.Get("unlock success"), ""
+		}).SetSuccessJS(`if (data.code === 0) {
+                                    swal(data.msg, '', 'success');
+                                    $.pjax.reload('#pjax-container');
+                                } else {
+                                    swal(data.msg, '', 'error');
+                                }`))
+
+	info.SetTable("goadmin_login_lockout").
+		SetTitle(lg("login lockout")).
+		SetDescription(lg("login lockout"))
+
+	formList := lockoutTable.GetForm().AddXssJsFilter()
+
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
+	formList.AddField(lg("type"), "target_type", db.Varchar, form.Text)
+	formList.AddField(lg("target"), "target", db.Varchar, form.Text)
+
+	formList.SetTable("goadmin_login_lockout").
+		SetTitle(lg("login lockout")).
+		SetDescription(lg("login lockout"))
+
+	return
+}
+
+// GetSessionTable return the table of the active sessions, only the sessions stored in the database are listed.
+// 登入中的session列表，可以撤銷單一session或用戶所有的session，只列出以資料庫儲存的session
+func (s *SystemTable) GetSessionTable(ctx *context.Context) (sessionTable Table) {
+	sessionTable = NewDefaultTable(Config{
+		Driver:     config.GetDatabases().GetDefault().Driver,
+		CanAdd:     false,
+		Editable:   false,
+		Deletable:  true,
+		Exportable: false,
+		Connection: "default",
+		PrimaryKey: PrimaryKey{
+			Type: db.Int,
+			Name: DefaultPrimaryKeyName,
+		},
+	})
+
+	info := sessionTable.GetInfo().AddXssJsFilter().
+		HideFilterArea().HideDetailButton().HideEditButton().HideNewButton()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField("userID", "user_id", db.Int).FieldHide().FieldFilterable()
+	info.AddField(lg("user"), "name", db.Varchar).FieldJoin(types.Join{
+		Table:     config.GetAuthUserTable(),
+		JoinField: "id",
+		Field:     "user_id",
+	})
+	info.AddField(lg("ip"), "ip", db.Varchar).FieldFilterable()
+	info.AddField(lg("user agent"), "user_agent", db.Varchar).FieldWidth(300)
+	info.AddField(lg("createdAt"), "created_at", db.Timestamp).FieldSortable()
+	info.AddField(lg("last seen"), "updated_at", db.Timestamp).FieldSortable()
+
+	// 目前請求的session
+	currentSid := auth.CurrentSid(ctx)
+	info.AddField(lg("current session"), "sid", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		if currentSid != "" && value.Value == currentSid {
+			return `<span class="label label-success">` + lg("current session") + `</span>`
+		}
+		return ""
+	})
+
+	info.AddActionButton(template.HTML(lg("revoke all sessions of the user")), action.Ajax("session_revoke_user",
+		func(ctx *context.Context) (success bool, msg string, data interface{}) {
+			res, err := s.table("goadmin_session").Select("user_id").Where("id", "=", ctx.FormValue("id")).First()
+			if err != nil || res == nil {
+				return false, language.Get("revoke fail"), ""
+			}
+			userId, _ := strconv.ParseInt(fmt.Sprintf("%v", res["user_id"]), 10, 64)
+			if err := auth.RevokeUserSessions(s.conn, userId, ""); err != nil {
+				return false, err.Error(), ""
+			}
+			return true, language.Get("revoke success"), ""
+		}).WithAlert(action.AlertData{
+		Title:              language.Get("are you sure to revoke all sessions of the user"),
+		Type:               "warning",
+		ShowCancelButton:   true,
+		ConfirmButtonColor: "#DD6B55",
+		ConfirmButtonText:  language.Get("yes"),
+		CloseOnConfirm:     false,
+		CancelButtonText:   language.Get("cancel"),
+	}).SetSuccessJS(`if (data.code === 0) {
+                                    swal(data.msg, '', 'success');
+                                    $.pjax.reload('#pjax-container');
+                                } else {
+                                    swal(data.msg, '', 'error');
+                                }`))
+
+	info.SetTable("goadmin_session").
+		SetTitle(lg("sessions")).
+		SetDescription(lg("sessions")).
+		SetSortField("updated_at").
+		SetSortDesc().
+		Where("goadmin_session.user_id", ">", 0)
+
+	formList := sessionTable.GetForm().AddXssJsFilter()
+
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
+
+	formList.SetTable("goadmin_session").
+		SetTitle(lg("sessions")).
+		SetDescription(lg("sessions"))
+
+	return
+}
+
+// GetAPIKeyTable return the table of the api keys of the service accounts, the key is only shown once
+// when it is created or rotated.
+// 服務帳號的api key列表，key只在新增或更換時顯示一次
+func (s *SystemTable) GetAPIKeyTable(ctx *context.Context) (apiKeyTable Table) {
+	apiKeyTable = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver))
+
+	info := apiKeyTable.GetInfo().AddXssJsFilter().HideFilterArea().HideDetailButton()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField(lg("name"), "name", db.Varchar).FieldFilterable()
+	info.AddField("userID", "user_id", db.Int).FieldHide()
+	info.AddField(lg("service account"), "name", db.Varchar).FieldJoin(types.Join{
+		Table:     config.GetAuthUserTable(),
+		JoinField: "id",
+		Field:     "user_id",
+	})
+	info.AddField(lg("api key"), "key_prefix", db.Varchar).FieldDisplay(func(value types.FieldModel) interface{} {
+		return `<code>` + tmpl.HTMLEscapeString(value.Value) + `_******</code>`
+	})
+	info.AddField(lg("permission"), "permissions", db.Text).FieldDisplay(func(value types.FieldModel) interface{} {
+		if value.Value == "" {
+			return "-"
+		}
+		return roleLabels(strings.Split(value.Value, ","), "success")
+	})
+	info.AddField(lg("expires at"), "expires_at", db.Timestamp).FieldDisplay(func(value types.FieldModel) interface{} {
+		if value.Value == "" {
+			return lg("never")
+		}
+		if (models.APIKeyModel{ExpiresAt: value.Value}).IsExpired() {
+			return `<span class="label label-danger">` + tmpl.HTMLEscapeString(value.Value) + `</span>`
+		}
+		return value.Value
+	}).FieldSortable()
+	info.AddField(lg("last used at"), "last_used_at", db.Timestamp).FieldSortable()
+	info.AddField(lg("last used ip"), "last_used_ip", db.Varchar)
+	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
+
+	info.AddActionButton(template.HTML(lg("rotate")), action.Ajax("api_key_rotate",
+		func(ctx *context.Context) (success bool, msg string, data interface{}) {
+			apiKey := models.APIKey().SetConn(s.conn).Find(ctx.FormValue("id"))
+			if apiKey.IsEmpty() {
+				return false, language.Get("rotate fail"), ""
+			}
+			key, prefix, hash := auth.GenerateAPIKey()
+			if _, err := apiKey.Rotate(prefix, hash); db.CheckError(err, db.UPDATE) {
+				return false, err.Error(), ""
+			}
+			return true, language.Get("the api key is only shown once"), key
+		}).WithAlert(action.AlertData{
+		Title:              language.Get("are you sure to rotate the api key"),
+		Type:               "warning",
+		ShowCancelButton:   true,
+		ConfirmButtonColor: "#DD6B55",
+		ConfirmButtonText:  language.Get("yes"),
+		CloseOnConfirm:     false,
+		CancelButtonText:   language.Get("cancel"),
+	}).SetSuccessJS(`if (data.code === 0) {
+                                    swal({title: data.msg, text: data.data, type: 'success'});
+                                    $.pjax.reload('#pjax-container');
+                                } else {
+                                    swal(data.msg, '', 'error');
+                                }`))
+
+	info.SetTable("goadmin_api_keys").
+		SetTitle(lg("api keys")).
+		SetDescription(lg("api keys"))
+
+	formList := apiKeyTable.GetForm().AddXssJsFilter()
+
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
+	formList.AddField(lg("name"), "name", db.Varchar, form.Text).FieldMust()
+	formList.AddField(lg("service account"), "user_id", db.Int, form.SelectSingle).
+		FieldOptionsFromTable(config.GetAuthUserTable(), "username", "id").
+		FieldMust()
+	formList.AddField(lg("permission"), "permissions", db.Text, form.Select).
+		FieldOptionsFromTable("goadmin_permissions", "slug", "slug").
+		FieldDisplay(func(model types.FieldModel) interface{} {
+			if model.Value == "" {
+				return []string{}
+			}
+			return strings.Split(model.Value, ",")
+		}).
+		FieldHelpMsg(template.HTML(lg("the api key only has the selected permissions")))
+	formList.AddField(lg("expires at"), "expires_at", db.Timestamp, form.Datetime).
+		FieldHelpMsg(template.HTML(lg("never expires if empty")))
+
+	// 新增時產生的key，只在新增後的頁面顯示一次
+	var createdKey string
+
+	apiKeyValues := func(values form2.Values) (string, int64, []string, error) {
+		if values.IsEmpty("name", "user_id") {
+			return "", 0, nil, errors.New("name or service account should not be empty")
+		}
+		userId, err := strconv.ParseInt(values.Get("user_id"), 10, 64)
+		if err != nil {
+			return "", 0, nil, errors.New("wrong service account")
+		}
+		permissions := make([]string, 0)
+		for _, slug := range values["permissions[]"] {
+			if slug != "" {
+				permissions = append(permissions, slug)
+			}
+		}
+		return values.Get("name"), userId, permissions, nil
+	}
+
+	formList.SetTable("goadmin_api_keys").
+		SetTitle(lg("api keys")).
+		SetDescription(lg("api keys")).
+		SetInsertFn(func(values form2.Values) error {
+			name, userId, permissions, err := apiKeyValues(values)
+			if err != nil {
+				return err
+			}
+			key, prefix, hash := auth.GenerateAPIKey()
+			if _, err := models.APIKey().SetConn(s.conn).
+				New(name, userId, prefix, hash, permissions, values.Get("expires_at")); db.CheckError(err, db.INSERT) {
+				return err
+			}
+			createdKey = key
+			return nil
+		}).
+		SetUpdateFn(func(values form2.Values) error {
+			name, userId, permissions, err := apiKeyValues(values)
+			if err != nil {
+				return err
+			}
+			_, err = models.APIKey().SetConn(s.conn).Find(values.Get("id")).
+				Update(name, userId, permissions, values.Get("expires_at"))
+			if db.CheckError(err, db.UPDATE) {
+				return err
+			}
+			return nil
+		}).
+		SetResponder(func(ctx *context.Context) {
+			infoUrl := config.Url("/info/api_keys")
+			if createdKey == "" {
+				ctx.HTML(http.StatusOK, fmt.Sprintf(`<script>location.href="%s"</script>`, infoUrl))
+				ctx.AddHeader(constant.PjaxUrlHeader, infoUrl)
+				return
+			}
+			page.SetPageContent(ctx, auth.Auth(ctx), func(ctx interface{}) (types.Panel, error) {
+				return types.Panel{
+					Content: template.Get(config.GetTheme()).Box().
+						WithHeadBorder().
+						SetHeader(template.HTML(lg("the api key is only shown once"))).
+						SetBody(template.HTML(`<pre>` + tmpl.HTMLEscapeString(createdKey) + `</pre><p>` +
+							lg("send the api key in the header") + ` <code>` + auth.APIKeyHeader + `</code></p>`)).
+						SetFooter(link(infoUrl, "back")).
+						GetContent(),
+					Title:       template.HTML(lg("api keys")),
+					Description: template.HTML(lg("api keys")),
+				}, nil
+			}, s.conn)
+			ctx.AddHeader(constant.PjaxUrlHeader, infoUrl)
+		})
+
+	return
+}
+
+func (s *SystemTable) GetRowScopeTable(ctx *context.Context) (rowScopeTable Table) {
+	rowScopeTable = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver))
+
+	info := rowScopeTable.GetInfo().AddXssJsFilter().HideFilterArea()
+
+	info.AddField("ID", "id", db.Int).FieldSortable()
+	info.AddField(lg("row scope table"), "prefix", db.Varchar).FieldFilterable().FieldSortable()
+	info.AddField(lg("role"), "name", db.Varchar).
+		FieldJoin(types.Join{
+			Table:     "goadmin_roles",
+			JoinField: "id",
+			Field:     "role_id",
+		})
+	info.AddField(lg("permission"), "name", db.Varchar).
+		FieldJoin(types.Join{
+			Table:     "goadmin_permissions",
+			JoinField: "id",
+			Field:     "permission_id",
+		})
+	info.AddField(lg("row scope field"), "field", db.Varchar)
+	info.AddField(lg("row scope operator"), "operator", db.Varchar)
+	info.AddField(lg("row scope value"), "val", db.Varchar)
+	info.AddField(lg("createdAt"), "created_at", db.Timestamp)
+	info.AddField(lg("updatedAt"), "updated_at", db.Timestamp)
+
+	info.SetTable("goadmin_row_scopes").
+		SetTitle(lg("row scope")).
+		SetDescription(lg("row scope"))
+
+	// 未選擇角色或權限時存入0
+	zeroIfEmpty := func(model types.PostFieldModel) interface{} {
+		if model.Value.Value() == "" {
+			return 0
+		}
+		return model.Value.Value()
+	}
+
+	operators := make(types.FieldOptions, 0)
+	for _, op := range []string{"=", "!=", ">", ">=", "<", "<=", "in", "not in", "like"} {
+		operators = append(operators, types.FieldOption{Value: op, Text: op})
+	}
+
+	formList := rowScopeTable.GetForm().AddXssJsFilter()
+
+	formList.AddField("ID", "id", db.Int, form.Default).FieldNotAllowEdit().FieldNotAllowAdd()
+	formList.AddField(lg("row scope table"), "prefix", db.Varchar, form.Text).
+		FieldHelpMsg(template.HTML(lg("the prefix of the table generator, for example: manager"))).FieldMust()
+	formList.AddField(lg("role"), "role_id", db.Int, form.SelectSingle).
+		FieldOptionsFromTable("goadmin_roles", "name", "id").
+		FieldPostFilterFn(zeroIfEmpty).
+		FieldHelpMsg(template.HTML(lg("choose a role or a permission")))
+	formList.AddField(lg("permission"), "permission_id", db.Int, form.SelectSingle).
+		FieldOptionsFromTable("goadmin_permissions", "name", "id").
+		FieldPostFilterFn(zeroIfEmpty)
+	formList.AddField(lg("row scope field"), "field", db.Varchar, form.Text).FieldMust()
+	formList.AddField(lg("row scope operator"), "operator", db.Varchar, form.SelectSingle).
+		FieldOptions(operators).FieldDefault("=").FieldMust()
+	formList.AddField(lg("row scope value"), "val", db.Varchar, form.Text).
+		FieldHelpMsg(template.HTML(lg("the values of in operator are separated by comma, variables: {user.id}, {user.username}, {user.name}, {user.roles}")))
+	formList.AddField(lg("updatedAt"), "updated_at", db.Timestamp, form.Default).FieldNotAllowAdd()
+	formList.AddField(lg("createdAt"), "created_at", db.Timestamp, form.Default).FieldNotAllowAdd()
+
+	formList.SetTable("goadmin_row_scopes").
+		SetTitle(lg("row scope")).
+		SetDescription(lg("row scope")).
+		SetPostValidator(func(values form2.Values) error {
+			hasRole := values.Get("role_id") != "" && values.Get("role_id") != "0"
+			hasPermission := values.Get("permission_id") != "" && values.Get("permission_id") != "0"
+			if hasRole == hasPermission {
+				return errors.New(lg("choose a role or a permission"))
+			}
+			if !rowScopeField.MatchString(values.Get("field")) {
+				return errors.New("invalid field")
+			}
+			if !rowScopeOperators[values.Get("operator")] {
+				return errors.New("invalid operator")
+			}
+			return nil
+		}).SetPostHook(func(values form2.Values) error {
+		if values.IsInsertPost() {
+			return nil
+		}
+		_, err := s.connection().Table("goadmin_row_scopes").
+			Where("id", "=", values.Get("id")).Update(dialect.H{
+			"updated_at": time.Now().Format("2006-01-02 15:04:05"),
+		})
+		return err
+	})
+
+	return
+}
+
 func (s *SystemTable) GetMenuTable(ctx *context.Context) (menuTable Table) {
 	menuTable = NewDefaultTable(DefaultConfigWithDriver(config.GetDatabases().GetDefault().Driver))
 
@@ -1680,6 +2533,15 @@ func lgWithConfigScore(v string, score ...string) string {
 	return language.GetWithScope(v, scores...)
 }
 
+// 將名稱以標籤顯示
+func roleLabels(names []string, typ string) tmpl.HTML {
+	labels := tmpl.HTML("")
+	for _, name := range names {
+		labels += label().SetType(typ).SetContent(tmpl.HTML(tmpl.HTMLEscapeString(name))).GetContent() + " "
+	}
+	return labels
+}
+
 func link(url, content string) tmpl.HTML {
 	return html.AEl().
 		SetAttr("href", url).
@@ -1724,6 +2586,29 @@ func interfaces(arr []string) []interface{} {
 	return iarr
 }
 
+// 判斷兩組id是否相同(不考慮順序及重複)
+func sameIds(ids []interface{}, arr []string) bool {
+	set := make(map[string]bool)
+	for _, id := range ids {
+		set[fmt.Sprintf("%v", id)] = true
+	}
+	other := make(map[string]bool)
+	for _, id := range arr {
+		if id != "" {
+			other[id] = true
+		}
+	}
+	if len(set) != len(other) {
+		return false
+	}
+	for id := range other {
+		if !set[id] {
+			return false
+		}
+	}
+	return true
+}
+
 func addSwitchForTool(formList *types.FormPanel, head, field, def string, row ...int) {
 	formList.AddField(lgWithScore(head, "tool"), field, db.Varchar, form.Switch).
 		FieldOptions(types.FieldOptions{
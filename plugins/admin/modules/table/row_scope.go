@@ -0,0 +1,346 @@
+package table
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+)
+
+// RowScopeRule is a condition of the row scope.
+// 行級資料權限的條件，例如region = 某地區
+type RowScopeRule struct {
+	Field    string
+	Operator string
+	Values   []string
+}
+
+// RowScope is the row level data permission of the table. The rules of a group are joined
+// by and, the groups are joined by or, nil means no restriction.
+// 每個group為一個角色或權限的條件(以and連接)，不同group之間以or連接
+type RowScope [][]RowScopeRule
+
+// RowScopeVariable return the values of the variable of the user, for example the regions of the user.
+type RowScopeVariable func(user models.UserModel) []string
+
+// 支援的運算子
+var rowScopeOperators = map[string]bool{
+	"=": true, "!=": true, ">": true, ">=": true, "<": true, "<=": true,
+	"in": true, "not in": true, "like": true,
+}
+
+var rowScopeField = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var (
+	rowScopeVariablesLock sync.RWMutex
+	rowScopeVariables     = map[string]RowScopeVariable{
+		"user.id": func(user models.UserModel) []string {
+			return []string{strconv.FormatInt(user.Id, 10)}
+		},
+		"user.username": func(user models.UserModel) []string {
+			return []string{user.UserName}
+		},
+		"user.name": func(user models.UserModel) []string {
+			return []string{user.Name}
+		},
+		"user.roles": func(user models.UserModel) []string {
+			slugs := make([]string, len(user.Roles))
+			for i, role := range user.Roles {
+				slugs[i] = role.Slug
+			}
+			return slugs
+		},
+	}
+)
+
+// RegisterRowScopeVariable register a variable which can be used as {name} in the value of the rule.
+// 註冊條件值可使用的變數，例如RegisterRowScopeVariable("user.region", ...)後條件值可設為{user.region}
+func RegisterRowScopeVariable(name string, fn RowScopeVariable) {
+	rowScopeVariablesLock.Lock()
+	defer rowScopeVariablesLock.Unlock()
+	rowScopeVariables[name] = fn
+}
+
+// RowScopeValues return the values of the rule for the user, the variable is replaced by the
+// values of the user.
+// 將條件值轉換成實際的值，變數替換成用戶的值，in/not in的固定值以逗號分隔
+func RowScopeValues(val, operator string, user models.UserModel) []string {
+	val = strings.TrimSpace(val)
+	if strings.HasPrefix(val, "{") && strings.HasSuffix(val, "}") {
+		rowScopeVariablesLock.RLock()
+		fn, ok := rowScopeVariables[val[1:len(val)-1]]
+		rowScopeVariablesLock.RUnlock()
+		if !ok {
+			return nil
+		}
+		return fn(user)
+	}
+	if operator == "in" || operator == "not in" {
+		values := strings.Split(val, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return values
+	}
+	return []string{val}
+}
+
+// GetRowScope return the row scope of the login user for the table generator of prefix.
+// The super administrator and the users whose roles and permissions have no rule of the table
+// are not restricted.
+// 回傳目前登入用戶在該資料表的行級資料權限，超級管理員或角色、權限都沒有設置條件時不限制
+func GetRowScope(ctx *context.Context, prefix string, conn db.Connection) RowScope {
+	user, ok := ctx.User().(models.UserModel)
+	if !ok || user.IsSuperAdmin() {
+		return nil
+	}
+
+	rules := models.RowScope().SetConn(conn).FindByPrefix(prefix)
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var (
+		owners  = make([]string, 0)
+		groups  = make(map[string][]RowScopeRule)
+		roleIds = make(map[int64]bool)
+	)
+
+	// 包含繼承的上層角色
+	for _, id := range user.SetConn(conn).GetAllRoleIdWithAncestors() {
+		if roleId, ok := id.(int64); ok {
+			roleIds[roleId] = true
+		}
+	}
+
+	for _, rule := range rules {
+		owner := ""
+		if rule.RoleId != 0 && roleIds[rule.RoleId] {
+			owner = "role:" + strconv.FormatInt(rule.RoleId, 10)
+		} else if rule.PermissionId != 0 && userHasPermission(user, rule.PermissionId) {
+			owner = "permission:" + strconv.FormatInt(rule.PermissionId, 10)
+		}
+		if owner == "" {
+			continue
+		}
+		if _, ok := groups[owner]; !ok {
+			owners = append(owners, owner)
+		}
+		groups[owner] = append(groups[owner], RowScopeRule{
+			Field:    rule.Field,
+			Operator: strings.ToLower(strings.TrimSpace(rule.Operator)),
+			Values:   RowScopeValues(rule.Val, strings.ToLower(strings.TrimSpace(rule.Operator)), user),
+		})
+	}
+
+	if len(owners) == 0 {
+		return nil
+	}
+
+	scope := make(RowScope, len(owners))
+	for i, owner := range owners {
+		scope[i] = groups[owner]
+	}
+	return scope
+}
+
+func userHasPermission(user models.UserModel, id int64) bool {
+	for _, permission := range user.Permissions {
+		if permission.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields return the valid fields used by the rules of the row scope.
+// 回傳條件中所有合法的欄位(不重複)
+func (scope RowScope) Fields() []string {
+	var (
+		fields = make([]string, 0)
+		seen   = make(map[string]bool)
+	)
+	for _, group := range scope {
+		for _, rule := range group {
+			if rowScopeField.MatchString(rule.Field) && !seen[rule.Field] {
+				seen[rule.Field] = true
+				fields = append(fields, rule.Field)
+			}
+		}
+	}
+	return fields
+}
+
+// Match check the row of the values is in the row scope, the field which is not in the values
+// does not match. It is used to check the values to be written.
+// 檢查資料(欄位名稱對應的值)是否在範圍內，用於檢查要寫入的資料，values中沒有的欄位視為不符合
+func (scope RowScope) Match(values map[string]string) bool {
+	if len(scope) == 0 {
+		return true
+	}
+
+	for _, group := range scope {
+		matched := true
+		for _, rule := range group {
+			if !rule.match(values) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule RowScopeRule) match(values map[string]string) bool {
+	operator := rule.operator()
+	if !rule.valid(operator) {
+		return false
+	}
+
+	value, ok := values[rule.Field]
+	if !ok {
+		return false
+	}
+
+	switch operator {
+	case "=", "in":
+		for _, v := range rule.Values {
+			if compareRowScopeValue(value, v) == 0 {
+				return true
+			}
+		}
+		return false
+	case "!=", "not in":
+		for _, v := range rule.Values {
+			if compareRowScopeValue(value, v) == 0 {
+				return false
+			}
+		}
+		return true
+	case ">":
+		return compareRowScopeValue(value, rule.Values[0]) > 0
+	case ">=":
+		return compareRowScopeValue(value, rule.Values[0]) >= 0
+	case "<":
+		return compareRowScopeValue(value, rule.Values[0]) < 0
+	case "<=":
+		return compareRowScopeValue(value, rule.Values[0]) <= 0
+	case "like":
+		return likeRowScopeValue(value, rule.Values[0])
+	}
+	return false
+}
+
+// 多個值的=、!=視為in、not in
+func (rule RowScopeRule) operator() string {
+	if len(rule.Values) > 1 {
+		if rule.Operator == "=" {
+			return "in"
+		} else if rule.Operator == "!=" {
+			return "not in"
+		}
+	}
+	return rule.Operator
+}
+
+// 欄位、運算子需合法且有值，多個值只能用於in、not in
+func (rule RowScopeRule) valid(operator string) bool {
+	return rowScopeField.MatchString(rule.Field) && rowScopeOperators[operator] && len(rule.Values) > 0 &&
+		(len(rule.Values) == 1 || operator == "in" || operator == "not in")
+}
+
+// 兩個值都是數字時以數值比較，否則以字串比較
+func compareRowScopeValue(a, b string) int {
+	x, errX := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errY := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if errX == nil && errY == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+// 以sql like的規則(%為任意字串，_為任意字元，不分大小寫)比對
+func likeRowScopeValue(value, pattern string) bool {
+	var expr strings.Builder
+	expr.WriteString("(?is)^")
+	for _, c := range pattern {
+		switch c {
+		case '%':
+			expr.WriteString(".*")
+		case '_':
+			expr.WriteString(".")
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	ok, _ := regexp.MatchString(expr.String(), value)
+	return ok
+}
+
+// 將資料庫取得的值轉換為字串
+func rowScopeString(v interface{}) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// Statement return the where statement and the arguments of the row scope, the fields are
+// prefixed by table. Invalid rule matches nothing.
+// 產生where語句，錯誤的條件(欄位、運算子或沒有值)視為不符合任何資料
+func (scope RowScope) Statement(table, delimiter string) (string, []interface{}) {
+	if len(scope) == 0 {
+		return "", nil
+	}
+
+	var (
+		groups = make([]string, len(scope))
+		args   = make([]interface{}, 0)
+	)
+
+	for i, group := range scope {
+		conds := make([]string, len(group))
+		for j, rule := range group {
+			var cond string
+			cond, args = rule.statement(table, delimiter, args)
+			conds[j] = cond
+		}
+		groups[i] = "(" + strings.Join(conds, " and ") + ")"
+	}
+
+	return "(" + strings.Join(groups, " or ") + ")", args
+}
+
+func (rule RowScopeRule) statement(table, delimiter string, args []interface{}) (string, []interface{}) {
+	operator := rule.operator()
+	if !rule.valid(operator) {
+		return "1 = 0", args
+	}
+
+	field := table + "." + modules.FilterField(rule.Field, delimiter)
+
+	if operator == "in" || operator == "not in" {
+		for _, value := range rule.Values {
+			args = append(args, value)
+		}
+		return field + " " + operator + " (" + strings.TrimSuffix(strings.Repeat("?,", len(rule.Values)), ",") + ")", args
+	}
+
+	return field + " " + operator + " ?", append(args, rule.Values[0])
+}
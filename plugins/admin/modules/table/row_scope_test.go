@@ -0,0 +1,116 @@
+package table
+
+import (
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRowScopeStatement(t *testing.T) {
+	var scope RowScope
+	raw, args := scope.Statement("orders", "`")
+	assert.Equal(t, raw, "")
+	assert.Equal(t, len(args), 0)
+
+	scope = RowScope{
+		{
+			{Field: "region", Operator: "=", Values: []string{"north"}},
+			{Field: "status", Operator: "in", Values: []string{"1", "2"}},
+		},
+		{
+			{Field: "owner_id", Operator: "=", Values: []string{"3", "4"}},
+		},
+	}
+	raw, args = scope.Statement("orders", "`")
+	assert.Equal(t, raw, "((orders.`region` = ? and orders.`status` in (?,?)) or (orders.`owner_id` in (?,?)))")
+	assert.Equal(t, args, []interface{}{"north", "1", "2", "3", "4"})
+
+	raw, _ = RowScope{{{Field: "region", Operator: "=", Values: []string{"north"}}}}.Statement("orders", "[")
+	assert.Equal(t, raw, "((orders.[region] = ?))")
+
+	// invalid rules match nothing
+	for _, rule := range []RowScopeRule{
+		{Field: "region`; drop table orders", Operator: "=", Values: []string{"north"}},
+		{Field: "region", Operator: "or 1 =", Values: []string{"1"}},
+		{Field: "region", Operator: "=", Values: nil},
+		{Field: "region", Operator: ">", Values: []string{"1", "2"}},
+	} {
+		raw, args = RowScope{{rule}}.Statement("orders", "`")
+		assert.Equal(t, raw, "((1 = 0))")
+		assert.Equal(t, len(args), 0)
+	}
+}
+
+func TestRowScopeValues(t *testing.T) {
+	user := models.UserModel{Id: 3, UserName: "jack", Name: "Jack",
+		Roles: []models.RoleModel{{Slug: "sales"}, {Slug: "operator"}}}
+
+	RegisterRowScopeVariable("user.region", func(user models.UserModel) []string {
+		if user.UserName == "jack" {
+			return []string{"north", "east"}
+		}
+		return nil
+	})
+
+	assert.Equal(t, RowScopeValues("{user.id}", "=", user), []string{"3"})
+	assert.Equal(t, RowScopeValues(" {user.username} ", "=", user), []string{"jack"})
+	assert.Equal(t, RowScopeValues("{user.roles}", "in", user), []string{"sales", "operator"})
+	assert.Equal(t, RowScopeValues("{user.region}", "=", user), []string{"north", "east"})
+	assert.Equal(t, len(RowScopeValues("{user.unknown}", "=", user)), 0)
+	assert.Equal(t, RowScopeValues("a, b", "in", user), []string{"a", "b"})
+	assert.Equal(t, RowScopeValues("a, b", "=", user), []string{"a, b"})
+}
+
+func TestRowScopeMatch(t *testing.T) {
+	var scope RowScope
+	assert.Equal(t, scope.Match(map[string]string{}), true)
+
+	scope = RowScope{
+		{
+			{Field: "region", Operator: "=", Values: []string{"north", "east"}},
+			{Field: "amount", Operator: "<=", Values: []string{"100"}},
+		},
+		{
+			{Field: "owner_id", Operator: "=", Values: []string{"3"}},
+		},
+	}
+	assert.Equal(t, scope.Fields(), []string{"region", "amount", "owner_id"})
+
+	assert.Equal(t, scope.Match(map[string]string{"region": "north", "amount": "99.5"}), true)
+	assert.Equal(t, scope.Match(map[string]string{"region": "north", "amount": "101"}), false)
+	assert.Equal(t, scope.Match(map[string]string{"region": "south", "amount": "1"}), false)
+	assert.Equal(t, scope.Match(map[string]string{"region": "south", "owner_id": "3"}), true)
+	assert.Equal(t, scope.Match(map[string]string{"owner_id": "3.0"}), true)
+	// the field which is not in the values does not match
+	assert.Equal(t, scope.Match(map[string]string{"region": "north"}), false)
+
+	like := RowScope{{{Field: "name", Operator: "like", Values: []string{"a_c%"}}}}
+	assert.Equal(t, like.Match(map[string]string{"name": "ABCdef"}), true)
+	assert.Equal(t, like.Match(map[string]string{"name": "a.c"}), true)
+	assert.Equal(t, like.Match(map[string]string{"name": "ac"}), false)
+
+	notIn := RowScope{{{Field: "status", Operator: "not in", Values: []string{"1", "2"}}}}
+	assert.Equal(t, notIn.Match(map[string]string{"status": "3"}), true)
+	assert.Equal(t, notIn.Match(map[string]string{"status": "2"}), false)
+
+	// invalid rules match nothing
+	invalid := RowScope{{{Field: "region", Operator: ">", Values: []string{"1", "2"}}}}
+	assert.Equal(t, invalid.Match(map[string]string{"region": "3"}), false)
+}
+
+func TestRowScopeStatementWithWhereOr(t *testing.T) {
+	services = service.List{db.DriverMysql: db.GetConnectionByDriver(db.DriverMysql)}
+
+	tb := NewDefaultTable(DefaultConfig()).(*DefaultTable)
+	tb.GetInfo().Where("status", "=", 1).WhereOr("status", "=", 2)
+	tb.SetRowScope(RowScope{{{Field: "region", Operator: "=", Values: []string{"north"}}}})
+
+	wheres, args := tb.Info.Wheres.Statement("", "`", []interface{}{}, []string{}, []string{"status"})
+	wheres, args = tb.rowScopeStatement("orders", wheres, args)
+	// the or of the wheres does not bypass the row scope
+	assert.Equal(t, wheres, "(`status` = ? or `status` = ?  ) and ((orders.`region` = ?)) ")
+	assert.Equal(t, args, []interface{}{1, 2, "north"})
+}
@@ -0,0 +1,260 @@
+package table
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/db/dialect"
+	errs "github.com/GoAdminGroup/go-admin/modules/errors"
+	"github.com/GoAdminGroup/go-admin/modules/logger"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/modules"
+)
+
+// TrashPurgeInterval is the interval of the scheduled purge of the recycle bin.
+// 回收站過期清理的執行間隔
+var TrashPurgeInterval = time.Hour
+
+var trashPurgeOnce sync.Once
+
+// 回傳軟刪除的條件，trash為true時為回收站中的資料(已刪除)，反之為未刪除的資料
+func (tb *DefaultTable) softDeleteCondition(table string, trash bool) string {
+	if tb.SoftDelete == "" || !tb.getDataFromDB() {
+		return ""
+	}
+	field := modules.FilterField(tb.SoftDelete, tb.delimiter())
+	if table != "" {
+		field = table + "." + field
+	}
+	if trash {
+		return field + " is not null"
+	}
+	return field + " is null"
+}
+
+// 將軟刪除的條件以and加入wheres
+func (tb *DefaultTable) softDeleteStatement(table, wheres string, trash bool) string {
+	cond := tb.softDeleteCondition(table, trash)
+	if cond == "" {
+		return wheres
+	}
+	return andWhere(wheres, cond)
+}
+
+// 回傳去除空值及重複值的ids
+func uniqueIds(ids []string) []interface{} {
+	var (
+		vals = make([]interface{}, 0, len(ids))
+		seen = make(map[string]bool)
+	)
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			vals = append(vals, id)
+		}
+	}
+	return vals
+}
+
+// checkSoftDeleted check the rows of ids are all in the recycle bin(trash is true) or all not deleted.
+// 檢查資料是否都在回收站中(trash為true)或都未被刪除，否則回傳錯誤
+func (tb *DefaultTable) checkSoftDeleted(table string, ids []string, trash bool) error {
+	cond := tb.softDeleteCondition("", trash)
+	if cond == "" || table == "" {
+		return nil
+	}
+	vals := uniqueIds(ids)
+	if len(vals) == 0 {
+		return nil
+	}
+	res, err := tb.sql().UsePrimary().Table(table).
+		Select(tb.PrimaryKey.Name).
+		WhereIn(tb.PrimaryKey.Name, vals).
+		WhereRaw(cond).
+		All()
+	if err != nil {
+		return err
+	}
+	if len(res) != len(vals) {
+		return errors.New(errs.WrongID)
+	}
+	return nil
+}
+
+// 將資料移至回收站，在軟刪除欄位記錄刪除時間
+func (tb *DefaultTable) softDelete(table string, ids []string) error {
+	vals := uniqueIds(ids)
+	if len(vals) == 0 {
+		return errors.New("delete error: wrong parameter")
+	}
+	_, err := tb.sql().Table(table).
+		WhereIn(tb.PrimaryKey.Name, vals).
+		WhereRaw(tb.softDeleteCondition("", false)).
+		Update(dialect.H{
+			tb.SoftDelete: time.Now().Format("2006-01-02 15:04:05"),
+		})
+	if db.CheckError(err, db.UPDATE) {
+		return err
+	}
+	return nil
+}
+
+// RestoreData restore the rows from the recycle bin.
+// 將回收站中的資料還原
+func (tb *DefaultTable) RestoreData(id string) error {
+	var (
+		table = tb.Info.Table
+		idArr = strings.Split(id, ",")
+	)
+
+	if tb.SoftDelete == "" || !tb.getDataFromDB() || table == "" {
+		return errors.New(errs.OperationNotAllow)
+	}
+
+	// 行級資料權限
+	if err := tb.checkRowScope(table, idArr); err != nil {
+		return err
+	}
+	if err := tb.checkSoftDeleted(table, idArr, true); err != nil {
+		return err
+	}
+
+	vals := uniqueIds(idArr)
+	if len(vals) == 0 {
+		return errors.New("restore error: wrong parameter")
+	}
+
+	before := tb.auditSnapshot(table, idArr)
+
+	_, err := tb.sql().Table(table).
+		WhereIn(tb.PrimaryKey.Name, vals).
+		WhereRaw(tb.softDeleteCondition("", true)).
+		Update(dialect.H{
+			tb.SoftDelete: nil,
+		})
+	if db.CheckError(err, db.UPDATE) {
+		return err
+	}
+
+	tb.audit(table, models.AuditRestore, before, tb.auditSnapshot(table, idArr))
+	return nil
+}
+
+// PurgeData permanently delete the rows in the recycle bin.
+// 永久刪除回收站中的資料
+func (tb *DefaultTable) PurgeData(id string) error {
+	var (
+		table = tb.Info.Table
+		idArr = strings.Split(id, ",")
+	)
+
+	if tb.SoftDelete == "" || !tb.getDataFromDB() || table == "" {
+		return errors.New(errs.OperationNotAllow)
+	}
+
+	// 行級資料權限
+	if err := tb.checkRowScope(table, idArr); err != nil {
+		return err
+	}
+	if err := tb.checkSoftDeleted(table, idArr, true); err != nil {
+		return err
+	}
+
+	return tb.purge(table, idArr)
+}
+
+func (tb *DefaultTable) purge(table string, ids []string) error {
+	vals := uniqueIds(ids)
+	if len(vals) == 0 {
+		return errors.New("purge error: wrong parameter")
+	}
+
+	before := tb.auditSnapshot(table, ids)
+
+	err := tb.sql().Table(table).
+		WhereIn(tb.PrimaryKey.Name, vals).
+		WhereRaw(tb.softDeleteCondition("", true)).
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	tb.audit(table, models.AuditPurge, before, nil)
+	return nil
+}
+
+// PurgeTrash permanently delete the rows which are in the recycle bin longer than the retention period.
+// 永久刪除在回收站中超過保留期限的資料，未設置保留期限則不處理
+func (tb *DefaultTable) PurgeTrash() error {
+	var table = tb.Info.Table
+
+	if tb.SoftDelete == "" || tb.TrashRetention <= 0 || !tb.getDataFromDB() || table == "" {
+		return nil
+	}
+
+	res, err := tb.sql().Table(table).
+		Select(tb.PrimaryKey.Name).
+		WhereRaw(modules.FilterField(tb.SoftDelete, tb.delimiter())+" < ?",
+			time.Now().Add(-tb.TrashRetention).Format("2006-01-02 15:04:05")).
+		All()
+	if err != nil {
+		return err
+	}
+	if len(res) == 0 {
+		return nil
+	}
+
+	ids := make([]string, len(res))
+	for i, row := range res {
+		ids[i] = fmt.Sprintf("%v", row[tb.PrimaryKey.Name])
+	}
+
+	return tb.purge(table, ids)
+}
+
+// PurgeTrash purge the expired rows in the recycle bin of all the tables.
+// 對所有資料表執行回收站的過期清理，資料表以沒有登入用戶的請求產生
+func PurgeTrash(list GeneratorList) {
+	for prefix, gen := range list {
+		func() {
+			defer func() {
+				if err := recover(); err != nil {
+					logger.Error("purge trash of ", prefix, " error: ", err)
+				}
+			}()
+			ctx := context.NewContext(&http.Request{
+				Method: "GET",
+				URL:    &url.URL{},
+				Header: make(http.Header),
+			})
+			trash := GetSoftDeleter(gen(ctx))
+			if trash == nil {
+				return
+			}
+			if err := trash.PurgeTrash(); err != nil {
+				logger.Error("purge trash of ", prefix, " error: ", err)
+			}
+		}()
+	}
+}
+
+// StartTrashPurge start the scheduled purge of the recycle bin, it only starts once.
+// 啟動回收站的定時清理，只會啟動一次
+func StartTrashPurge(list GeneratorList) {
+	trashPurgeOnce.Do(func() {
+		go func() {
+			ticker := time.NewTicker(TrashPurgeInterval)
+			defer ticker.Stop()
+			for range ticker.C {
+				PurgeTrash(list)
+			}
+		}()
+	})
+}
@@ -0,0 +1,44 @@
+package table
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSoftDeleteStatement(t *testing.T) {
+	services = service.List{db.DriverMysql: db.GetConnectionByDriver(db.DriverMysql)}
+
+	tb := NewDefaultTable(DefaultConfig()).(*DefaultTable)
+	assert.Equal(t, tb.GetSoftDelete(), "")
+	assert.Nil(t, GetSoftDeleter(tb))
+	assert.Equal(t, tb.softDeleteStatement("orders", "", false), "")
+
+	tb = NewDefaultTable(DefaultConfig().SetSoftDelete().SetTrashRetention(30 * 24 * time.Hour)).(*DefaultTable)
+	assert.Equal(t, tb.GetSoftDelete(), DefaultSoftDeleteField)
+	assert.Equal(t, GetSoftDeleter(tb), SoftDeleter(tb))
+	assert.Nil(t, GetSoftDeleter(customTable{Table: tb}))
+	assert.Equal(t, tb.softDeleteStatement("orders", "", false), "orders.`deleted_at` is null ")
+	assert.Equal(t, tb.softDeleteStatement("orders", "orders.`id` = ? ", true),
+		"(orders.`id` = ? ) and orders.`deleted_at` is not null ")
+	assert.Equal(t, tb.softDeleteCondition("", true), "`deleted_at` is not null")
+
+	// the or of the wheres does not bring back the deleted rows
+	tb.GetInfo().Where("status", "=", 1).WhereOr("status", "=", 2)
+	wheres, _ := tb.Info.Wheres.Statement("", "`", []interface{}{}, []string{}, []string{"status"})
+	assert.Equal(t, tb.softDeleteStatement("orders", wheres, false),
+		"(`status` = ? or `status` = ?  ) and orders.`deleted_at` is null ")
+
+	copied := tb.Copy().(*DefaultTable)
+	assert.Equal(t, copied.SoftDelete, DefaultSoftDeleteField)
+	assert.Equal(t, copied.TrashRetention, 30*24*time.Hour)
+
+	tb = NewDefaultTable(DefaultConfig().SetSoftDelete("removed_at")).(*DefaultTable)
+	assert.Equal(t, tb.softDeleteCondition("orders", false), "orders.`removed_at` is null")
+	assert.Equal(t, tb.PurgeTrash(), nil)
+
+	assert.Equal(t, uniqueIds([]string{"1", "", "2", "1"}), []interface{}{"1", "2"})
+}
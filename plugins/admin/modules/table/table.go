@@ -1,13 +1,16 @@
 package table
 
 import (
+	context2 "context"
 	"html/template"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/db"
 	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/GoAdminGroup/go-admin/plugins/admin/models"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/form"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/paginator"
 	"github.com/GoAdminGroup/go-admin/plugins/admin/modules/parameter"
@@ -75,6 +78,77 @@ type Table interface {
 	Copy() Table
 }
 
+// The optional interfaces of the Table. The tables implemented outside the package do not
+// have to implement them, the features are skipped for the tables which do not.
+// Table的選用介面，自定義的Table可以不實作，沒有實作時不套用對應的功能
+
+// RowScoper is the table which filters the rows with the row scope of the user.
+type RowScoper interface {
+	SetRowScope(scope RowScope)
+}
+
+// FieldPermissioner is the table which hides the fields the user has no permission of.
+type FieldPermissioner interface {
+	SetFieldPermission(fn FieldPermissionFn)
+}
+
+// OperatorSetter is the table which records the operator of the writes.
+type OperatorSetter interface {
+	SetOperator(op Operator)
+}
+
+// ContextSetter is the table which queries with the context of the request.
+type ContextSetter interface {
+	SetContext(ctx context2.Context)
+}
+
+// Exporter is the table which exports the data by chunks.
+type Exporter interface {
+	ExportData(params parameter.Parameters, fn func(info PanelInfo) error) error
+}
+
+// SoftDeleter is the table which moves the deleted rows to the recycle bin.
+type SoftDeleter interface {
+	GetSoftDelete() string
+	RestoreData(pk string) error
+	PurgeData(pk string) error
+	PurgeTrash() error
+}
+
+// GetSoftDeleter return the SoftDeleter of the table if the soft delete is enabled, or nil.
+// 資料表有實作SoftDeleter並啟用軟刪除時回傳SoftDeleter，否則回傳nil
+func GetSoftDeleter(t Table) SoftDeleter {
+	if s, ok := t.(SoftDeleter); ok && s.GetSoftDelete() != "" {
+		return s
+	}
+	return nil
+}
+
+// Approver is the table whose writes are submitted as change requests and written after approved.
+type Approver interface {
+	GetApproval() string
+	ApplyChange(change models.ChangeRequestModel, reviewerId int64, comment string) error
+}
+
+// SetRequest set the row scope, the field permission, the operator and the context of the
+// request to the table which implements the optional interfaces.
+// 設置目前登入用戶的行級資料權限、欄位權限、變更紀錄的操作者及查詢使用的context(請求中斷時停止查詢)
+func SetRequest(t Table, ctx *context.Context, prefix string, conn db.Connection) Table {
+	if s, ok := t.(RowScoper); ok {
+		s.SetRowScope(GetRowScope(ctx, prefix, conn))
+	}
+	if s, ok := t.(FieldPermissioner); ok {
+		s.SetFieldPermission(UserFieldPermission(ctx))
+	}
+	if s, ok := t.(OperatorSetter); ok {
+		s.SetOperator(CurrentOperator(ctx))
+	}
+	if s, ok := t.(ContextSetter); ok {
+		s.SetContext(ctx.Request.Context())
+	}
+	return t
+}
+
 type BaseTable struct {
 	Info           *types.InfoPanel
 	Form           *types.FormPanel
@@ -88,6 +162,11 @@ type BaseTable struct {
 	OnlyNewForm    bool
 	OnlyUpdateForm bool
 	PrimaryKey     PrimaryKey
+	SoftDelete     string
+	TrashRetention time.Duration
+	Approval       string
+	AuditRedact    []string
+	ExportLimit    int
 }
 
 // 將參數值設置至base.Info(InfoPanel(struct)).primaryKey中後回傳InfoPanel(struct)
@@ -128,6 +207,12 @@ func (base *BaseTable) GetDeletable() bool { return base.Deletable } // 回傳Ba
 
 func (base *BaseTable) GetExportable() bool { return base.Exportable } // 回傳BaseTable.Exportable(是否可以輸出)
 
+func (base *BaseTable) GetSoftDelete() string { return base.SoftDelete } // 回傳BaseTable.SoftDelete(軟刪除的欄位，空值表示不使用軟刪除)
+
+func (base *BaseTable) GetApproval() string { return base.Approval } // 回傳BaseTable.Approval(審核者需要的權限，空值表示不需要審核)
+
+func (base *BaseTable) GetAuditRedact() []string { return base.AuditRedact } // 回傳BaseTable.AuditRedact(變更紀錄中不記錄實際值的欄位)
+
 func (base *BaseTable) GetOnlyInfo() bool { return base.OnlyInfo } // 回傳BaseTable.OnlyInfo(是否唯一資訊)
 
 func (base *BaseTable) GetOnlyDetail() bool { return base.OnlyDetail } // 回傳BaseTable.OnlyDetail(是否取得detail)
@@ -156,6 +241,7 @@ type PanelInfo struct {
 	InfoList       types.InfoList           `json:"info_list"`
 	FilterFormData types.FormFields         `json:"filter_form_data"`
 	Paginator      types.PaginatorAttribute `json:"-"`
+	Total          int                      `json:"total"`
 	Title          string                   `json:"title"`
 	Description    string                   `json:"description"`
 }
@@ -174,8 +260,11 @@ type PrimaryKey struct {
 }
 
 const (
-	DefaultPrimaryKeyName = "id"
-	DefaultConnectionName = "default"
+	DefaultPrimaryKeyName  = "id"
+	DefaultConnectionName  = "default"
+	DefaultSoftDeleteField = "deleted_at"
+	// 匯出的excel檔在寫入前整個保留在記憶體中，因此限制匯出的筆數
+	DefaultExportLimit = 50000
 )
 
 var (
@@ -0,0 +1,37 @@
+package table
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GoAdminGroup/go-admin/context"
+	"github.com/GoAdminGroup/go-admin/modules/db"
+	"github.com/GoAdminGroup/go-admin/modules/service"
+	"github.com/stretchr/testify/assert"
+)
+
+// customTable is a table implemented outside the package which has only the methods of Table.
+type customTable struct {
+	Table
+}
+
+func TestSetRequest(t *testing.T) {
+	services = service.List{db.DriverMysql: db.GetConnectionByDriver(db.DriverMysql)}
+
+	ctx := context.NewContext(httptest.NewRequest("GET", "/admin/info/orders?__prefix=orders", nil))
+
+	tb := NewDefaultTable(DefaultConfig()).(*DefaultTable)
+	assert.Equal(t, SetRequest(tb, ctx, "orders", nil), Table(tb))
+	assert.Equal(t, tb.operator.Prefix, "orders")
+	assert.Equal(t, tb.ctx, ctx.Request.Context())
+
+	// the optional interfaces are skipped for the tables which do not implement them
+	custom := customTable{Table: NewDefaultTable(DefaultConfig())}
+	_, ok := Table(custom).(RowScoper)
+	assert.False(t, ok)
+	_, ok = Table(custom).(Approver)
+	assert.False(t, ok)
+	_, ok = Table(tb).(Approver)
+	assert.True(t, ok)
+	assert.Equal(t, SetRequest(custom, ctx, "orders", nil), Table(custom))
+}
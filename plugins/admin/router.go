@@ -30,6 +30,23 @@ func (admin *Admin) initRouter() *Admin {
 	// 對輸入的username、password身分驗證後取得user的role、permission及可用menu，最後更新資料表(goadmin_users)的密碼值(加密)
 	route.POST("/signin", admin.handler.Auth)
 
+	// 開啟兩步驟驗證的用戶在密碼驗證成功後輸入驗證碼(或綁定驗證器)
+	route.GET("/login/totp", admin.handler.ShowLoginTOTP)
+	route.POST("/login/totp", admin.handler.LoginTOTP)
+
+	// 密碼超過有效天數的用戶在登入時修改密碼
+	route.GET("/login/password", admin.handler.ShowLoginPassword)
+	route.POST("/login/password", admin.handler.LoginPassword)
+
+	// OpenID Connect單一登入，跳轉至provider及provider登入後的回調
+	route.GET("/oidc/:__provider/login", admin.handler.OIDCLogin)
+	route.GET("/oidc/:__provider/callback", admin.handler.OIDCCallback)
+
+	// 有設置auth_token_key時，可以透過帳號密碼取得api的bearer token
+	if auth.TokenEnabled() {
+		route.POST("/api/token", admin.handler.ApiToken).Name("api_token")
+	}
+
 	// auto install
 	// plugins\admin\controller\install.go
 	// 建立buffer(bytes.Buffer)並輸出HTML
@@ -66,6 +83,12 @@ func (admin *Admin) initRouter() *Admin {
 	// 登出並清除cookie後回到登入頁面
 	authRoute.GET("/logout", admin.handler.Logout)
 
+	// 用戶中心的兩步驟驗證設置，只能設置登入用戶自己的帳號
+	authRoute.GET("/totp", admin.handler.ShowTOTP).Name("totp")
+	authRoute.POST("/totp/enable", admin.handler.EnableTOTP).Name("totp_enable")
+	authRoute.POST("/totp/disable", admin.handler.DisableTOTP).Name("totp_disable")
+	authRoute.POST("/totp/recovery_codes", admin.handler.RegenerateTOTPRecoveryCodes).Name("totp_recovery_codes")
+
 	// menus
 	// 需要有參數id = ?
 	// MenuDelete查詢url中參數id的值後將id設置至MenuDeleteParam(struct)，接著將值設置至Context.UserValue[delete_menu_param]中，最後執行迴圈Context.handlers[ctx.index](ctx)
@@ -93,6 +116,19 @@ func (admin *Admin) initRouter() *Admin {
 	//分別處理上下半部表單的HTML語法，最後結合並輸出HTML
 	authRoute.GET("/menu", admin.handler.ShowMenu).Name("menu")
 
+	// 測試權限規則，回傳被允許或拒絕的路由
+	authRoute.POST("/permission/test", admin.handler.TestPermission).Name("permission_test")
+
+	// 超級管理員以其他用戶身分登入及結束
+	authRoute.POST("/impersonate", admin.handler.Impersonate).Name("impersonate")
+	authRoute.POST("/impersonate/exit", admin.handler.ExitImpersonate).Name("impersonate_exit")
+
+	// 變更申請的審核(需要有申請的審核權限)，以及導航列通知的待審核數量
+	authRoute.GET("/change_requests/review", admin.handler.ShowChangeRequest).Name("change_request_review")
+	authRoute.POST("/change_requests/approve", admin.handler.ApproveChangeRequest).Name("change_request_approve")
+	authRoute.POST("/change_requests/reject", admin.handler.RejectChangeRequest).Name("change_request_reject")
+	authRoute.GET("/change_requests/pending", admin.handler.PendingChangeRequests).Name("change_request_pending")
+
 	// 先檢查設置的參數(id = ?)是否符合條件，接著透過id取得goadmin_menu資料表中的資料，然後設置值至FormInfo(struct)中
 	// 最後以FormInfo(struct)匯出編輯介面的HTML語法
 	authRoute.GET("/menu/edit/show", admin.handler.ShowEditMenu).Name("menu_edit_show")
@@ -135,6 +171,10 @@ func (admin *Admin) initRouter() *Admin {
 	// 透過id刪除資料後回傳code、data(token)、msg
 	authPrefixRoute.POST("/delete/:__prefix", admin.guardian.Delete, admin.handler.Delete).Name("delete")
 
+	// 回收站：還原或永久刪除已軟刪除的資料
+	authPrefixRoute.POST("/restore/:__prefix", admin.guardian.Delete, admin.handler.Restore).Name("restore")
+	authPrefixRoute.POST("/purge/:__prefix", admin.guardian.Delete, admin.handler.Purge).Name("purge")
+
 	// 建立一個excel檔接著取得所有匯出的資料，最後將值加入至excel中
 	authPrefixRoute.POST("/export/:__prefix", admin.guardian.Export, admin.handler.Export).Name("export")
 
@@ -146,26 +186,37 @@ func (admin *Admin) initRouter() *Admin {
 
 	route.ANY("/operation/:__goadmin_op_id", auth.Middleware(admin.Conn), admin.handler.Operation)
 
-	// false
 	// 取得open的api
-	// if config.GetOpenAdminApi() {
-	// 	// crud json apis
-	// 	apiRoute := route.Group("/api", auth.Middleware(admin.Conn), admin.guardian.CheckPrefix)
-	// 	apiRoute.GET("/list/:__prefix", admin.handler.ApiList).Name("api_info")
-	// 	apiRoute.GET("/detail/:__prefix", admin.handler.ApiDetail).Name("api_detail")
-	// 	apiRoute.POST("/delete/:__prefix", admin.guardian.Delete, admin.handler.Delete).Name("api_delete")
-	// 	apiRoute.POST("/edit/:__prefix", admin.guardian.EditForm, admin.handler.ApiUpdate).Name("api_edit")
-	// 	apiRoute.GET("/edit/form/:__prefix", admin.guardian.ShowForm, admin.handler.ApiUpdateForm).Name("api_show_edit")
-	// 	apiRoute.POST("/create/:__prefix", admin.guardian.NewForm, admin.handler.ApiCreate).Name("api_new")
-	// 	apiRoute.GET("/create/form/:__prefix", admin.guardian.ShowNewForm, admin.handler.ApiCreateForm).Name("api_show_new")
-	// 	apiRoute.POST("/export/:__prefix", admin.guardian.Export, admin.handler.Export).Name("api_export")
-	// 	apiRoute.POST("/update/:__prefix", admin.guardian.Update, admin.handler.Update).Name("api_update")
-	// }
+	if config.GetOpenAdminApi() {
+		// crud json apis
+		// api的請求一律回傳json格式(包含驗證失敗、權限不足等錯誤)
+		apiRoute := route.Group("/api", jsonApi, auth.Middleware(admin.Conn), admin.guardian.CheckPrefix)
+		apiRoute.GET("/list/:__prefix", admin.handler.ApiList).Name("api_info")
+		apiRoute.GET("/detail/:__prefix", admin.handler.ApiDetail).Name("api_detail")
+		apiRoute.POST("/delete/:__prefix", admin.guardian.Delete, admin.handler.Delete).Name("api_delete")
+		apiRoute.POST("/restore/:__prefix", admin.guardian.Delete, admin.handler.Restore).Name("api_restore")
+		apiRoute.POST("/purge/:__prefix", admin.guardian.Delete, admin.handler.Purge).Name("api_purge")
+		apiRoute.POST("/edit/:__prefix", admin.guardian.EditForm, admin.handler.ApiUpdate).Name("api_edit")
+		apiRoute.GET("/edit/form/:__prefix", admin.guardian.ShowForm, admin.handler.ApiUpdateForm).Name("api_show_edit")
+		apiRoute.POST("/create/:__prefix", admin.guardian.NewForm, admin.handler.ApiCreate).Name("api_new")
+		apiRoute.GET("/create/form/:__prefix", admin.guardian.ShowNewForm, admin.handler.ApiCreateForm).Name("api_show_new")
+		apiRoute.POST("/export/:__prefix", admin.guardian.Export, admin.handler.Export).Name("api_export")
+		apiRoute.POST("/update/:__prefix", admin.guardian.Update, admin.handler.Update).Name("api_update")
+	}
 
 	admin.App = app
 	return admin
 }
 
+// jsonApi set the accept header of the api request to json.
+// 如果請求的header中accept沒有包含json則設置為application/json，後續的錯誤處理皆會回傳json
+func jsonApi(ctx *context.Context) {
+	if !ctx.WantJSON() {
+		ctx.Request.Header.Set("Accept", "application/json")
+	}
+	ctx.Next()
+}
+
 // globalErrorHandler(錯誤處理程序)
 // 判斷是否將站點關閉後執行迴圈Context.handlers[ctx.index](ctx)
 // 最後印出訪問訊息在終端機上並記錄所有操作行為至資料表(goadmin_operation_log)中
@@ -35,6 +35,7 @@ const loginTmpl = `{{define "login_theme1"}}
                 <form action="##" onsubmit="return false" method="post" id="sign-up-form" class="fh5co-form animate-box"
                       data-animate-effect="fadeIn">
                     <h2>{{.Title}}</h2>
+                    {{if not .SSOOnly}}
                     <div class="form-group">
                         <label for="username" class="sr-only">Username</label>
                         <input type="text" class="form-control" id="username" placeholder="{{lang "username"}}"
@@ -48,6 +49,12 @@ const loginTmpl = `{{define "login_theme1"}}
                     <div class="form-group">
                         <button class="btn btn-primary" onclick="submitData()">{{lang "login"}}</button>
                     </div>
+                    {{end}}
+                    {{range .SSOProviders}}
+                    <div class="form-group">
+                        <a class="btn btn-default btn-block" href="{{.Url}}">{{.Title}}</a>
+                    </div>
+                    {{end}}
                 </form>
             </div>
         </div>
@@ -81,7 +88,11 @@ const loginTmpl = `{{define "login_theme1"}}
                     location.href = data.data.url
                 },
                 error: function (data) {
-                    alert('{{lang "login fail"}}');
+                    if (data.status === 429 && data.responseJSON) {
+                        alert(data.responseJSON.msg);
+                    } else {
+                        alert('{{lang "login fail"}}');
+                    }
                 }
             });
         }
@@ -216,6 +216,8 @@ const (
 	NavBtnSiteName = "go_admin_site_navbtn"
 	NavBtnInfoName = "go_admin_info_navbtn"
 	NavBtnToolName = "go_admin_tool_navbtn"
+
+	NavBtnApprovalName = "go_admin_approval_navbtn"
 )
 
 func (b Buttons) RemoveSiteNavButton() Buttons {
@@ -230,6 +232,10 @@ func (b Buttons) RemoveToolNavButton() Buttons {
 	return b.RemoveButtonByName(NavBtnToolName)
 }
 
+func (b Buttons) RemoveApprovalNavButton() Buttons {
+	return b.RemoveButtonByName(NavBtnApprovalName)
+}
+
 type NavButton struct {
 	*BaseButton
 	Icon string
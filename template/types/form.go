@@ -151,6 +151,8 @@ type FormField struct {
 	Must        bool `json:"must"`
 	Hide        bool `json:"hide"`
 
+	Permissions []string `json:"permissions"`
+
 	Width int `json:"width"`
 
 	InputWidth int `json:"input_width"`
@@ -572,6 +574,14 @@ func (f *FormPanel) FieldHide() *FormPanel {
 	return f
 }
 
+// FieldPermission set the permissions(slug) required to edit the field, the field is read-only
+// and the submitted value is rejected when the user has none of them.
+// 設置編輯欄位需要的權限，用戶沒有任何一個權限時欄位為唯讀，且不接受提交該欄位
+func (f *FormPanel) FieldPermission(slugs ...string) *FormPanel {
+	f.FieldList[f.curFieldListIndex].Permissions = append(f.FieldList[f.curFieldListIndex].Permissions, slugs...)
+	return f
+}
+
 func (f *FormPanel) FieldPlaceholder(placeholder string) *FormPanel {
 	f.FieldList[f.curFieldListIndex].Placeholder = placeholder
 	return f
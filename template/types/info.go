@@ -148,6 +148,9 @@ type Field struct {
 	Filterable bool
 	Hide       bool
 
+	// Permissions 查看該欄位需要的權限(slug)，符合其中一個即可
+	Permissions []string
+
 	EditType    table.Type
 	EditOptions FieldOptions
 
@@ -1327,6 +1330,14 @@ func (i *InfoPanel) FieldHide() *InfoPanel {
 	return i
 }
 
+// FieldPermission set the permissions(slug) required to see the field, the field is removed from
+// the list, detail and export when the user has none of them.
+// 設置查看欄位需要的權限，用戶沒有任何一個權限時不顯示該欄位
+func (i *InfoPanel) FieldPermission(slugs ...string) *InfoPanel {
+	i.FieldList[i.curFieldListIndex].Permissions = append(i.FieldList[i.curFieldListIndex].Permissions, slugs...)
+	return i
+}
+
 func (i *InfoPanel) FieldJoin(join Join) *InfoPanel {
 	i.FieldList[i.curFieldListIndex].Joins = append(i.FieldList[i.curFieldListIndex].Joins, join)
 	return i
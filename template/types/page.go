@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"github.com/GoAdminGroup/go-admin/context"
 	"github.com/GoAdminGroup/go-admin/modules/config"
+	"github.com/GoAdminGroup/go-admin/modules/language"
 	"github.com/GoAdminGroup/go-admin/modules/menu"
 	"github.com/GoAdminGroup/go-admin/modules/system"
 	"github.com/GoAdminGroup/go-admin/modules/utils"
@@ -128,7 +129,7 @@ func NewPage(param NewPageParam) *Page {
 		IndexUrl:       config.GetIndexURL(),
 		CdnUrl:         config.GetAssetUrl(),
 		CustomHeadHtml: config.GetCustomHeadHtml(),
-		CustomFootHtml: config.GetCustomFootHtml() + btnJS,
+		CustomFootHtml: config.GetCustomFootHtml() + btnJS + impersonateBanner(param.User),
 		FooterInfo:     config.GetFooterInfo(),
 		AssetsList:     param.Assets,
 		navButtons:     param.Buttons,
@@ -139,6 +140,19 @@ func NewPage(param NewPageParam) *Page {
 	}
 }
 
+// 超級管理員以其他用戶身分登入時，在頁面上方顯示提示及結束的按鈕
+func impersonateBanner(user models.UserModel) template.HTML {
+	if !user.IsImpersonated() {
+		return ""
+	}
+	return template.HTML(`<div class="impersonate-banner" style="position:fixed;top:0;left:50%;z-index:9999;` +
+		`transform:translateX(-50%);padding:6px 12px;background:#dd4b39;color:#fff;border-radius:0 0 4px 4px;">` +
+		`<form method="post" action="` + config.Url("/impersonate/exit") + `" style="margin:0;">` +
+		template.HTMLEscapeString(fmt.Sprintf(language.Get("%s is logged in as %s"), user.ImpersonatorName, user.Name)) +
+		` <button type="submit" class="btn btn-xs btn-default" style="margin-left:8px;">` +
+		language.Get("exit impersonation") + `</button></form></div>`)
+}
+
 func (page *Page) AddButton(title template.HTML, icon string, action Action) *Page {
 	page.navButtons = append(page.navButtons, GetNavButton(title, icon, action))
 	page.CustomFootHtml += action.FooterContent()
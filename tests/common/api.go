@@ -21,6 +21,38 @@ func apiTest(e *httpexpect.Expect, sesID *http.Cookie) {
 		Expect().
 		Status(200).JSON().Object().ValueEqual("code", 200)
 
+	printlnWithColor("show with pagination", "green")
+	e.GET(config.Url("/api/list/manager")).
+		WithHeader("Accept", "application/json, text/plain, */*").
+		WithQuery("__page", "1").
+		WithQuery("__pageSize", "10").
+		WithCookie(sesID.Name, sesID.Value).
+		Expect().
+		Status(200).JSON().Object().Value("data").Object().
+		ContainsKey("data").ContainsKey("paginator").ContainsKey("filters")
+
+	printlnWithColor("show with wrong sort type", "green")
+	e.GET(config.Url("/api/list/manager")).
+		WithHeader("Accept", "application/json, text/plain, */*").
+		WithQuery("__sort_type", "up").
+		WithCookie(sesID.Name, sesID.Value).
+		Expect().
+		Status(400).JSON().Object().Value("errors").Object().ContainsKey("__sort_type")
+
+	printlnWithColor("show without login", "green")
+	e.GET(config.Url("/api/list/manager")).
+		WithHeader("Accept", "application/json, text/plain, */*").
+		Expect().
+		Status(401).JSON().Object().ValueEqual("code", 401)
+
+	printlnWithColor("detail", "green")
+	e.GET(config.Url("/api/detail/manager")).
+		WithHeader("Accept", "application/json, text/plain, */*").
+		WithQuery(constant.DetailPKKey, "1").
+		WithCookie(sesID.Name, sesID.Value).
+		Expect().
+		Status(200).JSON().Object().Value("data").Object().Value("data").Object().ContainsKey("id")
+
 	printlnWithColor("update form without id", "green")
 	e.GET(config.Url("/api/edit/form/manager")).
 		WithHeader("Accept", "application/json, text/plain, */*").
@@ -66,6 +66,7 @@ GO
 CREATE TABLE [dbo].[goadmin_operation_log] (
 	[id] int IDENTITY(1,1) NOT NULL,
 	[user_id] int NOT NULL,
+	[impersonator_id] int NOT NULL DEFAULT 0,
 	[path] varchar(255) COLLATE SQL_Latin1_General_CP1_CI_AS NOT NULL,
 	[method] varchar(10) COLLATE SQL_Latin1_General_CP1_CI_AS NOT NULL,
 	[ip] varchar(15) COLLATE SQL_Latin1_General_CP1_CI_AS NOT NULL,
@@ -722,6 +723,9 @@ CREATE TABLE [dbo].[goadmin_session] (
 	[id] int IDENTITY(1,1) NOT NULL,
 	[sid] varchar(50) COLLATE SQL_Latin1_General_CP1_CI_AS NULL DEFAULT '',
 	[values] varchar(3000) COLLATE SQL_Latin1_General_CP1_CI_AS NULL DEFAULT '',
+	[user_id] int NOT NULL DEFAULT 0,
+	[ip] varchar(50) COLLATE SQL_Latin1_General_CP1_CI_AS NOT NULL DEFAULT '',
+	[user_agent] varchar(500) COLLATE SQL_Latin1_General_CP1_CI_AS NOT NULL DEFAULT '',
 	[created_at] datetime NULL DEFAULT (getdate()),
 	[updated_at] datetime NULL DEFAULT (getdate())
 )
@@ -88,6 +88,7 @@ ALTER TABLE public.goadmin_operation_log_myid_seq OWNER TO postgres;
 CREATE TABLE public.goadmin_operation_log (
     id integer DEFAULT nextval('public.goadmin_operation_log_myid_seq'::regclass) NOT NULL,
     user_id integer NOT NULL,
+    impersonator_id integer DEFAULT 0 NOT NULL,
     path character varying(255) NOT NULL,
     method character varying(10) NOT NULL,
     ip character varying(15) NOT NULL,
@@ -255,6 +256,9 @@ CREATE TABLE public.goadmin_session (
     id integer DEFAULT nextval('public.goadmin_session_myid_seq'::regclass) NOT NULL,
     sid character varying(50) NOT NULL,
     "values" character varying(3000) NOT NULL,
+    user_id integer DEFAULT 0 NOT NULL,
+    ip character varying(50) DEFAULT ''::character varying NOT NULL,
+    user_agent character varying(500) DEFAULT ''::character varying NOT NULL,
     created_at timestamp without time zone DEFAULT now(),
     updated_at timestamp without time zone DEFAULT now()
 );